@@ -0,0 +1,129 @@
+package semver
+
+import (
+	"math"
+)
+
+// NextAbove returns the lowest release higher than v that the constraints
+// admit. Solvers can use it to probe the boundaries of a range and test
+// generators to produce versions just inside one. Prereleases are not
+// considered, as between any two versions there is no highest prerelease and
+// so no answer to the reverse question. The bool is false when no higher
+// release is admitted.
+func (cs Constraints) NextAbove(v *Version) (*Version, bool) {
+	var next *Version
+	for _, c := range cs.adjacentCandidates(v) {
+		if c.GreaterThan(v) && (next == nil || c.LessThan(next)) && cs.Check(c) {
+			next = c
+		}
+	}
+
+	return next, next != nil
+}
+
+// PrevBelow returns the highest release lower than v that the constraints
+// admit. Like NextAbove it only considers releases, so the highest release
+// below <2.0.0 is 1.18446744073709551615.18446744073709551615. The bool is
+// false when no lower release is admitted.
+func (cs Constraints) PrevBelow(v *Version) (*Version, bool) {
+	var prev *Version
+	for _, c := range cs.adjacentCandidates(v) {
+		if c.LessThan(v) && (prev == nil || c.GreaterThan(prev)) && cs.Check(c) {
+			prev = c
+		}
+	}
+
+	return prev, prev != nil
+}
+
+// adjacentCandidates returns the releases at which the constraints can start
+// or stop admitting versions, along with the releases on either side of v.
+// The admitted releases form ranges whose ends are all among them, so the
+// nearest admitted release to v in either direction is one of them. ^0.0.3
+// also admits 0.y.3 for every y (see Bound.SamePatch), so for such terms the
+// releases with its patch in the minor lines around each candidate are added.
+func (cs Constraints) adjacentCandidates(v *Version) []*Version {
+	var out []*Version
+	add := func(r [3]uint64, ok bool) {
+		if ok {
+			out = append(out, newRelease(r))
+		}
+	}
+
+	r := [3]uint64{v.major, v.minor, v.patch}
+	if v.pre != "" {
+		// The release of a prerelease is higher than it.
+		add(r, true)
+	} else {
+		add(releaseSucc(r))
+	}
+	add(releasePred(r))
+
+	for _, o := range cs.constraints {
+		for _, c := range o {
+			w := [3]uint64{c.con.major, c.con.minor, c.con.patch}
+			add(w, true)
+			add(releaseSucc(w))
+			add(releasePred(w))
+
+			// The ends of the minor and major lines the version is in,
+			// such as for ~1.2.3 or 1.x.
+			add([3]uint64{w[0], w[1], math.MaxUint64}, true)
+			add(releaseSucc([3]uint64{w[0], w[1], math.MaxUint64}))
+			add([3]uint64{w[0], math.MaxUint64, math.MaxUint64}, true)
+			add(releaseSucc([3]uint64{w[0], math.MaxUint64, math.MaxUint64}))
+		}
+	}
+
+	var carets []*Version
+	for _, o := range cs.constraints {
+		for _, c := range o {
+			b := c.bound()
+			if !b.SamePatch {
+				continue
+			}
+			for _, r := range out {
+				if r.major != 0 {
+					continue
+				}
+				for _, y := range []uint64{r.minor - 1, r.minor, r.minor + 1} {
+					carets = append(carets, newRelease([3]uint64{0, y, b.Min.patch}))
+				}
+			}
+		}
+	}
+
+	return append(out, carets...)
+}
+
+// releaseSucc returns the release directly after r. The bool is false when r
+// is the highest release.
+func releaseSucc(r [3]uint64) ([3]uint64, bool) {
+	for i := 2; i >= 0; i-- {
+		if r[i] < math.MaxUint64 {
+			r[i]++
+			return r, true
+		}
+		r[i] = 0
+	}
+	return r, false
+}
+
+// releasePred returns the release directly before r. The bool is false when
+// r is 0.0.0.
+func releasePred(r [3]uint64) ([3]uint64, bool) {
+	for i := 2; i >= 0; i-- {
+		if r[i] > 0 {
+			r[i]--
+			return r, true
+		}
+		r[i] = math.MaxUint64
+	}
+	return r, false
+}
+
+func newRelease(r [3]uint64) *Version {
+	v := &Version{major: r[0], minor: r[1], patch: r[2]}
+	v.original = v.String()
+	return v
+}
@@ -0,0 +1,149 @@
+package semver
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestNextAbove(t *testing.T) {
+	tests := []struct {
+		constraint string
+		version    string
+		expected   string
+	}{
+		{">=1.2.3", "1.0.0", "1.2.3"},
+		{">=1.2.3", "1.2.3", "1.2.4"},
+		{">1.2.3", "1.0.0", "1.2.4"},
+		{">1.2", "1.0.0", "1.3.0"},
+		{"^1.2.3", "1.9.9", "1.9.10"},
+		{"~1.2.3 || ^3", "1.2.18446744073709551615", "3.0.0"},
+		{"!=1.2.3", "1.2.2", "1.2.4"},
+		{"!=1.2.x", "1.1.9", "1.1.10"},
+		{"!=1.2.x", "1.1.18446744073709551615", "1.3.0"},
+		{">=1.0.0, !=1.2.3, !=1.2.4", "1.2.2", "1.2.5"},
+		{">=1.2.3-rc.1", "1.2.3-rc.1", "1.2.3"},
+		{"1.2.3 || 2.0.0", "1.2.3", "2.0.0"},
+		{"<1.2.3", "1.2.2", ""},
+		{"*", "18446744073709551615.18446744073709551615.18446744073709551615", ""},
+		{"^0.0.3", "0.0.3", "0.1.3"},
+		{"^0.0.3", "0.4.3", "0.5.3"},
+		{"^*", "0.0.0", "0.1.0"},
+	}
+
+	for _, tc := range tests {
+		c, err := NewConstraint(tc.constraint)
+		if err != nil {
+			t.Fatalf("Error parsing constraint %q: %s", tc.constraint, err)
+		}
+
+		n, ok := c.NextAbove(MustParse(tc.version))
+		if tc.expected == "" {
+			if ok {
+				t.Errorf("Expected no release of %q above %s, got %s", tc.constraint, tc.version, n)
+			}
+			continue
+		}
+		if !ok || n.String() != tc.expected {
+			t.Errorf("Expected release of %q above %s to be %s, got %v", tc.constraint, tc.version, tc.expected, n)
+		}
+	}
+}
+
+func TestPrevBelow(t *testing.T) {
+	tests := []struct {
+		constraint string
+		version    string
+		expected   string
+	}{
+		{"<=1.2.3", "2.0.0", "1.2.3"},
+		{"<1.2.3", "2.0.0", "1.2.2"},
+		{"<2.0.0", "2.0.0", "1.18446744073709551615.18446744073709551615"},
+		{"~1.2.3", "1.2.5", "1.2.4"},
+		{"!=1.2.3", "1.2.4", "1.2.2"},
+		{"!=1.2.x", "1.3.0", "1.1.18446744073709551615"},
+		{">=1.0.0 <1.2.3", "1.2.3-rc.1", "1.2.2"},
+		{">=1.0.0 <=1.2.3", "1.2.3-rc.1", "1.2.2"},
+		{"1.2.3 || 2.0.0", "2.0.0", "1.2.3"},
+		{">1.2.3", "1.2.4", ""},
+		{"*", "0.0.0", ""},
+		{"^0.0.3", "1.0.0", "0.18446744073709551615.3"},
+		{"^0.0.3", "0.2.0", "0.1.3"},
+		{"^0.0.3", "0.0.3", ""},
+	}
+
+	for _, tc := range tests {
+		c, err := NewConstraint(tc.constraint)
+		if err != nil {
+			t.Fatalf("Error parsing constraint %q: %s", tc.constraint, err)
+		}
+
+		p, ok := c.PrevBelow(MustParse(tc.version))
+		if tc.expected == "" {
+			if ok {
+				t.Errorf("Expected no release of %q below %s, got %s", tc.constraint, tc.version, p)
+			}
+			continue
+		}
+		if !ok || p.String() != tc.expected {
+			t.Errorf("Expected release of %q below %s to be %s, got %v", tc.constraint, tc.version, tc.expected, p)
+		}
+	}
+}
+
+func TestAdjacentGenerated(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	opts := &GenerateOptions{MaxSegment: 3}
+
+	// Every release with numbers up to 4, in order.
+	var grid Collection
+	for x := uint64(0); x <= 4; x++ {
+		for y := uint64(0); y <= 4; y++ {
+			for z := uint64(0); z <= 4; z++ {
+				grid = append(grid, newRelease([3]uint64{x, y, z}))
+			}
+		}
+	}
+
+	for i := 0; i < 500; i++ {
+		c := GenerateConstraint(r, opts)
+		v := GenerateVersion(r, opts)
+
+		if n, ok := c.NextAbove(v); ok {
+			if !n.GreaterThan(v) || !c.Check(n) {
+				t.Errorf("Release %s of %q above %s is not admitted", n, c, v)
+			}
+			for _, g := range grid {
+				if g.GreaterThan(v) && g.LessThan(n) && c.Check(g) {
+					t.Errorf("Expected release of %q above %s to be %s, got %s", c, v, g, n)
+					break
+				}
+			}
+		} else {
+			for _, g := range grid {
+				if g.GreaterThan(v) && c.Check(g) {
+					t.Errorf("Expected a release of %q above %s, such as %s", c, v, g)
+					break
+				}
+			}
+		}
+
+		if p, ok := c.PrevBelow(v); ok {
+			if !p.LessThan(v) || !c.Check(p) {
+				t.Errorf("Release %s of %q below %s is not admitted", p, c, v)
+			}
+			for _, g := range grid {
+				if g.LessThan(v) && g.GreaterThan(p) && c.Check(g) {
+					t.Errorf("Expected release of %q below %s to be %s, got %s", c, v, g, p)
+					break
+				}
+			}
+		} else {
+			for _, g := range grid {
+				if g.LessThan(v) && c.Check(g) {
+					t.Errorf("Expected a release of %q below %s, such as %s", c, v, g)
+					break
+				}
+			}
+		}
+	}
+}
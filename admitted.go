@@ -0,0 +1,123 @@
+package semver
+
+import (
+	"sort"
+)
+
+// AdmittedBy returns the versions of universe that the constraints admit, in
+// the order they appear in it. universe must be sorted in ascending order, as
+// sort.Sort sorts a Collection. Rather than checking every version, as
+// Collection.Filter does, the range each || group could admit is found by
+// binary search and only the versions in it are checked, so a narrow
+// constraint such as ~1.2.3 over a long list of published versions does
+// little work.
+func (cs Constraints) AdmittedBy(universe Collection) Collection {
+	var out Collection
+	cs.sweep(universe, func(v *Version) {
+		out = append(out, v)
+	})
+	return out
+}
+
+// Count returns the number of versions of universe that the constraints
+// admit. As with AdmittedBy, universe must be sorted in ascending order.
+func (cs Constraints) Count(universe Collection) int {
+	n := 0
+	cs.sweep(universe, func(*Version) {
+		n++
+	})
+	return n
+}
+
+// sweep calls fn with each admitted version of the sorted universe, in order.
+func (cs Constraints) sweep(universe Collection, fn func(*Version)) {
+	m := cs.Compile()
+
+	// Find the window of universe each group could admit, then visit the
+	// union of the windows once.
+	windows := make([][2]int, 0, len(cs.constraints))
+	for _, g := range cs.constraints {
+		lo, hi := 0, len(universe)
+		for _, c := range g {
+			if cs.IncludePrerelease {
+				c = cs.prereleaseConstraint(c)
+			}
+
+			b := c.window()
+			if b.Excluded {
+				continue
+			}
+			if b.Min != nil {
+				if i := searchMin(universe, b.Min, b.IncludeMin); i > lo {
+					lo = i
+				}
+			}
+			if b.Max != nil {
+				if i := searchMax(universe, b.Max, b.IncludeMax); i < hi {
+					hi = i
+				}
+			}
+		}
+		if lo < hi {
+			windows = append(windows, [2]int{lo, hi})
+		}
+	}
+	sort.Slice(windows, func(i, j int) bool { return windows[i][0] < windows[j][0] })
+
+	next := 0
+	for _, w := range windows {
+		if w[0] < next {
+			w[0] = next
+		}
+		for i := w[0]; i < w[1]; i++ {
+			if m.Check(universe[i]) {
+				fn(universe[i])
+			}
+		}
+		if w[1] > next {
+			next = w[1]
+		}
+	}
+}
+
+// window returns a range holding every version the constraint admits. It is
+// the bound of the constraint widened to the prereleases the check functions
+// admit outside of it. Not every version in it is admitted when the bound
+// has SamePatch set.
+func (c *constraint) window() Bound {
+	b := c.bound()
+
+	// >1.2 admits prereleases of 1.3.0 when checking prereleases by
+	// precedence.
+	if c.origfunc == ">" && c.dirty {
+		b.Min, b.IncludeMin = c.version(), false
+	}
+
+	// !=1.2.x excludes the prereleases of 1.2.0 but not those of 1.3.0 when
+	// checking prereleases by precedence, as in exclusionSides.
+	if c.includePrerelease && b.Excluded && c.dirty && !c.isAny() {
+		lo, hi := *b.Min, *b.Max
+		lo.pre, hi.pre = "0", "0"
+		b.Min, b.Max = &lo, &hi
+	}
+
+	return b
+}
+
+// searchMin returns the index of the first version of the sorted universe
+// that is at least min, or above it when inclusive is false.
+func searchMin(universe Collection, min *Version, inclusive bool) int {
+	return sort.Search(len(universe), func(i int) bool {
+		d := universe[i].Compare(min)
+		return d > 0 || d == 0 && inclusive
+	})
+}
+
+// searchMax returns the index after the last version of the sorted universe
+// that is at most max, or below it when inclusive is false.
+func searchMax(universe Collection, max *Version, inclusive bool) int {
+	return sort.Search(len(universe), func(i int) bool {
+		d := universe[i].Compare(max)
+		return d > 0 || d == 0 && !inclusive
+	})
+}
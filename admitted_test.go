@@ -0,0 +1,90 @@
+package semver
+
+import (
+	"math/rand"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestAdmittedBy(t *testing.T) {
+	universe := Collection{}
+	for _, s := range []string{
+		"0.0.3", "0.1.3", "0.2.0", "1.0.0", "1.2.2", "1.2.3-beta", "1.2.3",
+		"1.2.3+build", "1.2.4", "1.3.0-rc.1", "1.3.0", "2.0.0-rc.1", "2.0.0", "3.1.0",
+	} {
+		universe = append(universe, MustParse(s))
+	}
+	sort.Sort(universe)
+
+	tests := []struct {
+		constraint string
+		expected   string
+	}{
+		{"~1.2.3", "1.2.3 1.2.3+build 1.2.4"},
+		{">=1.2.3-0 <1.3.0-0", "1.2.3-beta 1.2.3 1.2.3+build 1.2.4"},
+		{"^1 || ^3", "1.0.0 1.2.2 1.2.3 1.2.3+build 1.2.4 1.3.0 3.1.0"},
+		{">=1.3.0 || ~1.2.3", "1.2.3 1.2.3+build 1.2.4 1.3.0 2.0.0 3.1.0"},
+		{"!=1.2.3 >1.2", "1.3.0 2.0.0 3.1.0"},
+		{"^0.0.3", "0.0.3 0.1.3"},
+		{">=5", ""},
+	}
+
+	for _, tc := range tests {
+		c, err := NewConstraint(tc.constraint)
+		if err != nil {
+			t.Fatalf("Error parsing constraint %q: %s", tc.constraint, err)
+		}
+
+		var got []string
+		for _, v := range c.AdmittedBy(universe) {
+			got = append(got, v.Original())
+		}
+		if s := strings.Join(got, " "); s != tc.expected {
+			t.Errorf("Expected %q to admit %q, got %q", tc.constraint, tc.expected, s)
+		}
+		if n := c.Count(universe); n != len(got) {
+			t.Errorf("Expected %q to count %d, got %d", tc.constraint, len(got), n)
+		}
+	}
+}
+
+func TestAdmittedByGenerated(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	opts := &GenerateOptions{MaxSegment: 3, Prerelease: 0.3, Metadata: 0.1}
+	options := []Constraints{
+		{},
+		{IncludePrerelease: true},
+		{IncludePrerelease: true, SnapPartialBounds: true},
+		{ExclusionsMatchMetadata: true, EqualityMatchesMetadata: true},
+	}
+
+	var universe Collection
+	for i := 0; i < 300; i++ {
+		universe = append(universe, GenerateVersion(r, opts))
+	}
+	sort.Sort(universe)
+
+	for i := 0; i < 500; i++ {
+		c := GenerateConstraint(r, opts)
+		for _, o := range options {
+			c.ExclusionsMatchMetadata = o.ExclusionsMatchMetadata
+			c.EqualityMatchesMetadata = o.EqualityMatchesMetadata
+			c.IncludePrerelease = o.IncludePrerelease
+			c.SnapPartialBounds = o.SnapPartialBounds
+
+			got := c.AdmittedBy(universe)
+			expected := universe.Filter(c)
+			if len(got) != len(expected) {
+				t.Errorf("Expected %q with %+v to admit %d versions, got %d", c, o, len(expected), len(got))
+				continue
+			}
+			for j := range got {
+				if got[j] != expected[j] {
+					t.Errorf("Expected %q with %+v to admit %s at %d, got %s", c, o, expected[j], j, got[j])
+					break
+				}
+			}
+		}
+	}
+}
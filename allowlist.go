@@ -0,0 +1,107 @@
+package semver
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ErrEmptyAllowList is returned by FromAllowList when there are no versions
+// to allow. Constraints always admit at least one version.
+var ErrEmptyAllowList = errors.New("Allow list is empty")
+
+// Precision is the part of a version that FromAllowList works at.
+type Precision int
+
+const (
+	// PrecisionPatch treats each version as itself.
+	PrecisionPatch Precision = iota
+
+	// PrecisionMinor treats each version as its minor line, so 1.2.3 stands
+	// for 1.2.x.
+	PrecisionMinor
+
+	// PrecisionMajor treats each version as its major line, so 1.2.3 stands
+	// for 1.x.
+	PrecisionMajor
+)
+
+// FromAllowList returns constraints admitting the versions in vs at
+// precision p. Versions that are contiguous at that precision are coalesced
+// into a range, such as 1.2.3, 1.2.4 and 1.2.5 into >=1.2.3 <=1.2.5 at
+// PrecisionPatch or 1.2.0 and 1.3.4 into >=1.2.0 <1.4.0 at PrecisionMinor. At
+// PrecisionPatch the constraints admit exactly the releases in vs, while
+// prereleases are kept as separate exact versions. Build metadata is ignored.
+//
+// Collection.Filter is the inverse, listing the versions of a collection
+// that the constraints admit.
+func FromAllowList(vs Collection, p Precision) (*Constraints, error) {
+	if len(vs) == 0 {
+		return nil, ErrEmptyAllowList
+	}
+
+	sorted := make(Collection, len(vs))
+	copy(sorted, vs)
+	sort.Sort(sorted)
+
+	var groups []string
+	var first, last *Version
+	flush := func() {
+		if first != nil {
+			groups = append(groups, allowRange(first, last, p))
+		}
+		first, last = nil, nil
+	}
+
+	for _, v := range sorted {
+		if p == PrecisionPatch && v.Prerelease() != "" {
+			groups = append(groups, fmt.Sprintf("%d.%d.%d-%s", v.Major(), v.Minor(), v.Patch(), v.Prerelease()))
+			continue
+		}
+
+		if first != nil && !allowContiguous(last, v, p) {
+			flush()
+		}
+		if first == nil {
+			first = v
+		}
+		last = v
+	}
+	flush()
+
+	return NewConstraint(strings.Join(groups, " || "))
+}
+
+// allowContiguous reports whether v is the same as or directly follows last
+// at precision p. last and v are in order.
+func allowContiguous(last, v *Version, p Precision) bool {
+	switch p {
+	case PrecisionMajor:
+		return v.Major() <= last.Major()+1
+	case PrecisionMinor:
+		return v.Major() == last.Major() && v.Minor() <= last.Minor()+1
+	}
+	return v.Major() == last.Major() && v.Minor() == last.Minor() && v.Patch() <= last.Patch()+1
+}
+
+// allowRange returns the constraint for the contiguous versions from first
+// to last at precision p.
+func allowRange(first, last *Version, p Precision) string {
+	switch p {
+	case PrecisionMajor:
+		if first.Major() == last.Major() {
+			return fmt.Sprintf("%d.x", first.Major())
+		}
+		return fmt.Sprintf(">=%d.0.0 <%d.0.0", first.Major(), last.Major()+1)
+	case PrecisionMinor:
+		if first.Minor() == last.Minor() {
+			return fmt.Sprintf("%d.%d.x", first.Major(), first.Minor())
+		}
+		return fmt.Sprintf(">=%d.%d.0 <%d.%d.0", first.Major(), first.Minor(), last.Major(), last.Minor()+1)
+	}
+	if first.Patch() == last.Patch() {
+		return fmt.Sprintf("%d.%d.%d", first.Major(), first.Minor(), first.Patch())
+	}
+	return fmt.Sprintf(">=%d.%d.%d <=%d.%d.%d", first.Major(), first.Minor(), first.Patch(), last.Major(), last.Minor(), last.Patch())
+}
@@ -0,0 +1,72 @@
+package semver
+
+import (
+	"testing"
+)
+
+func TestFromAllowList(t *testing.T) {
+	tests := []struct {
+		versions  []string
+		precision Precision
+		expected  string
+	}{
+		{[]string{"1.2.3"}, PrecisionPatch, "1.2.3"},
+		{[]string{"1.2.5", "1.2.3", "1.2.4"}, PrecisionPatch, ">=1.2.3 <=1.2.5"},
+		{[]string{"1.2.3", "1.2.4", "1.2.6", "1.3.0"}, PrecisionPatch, ">=1.2.3 <=1.2.4 || 1.2.6 || 1.3.0"},
+		{[]string{"1.2.3", "1.2.3+build.1", "1.2.4"}, PrecisionPatch, ">=1.2.3 <=1.2.4"},
+		{[]string{"1.2.3", "1.2.4-rc.1", "1.2.4"}, PrecisionPatch, "1.2.4-rc.1 || >=1.2.3 <=1.2.4"},
+		{[]string{"1.2.0", "1.3.4", "1.5.1"}, PrecisionMinor, ">=1.2.0 <1.4.0 || 1.5.x"},
+		{[]string{"1.9.0", "2.0.0"}, PrecisionMinor, "1.9.x || 2.0.x"},
+		{[]string{"1.2.0", "2.3.4", "4.0.0"}, PrecisionMajor, ">=1.0.0 <3.0.0 || 4.x"},
+	}
+
+	for _, tc := range tests {
+		vs := make(Collection, len(tc.versions))
+		for i, s := range tc.versions {
+			vs[i] = MustParse(s)
+		}
+
+		c, err := FromAllowList(vs, tc.precision)
+		if err != nil {
+			t.Errorf("Error creating constraints from %v: %s", tc.versions, err)
+			continue
+		}
+		if c.String() != tc.expected {
+			t.Errorf("Expected %v to become %q, got %q", tc.versions, tc.expected, c.String())
+		}
+
+		// Every version in the list is admitted.
+		for _, v := range vs {
+			if !c.Check(v) {
+				t.Errorf("Expected %q to admit %s", c, v)
+			}
+		}
+	}
+
+	if _, err := FromAllowList(nil, PrecisionPatch); err != ErrEmptyAllowList {
+		t.Errorf("Expected ErrEmptyAllowList for an empty list, got %v", err)
+	}
+}
+
+func TestFromAllowListFilter(t *testing.T) {
+	available := Collection{
+		MustParse("1.0.0"), MustParse("1.1.0"), MustParse("1.1.1"), MustParse("1.2.0"),
+		MustParse("1.2.1"), MustParse("1.2.2"), MustParse("2.0.0-rc.1"), MustParse("2.0.0"),
+	}
+	allowed := Collection{available[1], available[2], available[4], available[5], available[6]}
+
+	c, err := FromAllowList(allowed, PrecisionPatch)
+	if err != nil {
+		t.Fatalf("Error creating constraints: %s", err)
+	}
+
+	got := available.Filter(c)
+	if len(got) != len(allowed) {
+		t.Fatalf("Expected %v from %q, got %v", allowed, c, got)
+	}
+	for i := range got {
+		if got[i] != allowed[i] {
+			t.Errorf("Expected %s at %d, got %s", allowed[i], i, got[i])
+		}
+	}
+}
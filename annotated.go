@@ -0,0 +1,74 @@
+package semver
+
+import (
+	"time"
+)
+
+// Status is the support status of a release.
+type Status int
+
+const (
+	// StatusSupported is a release that is maintained.
+	StatusSupported Status = iota
+
+	// StatusDeprecated is a release that still works but should be moved
+	// away from.
+	StatusDeprecated
+
+	// StatusEOL is a release that has reached its end of life and is no
+	// longer maintained.
+	StatusEOL
+
+	// StatusYanked is a release that has been withdrawn and must not be
+	// selected.
+	StatusYanked
+)
+
+// String returns the name of the status.
+func (s Status) String() string {
+	switch s {
+	case StatusSupported:
+		return "supported"
+	case StatusDeprecated:
+		return "deprecated"
+	case StatusEOL:
+		return "end of life"
+	case StatusYanked:
+		return "yanked"
+	}
+	return "unknown"
+}
+
+// Annotated associates a version with its support status.
+type Annotated struct {
+	Version *Version
+	Status  Status
+
+	// Released is when the version was released and Changed is when it
+	// entered its current status. Either may be the zero time when unknown.
+	Released, Changed time.Time
+}
+
+// SelectAnnotated returns the highest of the annotated versions the
+// constraints admit, preferring supported releases over deprecated ones and
+// deprecated over end of life ones. Yanked releases are never selected.
+// supported reports whether the selected version is supported, so callers
+// can warn when a constraint only admits unsupported releases. nil is
+// returned when no version can be selected.
+func (cs Constraints) SelectAnnotated(as []*Annotated) (a *Annotated, supported bool) {
+	for _, c := range as {
+		if c.Status == StatusYanked || !cs.Check(c.Version) {
+			continue
+		}
+
+		if a == nil || c.Status < a.Status || (c.Status == a.Status && c.Version.GreaterThan(a.Version)) {
+			a = c
+		}
+	}
+
+	if a == nil {
+		return nil, false
+	}
+
+	return a, a.Status == StatusSupported
+}
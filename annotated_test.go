@@ -0,0 +1,72 @@
+package semver
+
+import (
+	"testing"
+)
+
+func TestStatusString(t *testing.T) {
+	tests := []struct {
+		status   Status
+		expected string
+	}{
+		{StatusSupported, "supported"},
+		{StatusDeprecated, "deprecated"},
+		{StatusEOL, "end of life"},
+		{StatusYanked, "yanked"},
+		{Status(42), "unknown"},
+	}
+
+	for _, tc := range tests {
+		if s := tc.status.String(); s != tc.expected {
+			t.Errorf("Expected status %d to be %q, got %q", tc.status, tc.expected, s)
+		}
+	}
+}
+
+func TestConstraintsSelectAnnotated(t *testing.T) {
+	as := []*Annotated{
+		{Version: MustParse("1.0.0"), Status: StatusEOL},
+		{Version: MustParse("1.1.0"), Status: StatusDeprecated},
+		{Version: MustParse("1.2.0"), Status: StatusDeprecated},
+		{Version: MustParse("1.3.0"), Status: StatusYanked},
+		{Version: MustParse("2.0.0"), Status: StatusSupported},
+		{Version: MustParse("2.1.0"), Status: StatusSupported},
+		{Version: MustParse("2.2.0"), Status: StatusYanked},
+	}
+
+	tests := []struct {
+		constraint string
+		expected   string
+		supported  bool
+	}{
+		{"*", "2.1.0", true},
+		{"^1", "1.2.0", false},
+		{"1.0.x", "1.0.0", false},
+		{"1.3.x", "", false},
+		{">=1.0.0", "2.1.0", true},
+		{"<2.0.0 || 2.0.x", "2.0.0", true},
+	}
+
+	for _, tc := range tests {
+		c, err := NewConstraint(tc.constraint)
+		if err != nil {
+			t.Errorf("Error parsing constraint %q: %s", tc.constraint, err)
+			continue
+		}
+
+		a, supported := c.SelectAnnotated(as)
+		if tc.expected == "" {
+			if a != nil {
+				t.Errorf("Expected nothing selected for %q, got %q", tc.constraint, a.Version)
+			}
+			continue
+		}
+		if a == nil || a.Version.String() != tc.expected {
+			t.Errorf("Expected %q to select %q, got %v", tc.constraint, tc.expected, a)
+			continue
+		}
+		if supported != tc.supported {
+			t.Errorf("Expected %q supported to be %t, got %t", tc.constraint, tc.supported, supported)
+		}
+	}
+}
@@ -0,0 +1,169 @@
+package semver
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// ComparisonRecord explains the outcome of comparing two versions.
+type ComparisonRecord struct {
+	A, B *Version
+
+	// Result is the result of A.Compare(B).
+	Result int
+
+	// Rule is the item of the precedence rules in section 11 of the
+	// specification that decided the result, such as "11.4.1", or "" when
+	// the versions have equal precedence.
+	Rule string
+
+	// Reason describes the decision.
+	Reason string
+}
+
+// String returns a description of the comparison.
+func (r ComparisonRecord) String() string {
+	op := "="
+	if r.Result < 0 {
+		op = "<"
+	} else if r.Result > 0 {
+		op = ">"
+	}
+
+	if r.Rule == "" {
+		return fmt.Sprintf("%s %s %s: %s", r.A, op, r.B, r.Reason)
+	}
+	return fmt.Sprintf("%s %s %s: %s (spec %s)", r.A, op, r.B, r.Reason, r.Rule)
+}
+
+// Explain compares the version to another one as Compare does and reports
+// which precedence rule decided the result. It is meant for debugging orders
+// that look surprising, such as 1.0.0-rc.10 sorting after 1.0.0-rc.9.
+func (v *Version) Explain(o *Version) ComparisonRecord {
+	r := ComparisonRecord{A: v, B: o}
+
+	segs := []struct {
+		name string
+		a, b uint64
+	}{
+		{"major", v.major, o.major},
+		{"minor", v.minor, o.minor},
+		{"patch", v.patch, o.patch},
+	}
+	for _, s := range segs {
+		if d := compareSegment(s.a, s.b); d != 0 {
+			r.Result = d
+			r.Rule = "11.2"
+			r.Reason = fmt.Sprintf("%s version %d differs from %d", s.name, s.a, s.b)
+			return r
+		}
+	}
+
+	switch {
+	case v.pre == "" && o.pre == "":
+		r.Reason = "major, minor, and patch versions are equal and neither has a prerelease"
+		if v.metadata != "" || o.metadata != "" {
+			r.Reason += ", build metadata is ignored"
+		}
+		return r
+	case v.pre == "":
+		r.Result = 1
+		r.Rule = "11.3"
+		r.Reason = "a release has higher precedence than a prerelease"
+		return r
+	case o.pre == "":
+		r.Result = -1
+		r.Rule = "11.3"
+		r.Reason = "a prerelease has lower precedence than a release"
+		return r
+	}
+
+	sparts := strings.Split(v.pre, ".")
+	oparts := strings.Split(o.pre, ".")
+	for i := 0; i < len(sparts) || i < len(oparts); i++ {
+		if i >= len(sparts) || i >= len(oparts) {
+			r.Result = 1
+			if i >= len(sparts) {
+				r.Result = -1
+			}
+			r.Rule = "11.4.4"
+			r.Reason = "prerelease identifiers are equal up to the shorter one, which has lower precedence"
+			return r
+		}
+
+		s, t := sparts[i], oparts[i]
+		d := comparePrePart(s, t)
+		if d == 0 {
+			continue
+		}
+
+		r.Result = d
+		sn := containsOnly(s, num)
+		tn := containsOnly(t, num)
+		switch {
+		case sn && tn:
+			r.Rule = "11.4.1"
+			r.Reason = fmt.Sprintf("prerelease identifier %d: numeric %s and %s are compared numerically", i+1, s, t)
+		case !sn && !tn:
+			r.Rule = "11.4.2"
+			r.Reason = fmt.Sprintf("prerelease identifier %d: alphanumeric %s and %s are compared in ASCII order", i+1, s, t)
+		default:
+			r.Rule = "11.4.3"
+			r.Reason = fmt.Sprintf("prerelease identifier %d: numeric identifiers have lower precedence than alphanumeric ones", i+1)
+		}
+		return r
+	}
+
+	r.Reason = "major, minor, patch, and prerelease are equal"
+	if v.metadata != "" || o.metadata != "" {
+		r.Reason += ", build metadata is ignored"
+	}
+	return r
+}
+
+// ComparisonAudit records the comparisons made through it, and the rule that
+// decided each, so they can be inspected afterwards. It is safe for
+// concurrent use. The zero value is ready to use.
+type ComparisonAudit struct {
+	mu      sync.Mutex
+	records []ComparisonRecord
+}
+
+// Compare compares v to o as Compare does and records the decision.
+func (a *ComparisonAudit) Compare(v, o *Version) int {
+	r := v.Explain(o)
+
+	a.mu.Lock()
+	a.records = append(a.records, r)
+	a.mu.Unlock()
+
+	return r.Result
+}
+
+// Sort sorts the collection in ascending order as sort.Sort does, recording
+// every comparison made.
+func (a *ComparisonAudit) Sort(c Collection) {
+	sort.Slice(c, func(i, j int) bool {
+		return a.Compare(c[i], c[j]) < 0
+	})
+}
+
+// Records returns the comparisons recorded so far in the order they were
+// made.
+func (a *ComparisonAudit) Records() []ComparisonRecord {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	out := make([]ComparisonRecord, len(a.records))
+	copy(out, a.records)
+	return out
+}
+
+// Reset discards the recorded comparisons.
+func (a *ComparisonAudit) Reset() {
+	a.mu.Lock()
+	a.records = nil
+	a.mu.Unlock()
+}
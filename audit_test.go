@@ -0,0 +1,90 @@
+package semver
+
+import (
+	"testing"
+	"testing/quick"
+)
+
+func TestExplain(t *testing.T) {
+	tests := []struct {
+		v1     string
+		v2     string
+		result int
+		rule   string
+	}{
+		{"1.2.3", "2.0.0", -1, "11.2"},
+		{"1.3.0", "1.2.9", 1, "11.2"},
+		{"1.2.4", "1.2.3", 1, "11.2"},
+		{"1.2.3", "1.2.3-rc.1", 1, "11.3"},
+		{"1.2.3-rc.1", "1.2.3", -1, "11.3"},
+		{"1.0.0-rc.10", "1.0.0-rc.9", 1, "11.4.1"},
+		{"1.0.0-alpha", "1.0.0-beta", -1, "11.4.2"},
+		{"1.0.0-1", "1.0.0-alpha", -1, "11.4.3"},
+		{"1.0.0-alpha.beta", "1.0.0-alpha.1", 1, "11.4.3"},
+		{"1.0.0-alpha", "1.0.0-alpha.1", -1, "11.4.4"},
+		{"1.0.0-alpha.1", "1.0.0-alpha", 1, "11.4.4"},
+		{"1.2.3", "1.2.3+build", 0, ""},
+		{"1.2.3-rc.1+a", "1.2.3-rc.1+b", 0, ""},
+	}
+
+	for _, tc := range tests {
+		r := MustParse(tc.v1).Explain(MustParse(tc.v2))
+		if r.Result != tc.result || r.Rule != tc.rule {
+			t.Errorf("Expected %s vs %s to be %d by %q, got %d by %q", tc.v1, tc.v2, tc.result, tc.rule, r.Result, r.Rule)
+		}
+		if r.Reason == "" {
+			t.Errorf("Expected a reason comparing %s and %s", tc.v1, tc.v2)
+		}
+	}
+
+	r := MustParse("1.0.0-rc.10").Explain(MustParse("1.0.0-rc.9"))
+	expected := "1.0.0-rc.10 > 1.0.0-rc.9: prerelease identifier 2: numeric 10 and 9 are compared numerically (spec 11.4.1)"
+	if r.String() != expected {
+		t.Errorf("Expected %q, got %q", expected, r.String())
+	}
+
+	// Explain must always agree with Compare.
+	f := func(a, b Version) bool {
+		return a.Explain(&b).Result == a.Compare(&b)
+	}
+	if err := quick.Check(f, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestComparisonAudit(t *testing.T) {
+	var a ComparisonAudit
+
+	c := Collection{MustParse("1.0.0-rc.9"), MustParse("1.0.0"), MustParse("1.0.0-rc.10")}
+	a.Sort(c)
+
+	if c[0].String() != "1.0.0-rc.9" || c[1].String() != "1.0.0-rc.10" || c[2].String() != "1.0.0" {
+		t.Errorf("Unexpected order %v", c)
+	}
+
+	records := a.Records()
+	if len(records) == 0 {
+		t.Fatal("Expected comparisons to be recorded")
+	}
+	found := false
+	for _, r := range records {
+		if r.Rule == "11.4.1" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("Expected the numeric prerelease comparison to be recorded, got %v", records)
+	}
+
+	if a.Compare(MustParse("1.0.0"), MustParse("2.0.0")) != -1 {
+		t.Error("Expected Compare to return the comparison result")
+	}
+	if len(a.Records()) != len(records)+1 {
+		t.Error("Expected Compare to record the comparison")
+	}
+
+	a.Reset()
+	if len(a.Records()) != 0 {
+		t.Error("Expected Reset to discard the records")
+	}
+}
@@ -245,3 +245,125 @@ func BenchmarkStrictNewVersionMetaDash(b *testing.B) {
 	b.ResetTimer()
 	benchStrictNewVersion("1.0.0-alpha.1+meta.data", b)
 }
+
+func BenchmarkParseBytes(b *testing.B) {
+	v := []byte("1.0.0-alpha.1+meta.data")
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = ParseBytes(v)
+	}
+}
+
+func BenchmarkInternerNewVersion(b *testing.B) {
+	in := NewInterner(0)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = in.NewVersion("1.0.0-alpha.1+meta.data")
+	}
+}
+
+/* Version comparison benchmarks */
+
+func benchCompare(v, o string, b *testing.B) {
+	v1 := MustParse(v)
+	v2 := MustParse(o)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = v1.Compare(v2)
+	}
+}
+
+func BenchmarkCompareSimple(b *testing.B) {
+	benchCompare("1.2.3", "1.2.4", b)
+}
+
+func BenchmarkComparePre(b *testing.B) {
+	benchCompare("1.2.3-alpha.1.beta.2", "1.2.3-alpha.1.beta.10", b)
+}
+
+func BenchmarkCompareTotalMeta(b *testing.B) {
+	v1 := MustParse("1.2.3-rc.1+build.1")
+	v2 := MustParse("1.2.3-rc.1+build.2")
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = v1.CompareTotal(v2)
+	}
+}
+
+/* Compiled constraint benchmarks */
+
+func benchFilter(c string, compile bool, b *testing.B) {
+	cs, err := NewConstraint(c)
+	if err != nil {
+		b.Fatal(err)
+	}
+	cs.IncludePrerelease = true
+
+	var vs Collection
+	for minor := uint64(0); minor < 100; minor++ {
+		for patch := uint64(0); patch < 10; patch++ {
+			vs = append(vs, &Version{major: 1, minor: minor, patch: patch})
+			vs = append(vs, &Version{major: 1, minor: minor, patch: patch, pre: "rc.1"})
+		}
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if compile {
+			_ = cs.Compile().Filter(vs)
+		} else {
+			_ = vs.Filter(cs)
+		}
+	}
+}
+
+const benchFilterConstraint = ">=1.0.0 !=1.1.0 !=1.2.0 !=1.3.0 !=1.4.0 !=1.5.0 !=1.6.0 !=1.7.0 !=1.8.0 || >=3.0.0"
+
+func BenchmarkFilterCollection(b *testing.B) {
+	benchFilter(benchFilterConstraint, false, b)
+}
+
+func BenchmarkFilterMatcher(b *testing.B) {
+	benchFilter(benchFilterConstraint, true, b)
+}
+
+/* Range enumeration benchmarks */
+
+func benchAdmitted(c string, sweep bool, b *testing.B) {
+	cs, err := NewConstraint(c)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	var vs Collection
+	for major := uint64(0); major < 10; major++ {
+		for minor := uint64(0); minor < 100; minor++ {
+			for patch := uint64(0); patch < 10; patch++ {
+				vs = append(vs, &Version{major: major, minor: minor, patch: patch})
+			}
+		}
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if sweep {
+			_ = cs.AdmittedBy(vs)
+		} else {
+			_ = vs.Filter(cs)
+		}
+	}
+}
+
+func BenchmarkAdmittedByFilter(b *testing.B) {
+	benchAdmitted("~1.2.3 || ^5.4", false, b)
+}
+
+func BenchmarkAdmittedBySweep(b *testing.B) {
+	benchAdmitted("~1.2.3 || ^5.4", true, b)
+}
@@ -0,0 +1,281 @@
+package semver
+
+import (
+	"encoding/binary"
+	"errors"
+)
+
+// binaryFormat is the first byte of the binary encodings of versions and
+// constraints. It is changed when the encoding changes.
+const binaryFormat = 1
+
+// ErrInvalidBinary is returned by UnmarshalBinary when the data was not
+// written by MarshalBinary.
+var ErrInvalidBinary = errors.New("Invalid binary encoding")
+
+// Bits of the flags byte of an encoded version.
+const binaryOriginal = 1 << 0
+
+// Bits of the flags byte of an encoded constraint term.
+const (
+	binaryDirty = 1 << iota
+	binaryMinorDirty
+	binaryPatchDirty
+)
+
+// Bits of the options byte of encoded constraints.
+const (
+	binaryExclusionsMatchMetadata = 1 << iota
+	binaryEqualityMatchesMetadata
+	binaryIncludePrerelease
+	binarySnapPartialBounds
+)
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface, which
+// encoding/gob also uses. The encoding is smaller than the string form and is
+// decoded without parsing the version.
+func (v Version) MarshalBinary() ([]byte, error) {
+	var e binaryEncoder
+	e.byte(binaryFormat)
+	e.version(&v)
+	return e.buf, nil
+}
+
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface,
+// decoding a version encoded by MarshalBinary.
+func (v *Version) UnmarshalBinary(b []byte) error {
+	d := binaryDecoder{buf: b}
+	if d.byte() != binaryFormat {
+		return ErrInvalidBinary
+	}
+
+	var temp Version
+	d.version(&temp)
+	if err := d.finish(); err != nil {
+		return err
+	}
+
+	*v = temp
+	return nil
+}
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface, which
+// encoding/gob also uses. The terms of the constraints are encoded in the
+// form they are checked in, along with the options, so decoding them does no
+// parsing.
+func (cs Constraints) MarshalBinary() ([]byte, error) {
+	var e binaryEncoder
+	e.byte(binaryFormat)
+
+	var opts byte
+	if cs.ExclusionsMatchMetadata {
+		opts |= binaryExclusionsMatchMetadata
+	}
+	if cs.EqualityMatchesMetadata {
+		opts |= binaryEqualityMatchesMetadata
+	}
+	if cs.IncludePrerelease {
+		opts |= binaryIncludePrerelease
+	}
+	if cs.SnapPartialBounds {
+		opts |= binarySnapPartialBounds
+	}
+	e.byte(opts)
+
+	e.uvarint(uint64(len(cs.constraints)))
+	for _, v := range cs.constraints {
+		e.uvarint(uint64(len(v)))
+		for _, c := range v {
+			var flags byte
+			if c.dirty {
+				flags |= binaryDirty
+			}
+			if c.minorDirty {
+				flags |= binaryMinorDirty
+			}
+			if c.patchDirty {
+				flags |= binaryPatchDirty
+			}
+			e.byte(flags)
+			e.string(c.origfunc)
+			e.string(c.orig)
+			e.version(c.con)
+		}
+	}
+
+	return e.buf, nil
+}
+
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface,
+// decoding constraints encoded by MarshalBinary.
+func (cs *Constraints) UnmarshalBinary(b []byte) error {
+	d := binaryDecoder{buf: b}
+	if d.byte() != binaryFormat {
+		return ErrInvalidBinary
+	}
+	opts := d.byte()
+
+	n := d.uvarint()
+	if n == 0 || n > uint64(len(d.buf)) {
+		return ErrInvalidBinary
+	}
+	if MaxConstraintGroups > 0 && n > uint64(MaxConstraintGroups) {
+		return ErrTooManyConstraintGroups
+	}
+
+	or := make([][]*constraint, n)
+	for k := range or {
+		m := d.uvarint()
+		if m == 0 || m > uint64(len(d.buf)) {
+			return ErrInvalidBinary
+		}
+
+		or[k] = make([]*constraint, m)
+		for kk := range or[k] {
+			flags := d.byte()
+			c := &constraint{
+				dirty:      flags&binaryDirty != 0,
+				minorDirty: flags&binaryMinorDirty != 0,
+				patchDirty: flags&binaryPatchDirty != 0,
+				origfunc:   d.string(),
+				orig:       d.string(),
+				con:        &Version{},
+			}
+			d.version(c.con)
+			if d.err != nil {
+				return d.err
+			}
+			if _, ok := constraintOps[c.origfunc]; !ok {
+				return ErrInvalidBinary
+			}
+			or[k][kk] = c
+		}
+	}
+	if err := d.finish(); err != nil {
+		return err
+	}
+
+	cs.constraints = or
+	cs.ExclusionsMatchMetadata = opts&binaryExclusionsMatchMetadata != 0
+	cs.EqualityMatchesMetadata = opts&binaryEqualityMatchesMetadata != 0
+	cs.IncludePrerelease = opts&binaryIncludePrerelease != 0
+	cs.SnapPartialBounds = opts&binarySnapPartialBounds != 0
+
+	return nil
+}
+
+type binaryEncoder struct {
+	buf []byte
+}
+
+func (e *binaryEncoder) byte(b byte) {
+	e.buf = append(e.buf, b)
+}
+
+func (e *binaryEncoder) uvarint(x uint64) {
+	var tmp [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(tmp[:], x)
+	e.buf = append(e.buf, tmp[:n]...)
+}
+
+func (e *binaryEncoder) string(s string) {
+	e.uvarint(uint64(len(s)))
+	e.buf = append(e.buf, s...)
+}
+
+// version encodes v. The original string is only stored when it differs
+// from the canonical one.
+func (e *binaryEncoder) version(v *Version) {
+	original := v.original != v.String()
+	if original {
+		e.byte(binaryOriginal)
+	} else {
+		e.byte(0)
+	}
+	e.uvarint(v.major)
+	e.uvarint(v.minor)
+	e.uvarint(v.patch)
+	e.string(v.pre)
+	e.string(v.metadata)
+	if original {
+		e.string(v.original)
+	}
+}
+
+// binaryDecoder reads what binaryEncoder writes. The first error is kept in
+// err and later reads return zero values.
+type binaryDecoder struct {
+	buf []byte
+	err error
+}
+
+func (d *binaryDecoder) byte() byte {
+	if d.err != nil || len(d.buf) == 0 {
+		d.err = ErrInvalidBinary
+		return 0
+	}
+	b := d.buf[0]
+	d.buf = d.buf[1:]
+	return b
+}
+
+func (d *binaryDecoder) uvarint() uint64 {
+	if d.err != nil {
+		return 0
+	}
+	x, n := binary.Uvarint(d.buf)
+	if n <= 0 {
+		d.err = ErrInvalidBinary
+		return 0
+	}
+	d.buf = d.buf[n:]
+	return x
+}
+
+func (d *binaryDecoder) string() string {
+	n := d.uvarint()
+	if d.err != nil || n > uint64(len(d.buf)) {
+		d.err = ErrInvalidBinary
+		return ""
+	}
+	s := string(d.buf[:n])
+	d.buf = d.buf[n:]
+	return s
+}
+
+// version decodes into v, checking the prerelease and metadata are valid.
+func (d *binaryDecoder) version(v *Version) {
+	flags := d.byte()
+	v.major = d.uvarint()
+	v.minor = d.uvarint()
+	v.patch = d.uvarint()
+	v.pre = d.string()
+	v.metadata = d.string()
+	if flags&binaryOriginal != 0 {
+		v.original = d.string()
+	} else {
+		v.original = v.String()
+	}
+	if d.err != nil {
+		return
+	}
+
+	if v.pre != "" {
+		if err := validatePrerelease(v.pre); err != nil {
+			d.err = err
+		}
+	}
+	if v.metadata != "" {
+		if err := validateMetadata(v.metadata); err != nil {
+			d.err = err
+		}
+	}
+}
+
+// finish returns the first error, or an error when there is trailing data.
+func (d *binaryDecoder) finish() error {
+	if d.err == nil && len(d.buf) > 0 {
+		d.err = ErrInvalidBinary
+	}
+	return d.err
+}
@@ -0,0 +1,158 @@
+package semver
+
+import (
+	"bytes"
+	"encoding/gob"
+	"testing"
+)
+
+func TestVersionBinary(t *testing.T) {
+	tests := []string{
+		"1.2.3",
+		"v1.2.3",
+		"1.2",
+		"1.2.3-beta.1+build.345",
+		"18446744073709551615.0.0",
+	}
+
+	for _, tc := range tests {
+		v := MustParse(tc)
+		b, err := v.MarshalBinary()
+		if err != nil {
+			t.Errorf("Error marshaling %q: %s", tc, err)
+			continue
+		}
+
+		var u Version
+		if err := u.UnmarshalBinary(b); err != nil {
+			t.Errorf("Error unmarshaling %q: %s", tc, err)
+			continue
+		}
+		if u.String() != v.String() || u.Original() != v.Original() {
+			t.Errorf("Expected %q to round trip, got %q from %q", tc, u.String(), u.Original())
+		}
+	}
+
+	var zero Version
+	b, err := zero.MarshalBinary()
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	var u Version
+	if err := u.UnmarshalBinary(b); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if u != zero {
+		t.Errorf("Expected the zero version to round trip, got %#v", u)
+	}
+}
+
+func TestConstraintsBinary(t *testing.T) {
+	tests := []string{
+		">=1.2.3, !=1.4.x || ^3",
+		"1.2.3 - 2.3.4",
+		"~1.2.x-beta",
+		"1.2",
+		"*",
+		"=>v1.0.0 =<2",
+		"!=1.2.3+build.1",
+	}
+	versions := []string{"0.9.0", "1.2.3", "1.2.3+build.1", "1.2.4-beta", "1.4.2", "2.0.0", "3.1.0"}
+
+	for _, tc := range tests {
+		c, err := NewConstraint(tc)
+		if err != nil {
+			t.Fatalf("Error parsing constraint %q: %s", tc, err)
+		}
+		c.ExclusionsMatchMetadata = true
+		c.IncludePrerelease = true
+
+		b, err := c.MarshalBinary()
+		if err != nil {
+			t.Errorf("Error marshaling %q: %s", tc, err)
+			continue
+		}
+
+		var u Constraints
+		if err := u.UnmarshalBinary(b); err != nil {
+			t.Errorf("Error unmarshaling %q: %s", tc, err)
+			continue
+		}
+		if u.String() != c.String() {
+			t.Errorf("Expected %q to round trip, got %q", tc, u.String())
+		}
+		if !u.ExclusionsMatchMetadata || !u.IncludePrerelease || u.EqualityMatchesMetadata || u.SnapPartialBounds {
+			t.Errorf("Expected the options of %q to round trip, got %+v", tc, u)
+		}
+		for _, s := range versions {
+			v := MustParse(s)
+			if a, e := u.Check(v), c.Check(v); a != e {
+				t.Errorf("Expected unmarshaled %q check of %s to be %t", tc, s, e)
+			}
+		}
+
+		// Every truncation is an error rather than a panic.
+		for i := 0; i < len(b); i++ {
+			var u Constraints
+			if err := u.UnmarshalBinary(b[:i]); err == nil {
+				t.Errorf("Expected error unmarshaling %d bytes of %q", i, tc)
+			}
+		}
+		if err := u.UnmarshalBinary(append(b, 0)); err == nil {
+			t.Errorf("Expected error unmarshaling %q with trailing data", tc)
+		}
+	}
+}
+
+func TestBinaryInvalid(t *testing.T) {
+	v := MustParse("1.2.3-beta")
+	b, err := v.MarshalBinary()
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	// Corrupt the prerelease.
+	b[len(b)-2] = '!'
+	var u Version
+	if err := u.UnmarshalBinary(b); err != ErrInvalidPrerelease {
+		t.Errorf("Expected ErrInvalidPrerelease, got %v", err)
+	}
+
+	var c Constraints
+	if err := c.UnmarshalBinary([]byte{2}); err != ErrInvalidBinary {
+		t.Errorf("Expected ErrInvalidBinary for an unknown format, got %v", err)
+	}
+}
+
+func TestGob(t *testing.T) {
+	type entry struct {
+		Version     *Version
+		Constraints *Constraints
+	}
+
+	c, err := NewConstraint("^1.2 || >=3.0.0-0")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	c.SnapPartialBounds = true
+	in := entry{Version: MustParse("v1.4.0"), Constraints: c}
+
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(in); err != nil {
+		t.Fatalf("Error encoding: %s", err)
+	}
+	var out entry
+	if err := gob.NewDecoder(&buf).Decode(&out); err != nil {
+		t.Fatalf("Error decoding: %s", err)
+	}
+
+	if out.Version.Original() != "v1.4.0" {
+		t.Errorf("Expected version v1.4.0, got %s", out.Version.Original())
+	}
+	if out.Constraints.String() != c.String() || !out.Constraints.SnapPartialBounds {
+		t.Errorf("Expected constraints %q, got %q", c, out.Constraints)
+	}
+	if !out.Constraints.Check(out.Version) {
+		t.Errorf("Expected %q to admit %s", out.Constraints, out.Version)
+	}
+}
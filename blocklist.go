@@ -0,0 +1,97 @@
+package semver
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Blocklist holds versions that must not be used, per package. It is meant
+// for organizations that maintain a central "do not use" list alongside the
+// constraints each project declares. A version is blocked for a package when
+// it satisfies any of the constraints added for that package. Prereleases
+// are checked by precedence, as with IncludePrerelease, so a ban on 1.4.x
+// also blocks 1.4.1-rc.1. 1.4.0-rc.1 sorts before 1.4.0 and is not blocked.
+type Blocklist struct {
+	entries map[string][]*Constraints
+}
+
+// NewBlocklist returns an empty Blocklist.
+func NewBlocklist() *Blocklist {
+	return &Blocklist{entries: make(map[string][]*Constraints)}
+}
+
+// ParseBlocklist reads a Blocklist from r. Each line holds a package name
+// followed by whitespace and a constraint matching the blocked versions, such
+// as
+//
+//	github.com/foo/bar 1.2.3 || 1.4.x
+//	example.com/baz <0.9.0
+//
+// A package may appear on several lines. Blank lines and lines starting with
+// # are ignored. Errors include the line number.
+func ParseBlocklist(r io.Reader) (*Blocklist, error) {
+	b := NewBlocklist()
+
+	s := bufio.NewScanner(r)
+	for n := 1; s.Scan(); n++ {
+		line := strings.TrimSpace(s.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		i := strings.IndexAny(line, " \t")
+		if i == -1 {
+			return nil, fmt.Errorf("line %d: missing constraint for %s", n, line)
+		}
+
+		c, err := NewConstraint(strings.TrimSpace(line[i+1:]))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %s", n, err)
+		}
+		b.Add(line[:i], c)
+	}
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// Add blocks the versions of the package satisfying the constraint,
+// including the prereleases within it. c is not modified.
+func (b *Blocklist) Add(pkg string, c *Constraints) {
+	pre := *c
+	pre.IncludePrerelease = true
+	b.entries[pkg] = append(b.entries[pkg], &pre)
+}
+
+// Blocked reports whether the version of the package is blocked.
+func (b *Blocklist) Blocked(pkg string, v *Version) bool {
+	for _, c := range b.entries[pkg] {
+		if c.Check(v) {
+			return true
+		}
+	}
+	return false
+}
+
+// Check reports whether the version of the package satisfies the constraint
+// and is not blocked. This applies the blocklist to the constraints a
+// project declares.
+func (b *Blocklist) Check(pkg string, c *Constraints, v *Version) bool {
+	return c.Check(v) && !b.Blocked(pkg, v)
+}
+
+// Filter returns the versions of the package that are not blocked, in the
+// order they were given.
+func (b *Blocklist) Filter(pkg string, vs Collection) Collection {
+	var out Collection
+	for _, v := range vs {
+		if !b.Blocked(pkg, v) {
+			out = append(out, v)
+		}
+	}
+	return out
+}
@@ -0,0 +1,85 @@
+package semver
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseBlocklist(t *testing.T) {
+	input := `
+# Known bad releases.
+github.com/foo/bar 1.2.3 || 1.4.x
+github.com/foo/bar	>=2.0.0-0, <2.0.2-0
+example.com/baz <0.9.0
+`
+
+	b, err := ParseBlocklist(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	tests := []struct {
+		pkg     string
+		version string
+		blocked bool
+	}{
+		{"github.com/foo/bar", "1.2.3", true},
+		{"github.com/foo/bar", "1.2.4", false},
+		{"github.com/foo/bar", "1.4.7", true},
+		{"github.com/foo/bar", "1.4.1-rc.1", true},
+		{"github.com/foo/bar", "1.4.0-rc.1", false},
+		{"github.com/foo/bar", "1.2.3-rc.1", false},
+		{"github.com/foo/bar", "2.0.0-rc.1", true},
+		{"github.com/foo/bar", "2.0.1", true},
+		{"github.com/foo/bar", "2.0.2", false},
+		{"example.com/baz", "0.8.0", true},
+		{"example.com/baz", "0.9.0-rc.1", true},
+		{"example.com/baz", "1.2.3", false},
+		{"example.com/other", "1.2.3", false},
+	}
+
+	for _, tc := range tests {
+		if a := b.Blocked(tc.pkg, MustParse(tc.version)); a != tc.blocked {
+			t.Errorf("Expected %s %s blocked to be %t, got %t", tc.pkg, tc.version, tc.blocked, a)
+		}
+	}
+
+	invalid := []string{
+		"github.com/foo/bar",
+		"github.com/foo/bar >=foo",
+	}
+	for _, s := range invalid {
+		if _, err := ParseBlocklist(strings.NewReader("# header\n" + s)); err == nil || !strings.HasPrefix(err.Error(), "line 2: ") {
+			t.Errorf("Expected an error on line 2 for %q, got %v", s, err)
+		}
+	}
+}
+
+func TestBlocklistCheck(t *testing.T) {
+	b := NewBlocklist()
+	blocked, _ := NewConstraint("1.3.0")
+	b.Add("foo", blocked)
+
+	c, _ := NewConstraint("^1.2")
+	tests := []struct {
+		version string
+		ok      bool
+	}{
+		{"1.2.0", true},
+		{"1.3.0", false},
+		{"1.3.1", true},
+		{"2.0.0", false},
+	}
+
+	for _, tc := range tests {
+		if a := b.Check("foo", c, MustParse(tc.version)); a != tc.ok {
+			t.Errorf("Expected %s to be allowed %t, got %t", tc.version, tc.ok, a)
+		}
+	}
+
+	vs := Collection{MustParse("1.2.0"), MustParse("1.3.0"), MustParse("1.4.0")}
+	out := b.Filter("foo", vs)
+	if len(out) != 2 || out[0].String() != "1.2.0" || out[1].String() != "1.4.0" {
+		t.Errorf("Expected 1.3.0 to be filtered out, got %v", out)
+	}
+}
@@ -0,0 +1,256 @@
+package semver
+
+import (
+	"math"
+)
+
+// Bound is a read-only view of a single term of a constraint, such as
+// >=1.2.3 or ~1.2.x, as the range of versions it admits. It lets tools such
+// as linters and converters to other formats inspect constraints without
+// parsing their strings. Whether the prereleases in a range are admitted
+// follows the rules in the package documentation and is not part of the view.
+type Bound struct {
+	// Op and Version are the operator and version as written. Op is "" for
+	// equality without an operator.
+	Op, Version string
+
+	// Min and Max are the ends of the range, nil when it is unbounded in
+	// that direction. IncludeMin and IncludeMax report whether Min and Max
+	// are themselves in the range.
+	Min, Max               *Version
+	IncludeMin, IncludeMax bool
+
+	// Excluded is set for != terms, which admit the versions outside the
+	// range rather than those inside it.
+	Excluded bool
+
+	// SamePatch is set when only the versions in the range with the same
+	// patch number as Min are admitted. ^0.0.3 is [0.0.3, 1.0.0) with
+	// SamePatch, as it admits 0.7.3 but not 0.7.4, and ^* is [0.0.0, 1.0.0)
+	// with SamePatch. See constraintCaret.
+	SamePatch bool
+}
+
+// Admits reports whether v is in the range of the bound, or outside it when
+// the bound is Excluded. Whether a prerelease is admitted is not considered.
+func (b Bound) Admits(v *Version) bool {
+	return b.contains(v) != b.Excluded
+}
+
+func (b Bound) contains(v *Version) bool {
+	if b.Min != nil {
+		if d := v.Compare(b.Min); d < 0 || d == 0 && !b.IncludeMin {
+			return false
+		}
+	}
+	if b.Max != nil {
+		if d := v.Compare(b.Max); d > 0 || d == 0 && !b.IncludeMax {
+			return false
+		}
+	}
+	return !b.SamePatch || v.Patch() == b.Min.Patch()
+}
+
+// Visit calls fn with each term of the constraints in the order they were
+// written. group is the index of the || group the term is in. A version
+// satisfies the constraints if it is admitted by every term of any group.
+// Hyphen ranges are visited as a >= and a <= term.
+func (cs Constraints) Visit(fn func(group int, b Bound)) {
+	for k, v := range cs.constraints {
+		for _, c := range v {
+			fn(k, c.bound())
+		}
+	}
+}
+
+// bound returns the range of versions the constraint admits. Open ends of
+// wildcard ranges are the versions the constraint functions compare against.
+func (c *constraint) bound() Bound {
+	b := Bound{Op: c.origfunc, Version: c.orig}
+
+	switch c.origfunc {
+	case "", "=", "!=":
+		b.Excluded = c.origfunc == "!="
+		if !c.dirty || c.isAny() && b.Excluded {
+			// != with a wildcard major only excludes 0.0.0. See
+			// constraintNotEqual.
+			b.Min, b.IncludeMin = c.version(), true
+			b.Max, b.IncludeMax = c.version(), true
+		} else if !c.isAny() {
+			b.Min, b.IncludeMin = c.version(), true
+			b.Max = boundVersion(c.tildeUpper())
+		}
+	case ">":
+		switch {
+		case c.minorDirty:
+			b.Min, b.IncludeMin = boundVersion(c.con.IncMajor()), true
+		case c.patchDirty:
+			b.Min, b.IncludeMin = boundVersion(c.con.IncMinor()), true
+		default:
+			b.Min = c.version()
+		}
+	case "<":
+		b.Max = c.version()
+	case ">=", "=>":
+		b.Min, b.IncludeMin = c.version(), true
+	case "<=", "=<":
+		switch {
+		case !c.dirty:
+			b.Max, b.IncludeMax = c.version(), true
+		case c.minorDirty:
+			b.Max = boundVersion(c.con.IncMajor())
+		default:
+			b.Max = boundVersion(c.con.IncMinor())
+		}
+	case "~", "~>":
+		b.Min, b.IncludeMin = c.version(), true
+		if c.con.Major() != 0 || c.con.Minor() != 0 || c.con.Patch() != 0 ||
+			c.minorDirty || c.patchDirty {
+			// ~0.0.0 and ~* admit everything. See constraintTilde.
+			b.Max = boundVersion(c.tildeUpper())
+		}
+	case "^":
+		b.Min, b.IncludeMin = c.version(), true
+		if c.caretSamePatch() {
+			b.Max, b.SamePatch = boundVersion(c.con.IncMajor()), true
+		} else {
+			b.Max = boundVersion(c.caretUpper())
+		}
+	}
+
+	return b
+}
+
+// version returns a copy of the version of the constraint so a Bound cannot
+// change it.
+func (c *constraint) version() *Version {
+	return boundVersion(*c.con)
+}
+
+func boundVersion(v Version) *Version {
+	v.original = v.String()
+	return &v
+}
+
+// MinAdmissible returns the lowest end of the ranges of versions the
+// constraints could admit, for tools such as lockfile generators that pick
+// the lowest version under minimal version selection. inclusive reports
+// whether min itself is in the range, so >1.2.3 gives 1.2.3 and false. min is
+// nil when the range is unbounded below. ok is false when the ranges of the
+// terms of every || group do not overlap, so no version is admitted. As with
+// Visit, the prerelease rules are not part of the ranges, so a prerelease at
+// or near the end may still be rejected.
+func (cs Constraints) MinAdmissible() (min *Version, inclusive, ok bool) {
+	for _, r := range cs.admissibleRanges() {
+		if r.min == nil {
+			return nil, false, true
+		}
+		if !ok {
+			min, inclusive, ok = r.min, r.incMin, true
+		} else if n := r.min.Compare(min); n < 0 || n == 0 && r.incMin {
+			min, inclusive = r.min, r.incMin
+		}
+	}
+	return min, inclusive, ok
+}
+
+// MaxAdmissible returns the highest end of the ranges of versions the
+// constraints could admit in the same way as MinAdmissible. ^1.2.3 gives
+// 2.0.0 and false. max is nil when the range is unbounded above.
+func (cs Constraints) MaxAdmissible() (max *Version, inclusive, ok bool) {
+	for _, r := range cs.admissibleRanges() {
+		if r.max == nil {
+			return nil, false, true
+		}
+		if !ok {
+			max, inclusive, ok = r.max, r.incMax, true
+		} else if n := r.max.Compare(max); n > 0 || n == 0 && r.incMax {
+			max, inclusive = r.max, r.incMax
+		}
+	}
+	return max, inclusive, ok
+}
+
+// admissibleRanges returns the ranges left in each || group after
+// intersecting the windows of its terms and removing its != terms. ^0.0.3
+// only admits 0.y.3 versions, so its range ends at the highest of them.
+func (cs Constraints) admissibleRanges() []versionRange {
+	var out []versionRange
+	for _, g := range cs.constraints {
+		ranges := []versionRange{{}}
+		for _, c := range g {
+			if cs.IncludePrerelease {
+				c = cs.prereleaseConstraint(c)
+			}
+
+			b := c.window()
+			if b.SamePatch {
+				b.Max, b.IncludeMax = newRelease([3]uint64{0, math.MaxUint64, b.Min.Patch()}), true
+			}
+
+			var next []versionRange
+			for _, r := range ranges {
+				if b.Excluded {
+					next = append(next, r.subtract(b)...)
+				} else if r = r.intersect(b); !r.empty() {
+					next = append(next, r)
+				}
+			}
+			ranges = next
+		}
+		out = append(out, ranges...)
+	}
+	return out
+}
+
+// versionRange is a range of versions built up from the ranges of Bounds. A
+// nil min or max is unbounded.
+type versionRange struct {
+	min, max       *Version
+	incMin, incMax bool
+}
+
+// intersect returns the part of r inside the range of b, ignoring whether b
+// is Excluded.
+func (r versionRange) intersect(b Bound) versionRange {
+	if b.Min != nil {
+		if r.min == nil {
+			r.min, r.incMin = b.Min, b.IncludeMin
+		} else if n := b.Min.Compare(r.min); n > 0 || n == 0 && !b.IncludeMin {
+			r.min, r.incMin = b.Min, b.IncludeMin
+		}
+	}
+	if b.Max != nil {
+		if r.max == nil {
+			r.max, r.incMax = b.Max, b.IncludeMax
+		} else if n := b.Max.Compare(r.max); n < 0 || n == 0 && !b.IncludeMax {
+			r.max, r.incMax = b.Max, b.IncludeMax
+		}
+	}
+	return r
+}
+
+// subtract returns the parts of r outside the range of b, of which there are
+// at most two.
+func (r versionRange) subtract(b Bound) []versionRange {
+	var out []versionRange
+	if b.Min != nil {
+		if l := r.intersect(Bound{Max: b.Min, IncludeMax: !b.IncludeMin}); !l.empty() {
+			out = append(out, l)
+		}
+	}
+	if b.Max != nil {
+		if h := r.intersect(Bound{Min: b.Max, IncludeMin: !b.IncludeMax}); !h.empty() {
+			out = append(out, h)
+		}
+	}
+	return out
+}
+
+func (r versionRange) empty() bool {
+	if r.min == nil || r.max == nil {
+		return false
+	}
+	n := r.min.Compare(r.max)
+	return n > 0 || n == 0 && !(r.incMin && r.incMax)
+}
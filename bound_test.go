@@ -0,0 +1,233 @@
+package semver
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+func TestVisit(t *testing.T) {
+	tests := []struct {
+		constraint string
+		expected   []string
+	}{
+		{"1.2.3", []string{"0 [1.2.3, 1.2.3]"}},
+		{"=1.2.x", []string{"0 [1.2.0, 1.3.0)"}},
+		{"*", []string{"0 (, )"}},
+		{"!=1.2.3", []string{"0 not [1.2.3, 1.2.3]"}},
+		{"!=1.x", []string{"0 not [1.0.0, 2.0.0)"}},
+		{"!=*", []string{"0 not [0.0.0, 0.0.0]"}},
+		{">1.2.3", []string{"0 (1.2.3, )"}},
+		{">1.2", []string{"0 [1.3.0, )"}},
+		{">1", []string{"0 [2.0.0, )"}},
+		{"<1.2", []string{"0 (, 1.2.0)"}},
+		{">=1.2.3-beta", []string{"0 [1.2.3-beta, )"}},
+		{"<=1.2.3", []string{"0 (, 1.2.3]"}},
+		{"<=1.2", []string{"0 (, 1.3.0)"}},
+		{"<=1", []string{"0 (, 2.0.0)"}},
+		{"~1.2.3", []string{"0 [1.2.3, 1.3.0)"}},
+		{"~1", []string{"0 [1.0.0, 2.0.0)"}},
+		{"~*", []string{"0 [0.0.0, )"}},
+		{"^1.2.3", []string{"0 [1.2.3, 2.0.0)"}},
+		{"^0.2.3", []string{"0 [0.2.3, 0.3.0)"}},
+		{"^0.0.3", []string{"0 [0.0.3, 1.0.0) patch 3"}},
+		{"^*", []string{"0 [0.0.0, 1.0.0) patch 0"}},
+		{"^0.0", []string{"0 [0.0.0, 0.1.0)"}},
+		{"1.2 - 2.3.4 || ^3", []string{"0 [1.2.0, )", "0 (, 2.3.4]", "1 [3.0.0, 4.0.0)"}},
+	}
+
+	for _, tc := range tests {
+		c, err := NewConstraint(tc.constraint)
+		if err != nil {
+			t.Fatalf("Error parsing constraint %q: %s", tc.constraint, err)
+		}
+
+		var got []string
+		c.Visit(func(group int, b Bound) {
+			got = append(got, string(rune('0'+group))+" "+formatBound(b))
+		})
+		if strings.Join(got, "; ") != strings.Join(tc.expected, "; ") {
+			t.Errorf("Expected %q to visit %q, got %q", tc.constraint, tc.expected, got)
+		}
+	}
+}
+
+func TestVisitReadOnly(t *testing.T) {
+	c, err := NewConstraint(">=1.2.3")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	c.Visit(func(_ int, b Bound) {
+		if err := b.Min.Set("5.0.0"); err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+	})
+	if !c.Check(MustParse("1.2.3")) {
+		t.Error("Changing a bound changed the constraint")
+	}
+}
+
+func TestVisitGenerated(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	opts := &GenerateOptions{MaxSegment: 3}
+
+	for i := 0; i < 500; i++ {
+		c := GenerateConstraint(r, opts)
+
+		var groups [][]Bound
+		c.Visit(func(group int, b Bound) {
+			if group == len(groups) {
+				groups = append(groups, nil)
+			}
+			groups[group] = append(groups[group], b)
+		})
+
+		for j := 0; j < 20; j++ {
+			v := GenerateVersion(r, opts)
+
+			in := false
+			for _, g := range groups {
+				all := true
+				for _, b := range g {
+					if !b.Admits(v) {
+						all = false
+					}
+				}
+				in = in || all
+			}
+
+			if a := c.Check(v); a != in {
+				t.Errorf("Expected %q check of %s to be %t from its bounds", c, v, in)
+			}
+		}
+	}
+}
+
+func TestAdmissible(t *testing.T) {
+	tests := []struct {
+		constraint string
+		includePre bool
+		min, max   string
+	}{
+		{">=1.2.3 <2.0.0", false, "[1.2.3", "2.0.0)"},
+		{">1.2.3", false, "(1.2.3", ")"},
+		{"<1.0 || >=2, <=3", false, "(", "4.0.0)"},
+		{"~1.2 || ^3.1", false, "[1.2.0", "4.0.0)"},
+		{"1.2.3 || 1.2.3 - 1.4", false, "[1.2.3", "1.5.0)"},
+		{">=1.0.0 <=1.0.1 !=1.0.0", false, "(1.0.0", "1.0.1]"},
+		{"^0.0.3", false, "[0.0.3", "0.18446744073709551615.3]"},
+		{"^0.0.3 <0.2", false, "[0.0.3", "0.2.0)"},
+		{"~3 !=3.0", false, "[3.1.0", "4.0.0)"},
+		{"~3 !=3.0", true, "[3.1.0-0", "4.0.0)"},
+		{"~3 !=3.1", true, "[3.0.0", "4.0.0)"},
+		{"~3 <3.2 !=3.1", true, "[3.0.0", "3.2.0)"},
+		{">=2 <1", false, "", ""},
+		{"1.2.3 !=1.2.3", false, "", ""},
+	}
+
+	for _, tc := range tests {
+		c, err := NewConstraint(tc.constraint)
+		if err != nil {
+			t.Fatalf("Error parsing constraint %q: %s", tc.constraint, err)
+		}
+		c.IncludePrerelease = tc.includePre
+
+		min, minInc, minOK := c.MinAdmissible()
+		max, maxInc, maxOK := c.MaxAdmissible()
+		if tc.min == "" {
+			if minOK || maxOK {
+				t.Errorf("Expected %q to admit nothing, got %v and %v", tc.constraint, min, max)
+			}
+			continue
+		}
+		if !minOK || !maxOK {
+			t.Errorf("Expected %q to admit versions", tc.constraint)
+			continue
+		}
+
+		got := "("
+		if minInc {
+			got = "["
+		}
+		if min != nil {
+			got += min.String()
+		}
+		if got != tc.min {
+			t.Errorf("Expected the lowest end of %q to be %q, got %q", tc.constraint, tc.min, got)
+		}
+
+		got = ")"
+		if maxInc {
+			got = "]"
+		}
+		if max != nil {
+			got = max.String() + got
+		}
+		if got != tc.max {
+			t.Errorf("Expected the highest end of %q to be %q, got %q", tc.constraint, tc.max, got)
+		}
+	}
+}
+
+func TestAdmissibleGenerated(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	opts := &GenerateOptions{MaxSegment: 3, Prerelease: 0.3}
+
+	for i := 0; i < 500; i++ {
+		c := GenerateConstraint(r, opts)
+		c.IncludePrerelease = r.Intn(2) == 0
+		min, minInc, ok := c.MinAdmissible()
+		max, maxInc, _ := c.MaxAdmissible()
+
+		for j := 0; j < 20; j++ {
+			v := GenerateVersion(r, opts)
+			if !c.Check(v) {
+				continue
+			}
+			if !ok {
+				t.Errorf("Expected %q to admit nothing, but it admits %s", c, v)
+				break
+			}
+			if min != nil {
+				if n := v.Compare(min); n < 0 || n == 0 && !minInc {
+					t.Errorf("Expected %s admitted by %q to be above its lowest end %s", v, c, min)
+				}
+			}
+			if max != nil {
+				if n := v.Compare(max); n > 0 || n == 0 && !maxInc {
+					t.Errorf("Expected %s admitted by %q to be below its highest end %s", v, c, max)
+				}
+			}
+		}
+	}
+}
+
+func formatBound(b Bound) string {
+	var s string
+	if b.Excluded {
+		s = "not "
+	}
+	if b.IncludeMin {
+		s += "["
+	} else {
+		s += "("
+	}
+	if b.Min != nil {
+		s += b.Min.String()
+	}
+	s += ", "
+	if b.Max != nil {
+		s += b.Max.String()
+	}
+	if b.IncludeMax {
+		s += "]"
+	} else {
+		s += ")"
+	}
+	if b.SamePatch {
+		s += fmt.Sprintf(" patch %d", b.Min.Patch())
+	}
+	return s
+}
@@ -0,0 +1,112 @@
+package semver
+
+import (
+	"strconv"
+)
+
+// Caret returns constraints admitting versions compatible with v as the ^
+// operator defines it. ^1.2.3 is >=1.2.3 <2.0.0, ^0.2.3 is >=0.2.3 <0.3.0
+// and ^0.0.3 is >=0.0.3 <0.0.4.
+func Caret(v *Version) *Constraints {
+	return newVersionConstraints(exactConstraint("^", v))
+}
+
+// Tilde returns constraints admitting patch releases of v as the ~ operator
+// defines it, so ~1.2.3 is >=1.2.3 <1.3.0.
+func Tilde(v *Version) *Constraints {
+	return newVersionConstraints(exactConstraint("~", v))
+}
+
+// AtLeast returns constraints admitting v and every higher version.
+func AtLeast(v *Version) *Constraints {
+	return newVersionConstraints(exactConstraint(">=", v))
+}
+
+// Before returns constraints admitting every version lower than v.
+func Before(v *Version) *Constraints {
+	return newVersionConstraints(exactConstraint("<", v))
+}
+
+// Between returns constraints admitting the versions between lo and hi.
+// inclLo and inclHi choose whether lo and hi themselves are admitted.
+func Between(lo, hi *Version, inclLo, inclHi bool) *Constraints {
+	loOp, hiOp := ">", "<"
+	if inclLo {
+		loOp = ">="
+	}
+	if inclHi {
+		hiOp = "<="
+	}
+	return newVersionConstraints(exactConstraint(loOp, lo), exactConstraint(hiOp, hi))
+}
+
+// Wildcard returns constraints admitting the versions of an x-range. A
+// negative number is a wildcard, so Wildcard(1, 2) is 1.2.x and admits
+// >=1.2.0 <1.3.0, Wildcard(1, -1) is 1.x and Wildcard(-1, -1) is x, which
+// admits every release. minor is ignored when major is a wildcard.
+func Wildcard(major, minor int) *Constraints {
+	cv := constraintVersion{major: "x", minor: "x", patch: "x"}
+	if major >= 0 {
+		cv.major = strconv.Itoa(major)
+		if minor >= 0 {
+			cv.minor = strconv.Itoa(minor)
+		}
+	}
+
+	switch {
+	case cv.major == "x":
+		cv.orig = "x"
+	case cv.minor == "x":
+		cv.orig = cv.major + ".x"
+	default:
+		cv.orig = cv.major + "." + cv.minor + ".x"
+	}
+
+	c, err := newConstraint("", cv)
+	if err != nil {
+		// Versions made from ints always parse.
+		panic(err)
+	}
+	return newVersionConstraints(c)
+}
+
+// Intersect returns constraints admitting the versions that both a and b
+// admit. Each || group of a is combined with each of b, so 1.2.x || 2.x
+// intersected with >=1.2.5 is 1.2.x >=1.2.5 || 2.x >=1.2.5, which admits
+// >=1.2.5 <1.3.0 and 2.x. The options of the result are those of a.
+func Intersect(a, b *Constraints) *Constraints {
+	out := &Constraints{
+		ExclusionsMatchMetadata: a.ExclusionsMatchMetadata,
+		EqualityMatchesMetadata: a.EqualityMatchesMetadata,
+		IncludePrerelease:       a.IncludePrerelease,
+		SnapPartialBounds:       a.SnapPartialBounds,
+	}
+
+	for _, x := range a.constraints {
+		for _, y := range b.constraints {
+			and := make([]*constraint, 0, len(x)+len(y))
+			and = append(and, x...)
+			and = append(and, y...)
+			out.constraints = append(out.constraints, and)
+		}
+	}
+
+	return out
+}
+
+// newVersionConstraints returns constraints with a single AND group.
+func newVersionConstraints(and ...*constraint) *Constraints {
+	return &Constraints{constraints: [][]*constraint{and}}
+}
+
+// exactConstraint returns the constraint for the operator op applied to all
+// of v, as though v had been written out in full.
+func exactConstraint(op string, v *Version) *constraint {
+	con := *v
+	con.original = v.String()
+	return &constraint{
+		con:      &con,
+		orig:     con.original,
+		origfunc: op,
+	}
+}
@@ -0,0 +1,139 @@
+package semver
+
+import (
+	"testing"
+)
+
+func TestBuilders(t *testing.T) {
+	tests := []struct {
+		c        *Constraints
+		expected string
+	}{
+		{Caret(MustParse("1.2.3")), "^1.2.3"},
+		{Caret(MustParse("v0.2.3")), "^0.2.3"},
+		{Tilde(MustParse("1.2.3-beta.1")), "~1.2.3-beta.1"},
+		{AtLeast(MustParse("1.2")), ">=1.2.0"},
+		{Before(MustParse("2.0.0")), "<2.0.0"},
+		{Between(MustParse("1.0.0"), MustParse("2.0.0"), true, false), ">=1.0.0 <2.0.0"},
+		{Between(MustParse("1.0.0"), MustParse("2.0.0"), false, true), ">1.0.0 <=2.0.0"},
+	}
+
+	for _, tc := range tests {
+		if s := tc.c.String(); s != tc.expected {
+			t.Errorf("Expected %q, got %q", tc.expected, s)
+		}
+
+		// The constraints must check the same as their parsed form.
+		p, err := NewConstraint(tc.expected)
+		if err != nil {
+			t.Errorf("Error parsing %q: %s", tc.expected, err)
+			continue
+		}
+		for _, s := range []string{"0.2.3", "0.2.9", "0.3.0", "1.0.0", "1.0.1", "1.2.0", "1.2.3-beta.1", "1.2.3-beta.2", "1.2.3", "1.2.9", "1.3.0", "1.9.9", "2.0.0-rc.1", "2.0.0"} {
+			v := MustParse(s)
+			if a, e := tc.c.Check(v), p.Check(v); a != e {
+				t.Errorf("Expected %q check of %s to be %t", tc.expected, s, e)
+			}
+		}
+	}
+}
+
+func TestCaretMajorZero(t *testing.T) {
+	tests := []struct {
+		base    string
+		version string
+		check   bool
+	}{
+		{"0.2.3", "0.2.9", true},
+		{"0.2.3", "0.3.0", false},
+		{"0.2.3", "0.2.2", false},
+		{"0.0.3", "0.0.3", true},
+		{"0.0.3", "0.0.4", false},
+		{"1.2.3", "1.9.0", true},
+		{"1.2.3", "2.0.0", false},
+	}
+
+	for _, tc := range tests {
+		c := Caret(MustParse(tc.base))
+		if a := c.Check(MustParse(tc.version)); a != tc.check {
+			t.Errorf("Expected ^%s check of %s to be %t", tc.base, tc.version, tc.check)
+		}
+	}
+}
+
+func TestWildcard(t *testing.T) {
+	tests := []struct {
+		c        *Constraints
+		expected string
+		admits   []string
+		rejects  []string
+	}{
+		{Wildcard(1, 2), "1.2.x", []string{"1.2.0", "1.2.9"}, []string{"1.1.9", "1.3.0", "1.2.3-beta"}},
+		{Wildcard(1, -1), "1.x", []string{"1.0.0", "1.9.9"}, []string{"0.9.9", "2.0.0"}},
+		{Wildcard(0, 0), "0.0.x", []string{"0.0.0", "0.0.9"}, []string{"0.1.0"}},
+		{Wildcard(-1, 3), "x", []string{"0.0.0", "1.2.3", "99.0.0"}, []string{"1.2.3-beta"}},
+	}
+
+	for _, tc := range tests {
+		if s := tc.c.String(); s != tc.expected {
+			t.Errorf("Expected %q, got %q", tc.expected, s)
+		}
+		for _, s := range tc.admits {
+			if !tc.c.Check(MustParse(s)) {
+				t.Errorf("Expected %q to admit %s", tc.expected, s)
+			}
+		}
+		for _, s := range tc.rejects {
+			if tc.c.Check(MustParse(s)) {
+				t.Errorf("Expected %q to reject %s", tc.expected, s)
+			}
+		}
+	}
+}
+
+func TestIntersect(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		expected string
+		admits   []string
+		rejects  []string
+	}{
+		{"1.2.x", ">=1.2.5", "1.2.x >=1.2.5", []string{"1.2.5", "1.2.9"}, []string{"1.2.4", "1.3.0"}},
+		{"1.2.x || 2.x", ">=1.2.5", "1.2.x >=1.2.5 || 2.x >=1.2.5", []string{"1.2.5", "2.1.0"}, []string{"1.2.4", "1.3.0", "3.0.0"}},
+		{"^1 || ^3", "<1.5 || >=3.2", "^1 <1.5 || ^1 >=3.2 || ^3 <1.5 || ^3 >=3.2", []string{"1.4.0", "3.2.0"}, []string{"1.5.0", "3.1.0", "2.0.0"}},
+	}
+
+	for _, tc := range tests {
+		a, err := NewConstraint(tc.a)
+		if err != nil {
+			t.Fatalf("Error parsing constraint %q: %s", tc.a, err)
+		}
+		b, err := NewConstraint(tc.b)
+		if err != nil {
+			t.Fatalf("Error parsing constraint %q: %s", tc.b, err)
+		}
+
+		c := Intersect(a, b)
+		if s := c.String(); s != tc.expected {
+			t.Errorf("Expected %q, got %q", tc.expected, s)
+		}
+		for _, s := range tc.admits {
+			if !c.Check(MustParse(s)) {
+				t.Errorf("Expected %q to admit %s", tc.expected, s)
+			}
+		}
+		for _, s := range tc.rejects {
+			if c.Check(MustParse(s)) {
+				t.Errorf("Expected %q to reject %s", tc.expected, s)
+			}
+		}
+	}
+
+	i := Intersect(Wildcard(1, 2), AtLeast(MustParse("1.2.5")))
+	for _, s := range []string{"1.2.4", "1.2.5", "1.2.18446744073709551615", "1.3.0"} {
+		v := MustParse(s)
+		if a, e := i.Check(v), v.Compare(MustParse("1.2.5")) >= 0 && v.LessThan(MustParse("1.3.0")); a != e {
+			t.Errorf("Expected 1.2.x and >=1.2.5 check of %s to be %t", s, e)
+		}
+	}
+}
@@ -0,0 +1,29 @@
+package semver
+
+import (
+	"errors"
+	"runtime/debug"
+)
+
+// ErrNoBuildVersion is returned by FromBuildInfo when the build information
+// does not record a version for the main module. This is the case for
+// binaries built from a local checkout, which report "(devel)".
+var ErrNoBuildVersion = errors.New("Build information has no module version")
+
+// FromBuildInfo returns the version of the main module recorded in the build
+// information, such as the result of debug.ReadBuildInfo. Pseudo-versions,
+// such as v0.0.0-20191109021931-daa7c04131f5, are parsed with the timestamp
+// and commit as the prerelease. ErrNoBuildVersion is returned when the main
+// module has no version.
+func FromBuildInfo(info *debug.BuildInfo) (*Version, error) {
+	if info == nil {
+		return nil, ErrNoBuildVersion
+	}
+
+	switch info.Main.Version {
+	case "", "(devel)":
+		return nil, ErrNoBuildVersion
+	}
+
+	return NewVersion(info.Main.Version)
+}
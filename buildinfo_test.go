@@ -0,0 +1,46 @@
+package semver
+
+import (
+	"runtime/debug"
+	"testing"
+)
+
+func TestFromBuildInfo(t *testing.T) {
+	tests := []struct {
+		version  string
+		expected string
+		err      error
+	}{
+		{"v1.2.3", "1.2.3", nil},
+		{"v0.0.0-20191109021931-daa7c04131f5", "0.0.0-20191109021931-daa7c04131f5", nil},
+		{"v1.2.4-0.20191109021931-daa7c04131f5", "1.2.4-0.20191109021931-daa7c04131f5", nil},
+		{"v2.0.0+incompatible", "2.0.0+incompatible", nil},
+		{"(devel)", "", ErrNoBuildVersion},
+		{"", "", ErrNoBuildVersion},
+	}
+
+	for _, tc := range tests {
+		info := &debug.BuildInfo{}
+		info.Main.Version = tc.version
+
+		v, err := FromBuildInfo(info)
+		if err != tc.err {
+			t.Errorf("Expected error %v for %q, got %v", tc.err, tc.version, err)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+
+		if v.String() != tc.expected {
+			t.Errorf("Expected %q to parse as %q, got %q", tc.version, tc.expected, v)
+		}
+		if v.Original() != tc.version {
+			t.Errorf("Expected original %q, got %q", tc.version, v.Original())
+		}
+	}
+
+	if _, err := FromBuildInfo(nil); err != ErrNoBuildVersion {
+		t.Errorf("Expected ErrNoBuildVersion for nil build info, got %v", err)
+	}
+}
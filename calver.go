@@ -0,0 +1,115 @@
+package semver
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// calverToken describes a field of a calendar version format.
+type calverToken struct {
+	// width is the exact number of digits of a zero padded field, or 0 for a
+	// field written without leading zeros.
+	width int
+
+	// min and max bound the value. A max of 0 leaves it unbounded.
+	min, max uint64
+}
+
+// calverTokens are the fields of the formats described at
+// https://calver.org.
+var calverTokens = map[string]calverToken{
+	"YYYY":  {min: 1},
+	"YY":    {},
+	"0Y":    {width: 2},
+	"MM":    {min: 1, max: 12},
+	"0M":    {width: 2, min: 1, max: 12},
+	"WW":    {min: 1, max: 53},
+	"0W":    {width: 2, min: 1, max: 53},
+	"DD":    {min: 1, max: 31},
+	"0D":    {width: 2, min: 1, max: 31},
+	"MAJOR": {},
+	"MINOR": {},
+	"MICRO": {},
+}
+
+// ParseCalVer parses a calendar version written in the given format, such as
+// "YYYY.0M.MICRO" for 2024.01.3 or "YY.0M" for Ubuntu's 22.04. The format is
+// one to three dot separated fields from https://calver.org: YYYY, YY and 0Y
+// for the year, MM and 0M for the month, WW and 0W for the week, DD and 0D
+// for the day, and MAJOR, MINOR and MICRO. Fields starting with 0 are zero
+// padded to two digits. Like other versions, a calendar version may have a
+// prerelease and build metadata, as in 2024.01.3-rc.1+build.5.
+//
+// The fields become the major, minor, and patch numbers of the version, with
+// missing ones set to 0, so 22.04 is 22.4.0 and Original returns 22.04. The
+// version then compares, sorts and checks against constraints like any
+// other, and constraints on calendar versions are written with the same
+// numbers, such as >=22.4 <24.4. Calendar and semantic versions can be mixed
+// in a Collection, where they are ordered by these numbers, so 2024.01 sorts
+// after 3.0.0 and 22.04 between 3.0.0 and 2024.01.
+func ParseCalVer(format, v string) (*Version, error) {
+	tokens := strings.Split(format, ".")
+	if len(tokens) > 3 {
+		return nil, fmt.Errorf("Invalid calendar version format %q: at most 3 fields are supported", format)
+	}
+	for _, t := range tokens {
+		if _, ok := calverTokens[t]; !ok {
+			return nil, fmt.Errorf("Invalid calendar version format %q: unknown field %q", format, t)
+		}
+	}
+
+	if MaxVersionLength > 0 && len(v) > MaxVersionLength {
+		return nil, ErrVersionTooLong
+	}
+
+	s, rest := v, ""
+	if i := strings.IndexAny(s, "-+"); i != -1 {
+		s, rest = s[:i], s[i:]
+	}
+
+	segs := strings.Split(s, ".")
+	if len(segs) != len(tokens) {
+		return nil, fmt.Errorf("Calendar version %q does not match format %q", v, format)
+	}
+
+	var nums [3]uint64
+	for i, seg := range segs {
+		n, err := parseCalverField(calverTokens[tokens[i]], seg)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid %s field %q in calendar version %q: %s", tokens[i], seg, v, err)
+		}
+		nums[i] = n
+	}
+
+	sv, err := StrictNewVersion(fmt.Sprintf("%d.%d.%d%s", nums[0], nums[1], nums[2], rest))
+	if err != nil {
+		return nil, err
+	}
+	sv.original = v
+
+	return sv, nil
+}
+
+// parseCalverField parses the value of a single field of a calendar version.
+func parseCalverField(t calverToken, s string) (uint64, error) {
+	if s == "" || !containsOnly(s, num) {
+		return 0, ErrInvalidCharacters
+	}
+	if t.width > 0 && len(s) != t.width {
+		return 0, fmt.Errorf("expected %d digits", t.width)
+	}
+	if t.width == 0 && len(s) > 1 && s[0] == '0' {
+		return 0, ErrSegmentStartsZero
+	}
+
+	n, err := strconv.ParseUint(s, 10, 64)
+	if err != nil {
+		return 0, ErrSegmentOverflow
+	}
+	if n < t.min || t.max > 0 && n > t.max {
+		return 0, fmt.Errorf("out of range")
+	}
+
+	return n, nil
+}
@@ -0,0 +1,99 @@
+package semver
+
+import (
+	"sort"
+	"testing"
+)
+
+func TestParseCalVer(t *testing.T) {
+	tests := []struct {
+		format   string
+		version  string
+		expected string
+		err      bool
+	}{
+		{"YYYY.0M.MICRO", "2024.01.3", "2024.1.3", false},
+		{"YYYY.0M.MICRO", "2024.1.3", "", true},
+		{"YYYY.0M.MICRO", "2024.13.3", "", true},
+		{"YYYY.0M.MICRO", "2024.01.03", "", true},
+		{"YYYY.0M.MICRO", "2024.01", "", true},
+		{"YYYY.0M.MICRO", "2024.01.3-rc.1+build.5", "2024.1.3-rc.1+build.5", false},
+		{"YYYY.0M.MICRO", "2024.01.3-", "", true},
+		{"YY.0M", "22.04", "22.4.0", false},
+		{"YY.0M", "v22.04", "", true},
+		{"0Y.MM.DD", "06.2.29", "6.2.29", false},
+		{"0Y.MM.DD", "6.2.29", "", true},
+		{"YY.MM.DD", "16.2.32", "", true},
+		{"YY.MM.DD", "16.0.1", "", true},
+		{"YYYY.0W", "2024.53", "2024.53.0", false},
+		{"YYYY.0W", "2024.54", "", true},
+		{"YYYY.MINOR.MICRO", "2024.0.0", "2024.0.0", false},
+		{"YYYY", "2024", "2024.0.0", false},
+		{"YYYY", "0", "", true},
+		{"YYYY.MM", "2024.1x", "", true},
+		{"YYYY.MM.DD.MICRO", "2024.1.1.1", "", true},
+		{"YYYY.MONTH", "2024.1", "", true},
+	}
+
+	for _, tc := range tests {
+		v, err := ParseCalVer(tc.format, tc.version)
+		if tc.err {
+			if err == nil {
+				t.Errorf("Expected error parsing %q as %s, got %s", tc.version, tc.format, v)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Error parsing %q as %s: %s", tc.version, tc.format, err)
+			continue
+		}
+
+		if v.String() != tc.expected {
+			t.Errorf("Expected %q as %s to be %s, got %s", tc.version, tc.format, tc.expected, v)
+		}
+		if v.Original() != tc.version {
+			t.Errorf("Expected original %q, got %q", tc.version, v.Original())
+		}
+	}
+}
+
+func TestCalVerOrdering(t *testing.T) {
+	versions := []struct {
+		format, version string
+	}{
+		{"YYYY.0M", "2024.01"},
+		{"YY.0M", "22.04"},
+		{"YY.0M", "20.10"},
+		{"YYYY.0M", "2023.12"},
+	}
+
+	var vs Collection
+	for _, tc := range versions {
+		v, err := ParseCalVer(tc.format, tc.version)
+		if err != nil {
+			t.Fatalf("Error parsing %q: %s", tc.version, err)
+		}
+		vs = append(vs, v)
+	}
+	vs = append(vs, MustParse("3.0.0"))
+	sort.Sort(vs)
+
+	expected := []string{"3.0.0", "20.10", "22.04", "2023.12", "2024.01"}
+	for i, v := range vs {
+		if v.Original() != expected[i] {
+			t.Errorf("Expected %s at %d, got %s", expected[i], i, v.Original())
+		}
+	}
+
+	c, err := NewConstraint(">=22.4 <24.4")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	jammy, err := ParseCalVer("YY.0M", "22.04")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if !c.Check(jammy) {
+		t.Errorf("Expected %q to admit 22.04", c)
+	}
+}
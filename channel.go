@@ -0,0 +1,81 @@
+package semver
+
+import (
+	"sort"
+)
+
+// Channel is a release channel, such as alpha, beta, or rc, named by the
+// tag that the pre-releases on it start with. Builds are promoted from one
+// channel to the next until they are released, so a channel includes every
+// release as well as its own pre-releases. The empty channel, ChannelStable,
+// only includes releases. Tags are case sensitive.
+type Channel string
+
+// ChannelStable is the channel of releases alone.
+const ChannelStable Channel = ""
+
+// Check reports whether v is on the channel, either as a release or as a
+// pre-release whose PrereleaseTag is the channel.
+func (ch Channel) Check(v *Version) bool {
+	return v.pre == "" || ch != ChannelStable && v.PrereleaseTag() == string(ch)
+}
+
+// Filter returns the versions on the channel, in the order they appear in vs.
+func (ch Channel) Filter(vs Collection) Collection {
+	var out Collection
+	for _, v := range vs {
+		if ch.Check(v) {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+// Channels returns the channels of the pre-releases in the collection, sorted
+// and without duplicates. ChannelStable is not included.
+func (c Collection) Channels() []Channel {
+	seen := make(map[Channel]bool)
+	var out []Channel
+	for _, v := range c {
+		if v.pre == "" {
+			continue
+		}
+		if ch := Channel(v.PrereleaseTag()); !seen[ch] {
+			seen[ch] = true
+			out = append(out, ch)
+		}
+	}
+
+	sort.Slice(out, func(i, j int) bool { return out[i] < out[j] })
+	return out
+}
+
+// LatestOn returns the highest version in the collection that is on the
+// channel and satisfies the constraints, such as the newest beta or release
+// of 2.x. cs may be nil to accept any version on the channel. The constraints
+// are checked with IncludePrerelease set so that pre-releases on the channel
+// are considered. The collection does not need to be sorted.
+// ErrNoSatisfyingVersion is returned when no version qualifies.
+func (c Collection) LatestOn(ch Channel, cs *Constraints) (*Version, error) {
+	var m *Matcher
+	if cs != nil {
+		pre := *cs
+		pre.IncludePrerelease = true
+		m = pre.Compile()
+	}
+
+	var latest *Version
+	for _, v := range c {
+		if !ch.Check(v) || m != nil && !m.Check(v) {
+			continue
+		}
+		if latest == nil || v.GreaterThan(latest) {
+			latest = v
+		}
+	}
+
+	if latest == nil {
+		return nil, ErrNoSatisfyingVersion
+	}
+	return latest, nil
+}
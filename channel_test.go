@@ -0,0 +1,102 @@
+package semver
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPrereleaseTag(t *testing.T) {
+	tests := map[string]string{
+		"1.2.3":            "",
+		"1.2.3+build.1":    "",
+		"1.2.3-beta":       "beta",
+		"1.2.3-rc.1":       "rc",
+		"1.2.3-alpha.1.2":  "alpha",
+		"1.2.3-0.3":        "0",
+		"1.2.3-x-y.1+meta": "x-y",
+	}
+
+	for s, e := range tests {
+		if a := MustParse(s).PrereleaseTag(); a != e {
+			t.Errorf("Expected tag of %s to be %q, got %q", s, e, a)
+		}
+	}
+}
+
+func TestChannel(t *testing.T) {
+	vs := Collection{}
+	for _, s := range []string{"1.0.0", "1.1.0-alpha.1", "1.1.0-beta.1", "1.1.0-beta.2", "1.1.0-rc.1", "1.1.0", "2.0.0-alpha.1", "2.0.0-Beta.1"} {
+		vs = append(vs, MustParse(s))
+	}
+
+	tests := []struct {
+		ch       Channel
+		expected []string
+	}{
+		{ChannelStable, []string{"1.0.0", "1.1.0"}},
+		{"alpha", []string{"1.0.0", "1.1.0-alpha.1", "1.1.0", "2.0.0-alpha.1"}},
+		{"beta", []string{"1.0.0", "1.1.0-beta.1", "1.1.0-beta.2", "1.1.0"}},
+		{"nightly", []string{"1.0.0", "1.1.0"}},
+	}
+
+	for _, tc := range tests {
+		var got []string
+		for _, v := range tc.ch.Filter(vs) {
+			got = append(got, v.String())
+		}
+		if !reflect.DeepEqual(got, tc.expected) {
+			t.Errorf("Expected channel %q to have %q, got %q", tc.ch, tc.expected, got)
+		}
+	}
+
+	if chs := vs.Channels(); !reflect.DeepEqual(chs, []Channel{"Beta", "alpha", "beta", "rc"}) {
+		t.Errorf("Unexpected channels %q", chs)
+	}
+}
+
+func TestLatestOn(t *testing.T) {
+	vs := Collection{}
+	for _, s := range []string{"2.0.0-beta.2", "1.0.0", "1.1.0-beta.1", "1.1.0-rc.1", "2.0.0-alpha.1", "1.0.1"} {
+		vs = append(vs, MustParse(s))
+	}
+
+	c, err := NewConstraint("^1")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	tests := []struct {
+		ch       Channel
+		cs       *Constraints
+		expected string
+	}{
+		{"beta", nil, "2.0.0-beta.2"},
+		{"beta", c, "1.1.0-beta.1"},
+		{"rc", c, "1.1.0-rc.1"},
+		{"alpha", c, "1.0.1"},
+		{ChannelStable, nil, "1.0.1"},
+	}
+
+	for _, tc := range tests {
+		v, err := vs.LatestOn(tc.ch, tc.cs)
+		if err != nil {
+			t.Errorf("Unexpected error for channel %q: %s", tc.ch, err)
+			continue
+		}
+		if v.String() != tc.expected {
+			t.Errorf("Expected latest on %q to be %s, got %s", tc.ch, tc.expected, v)
+		}
+	}
+
+	if c.IncludePrerelease {
+		t.Error("Expected LatestOn to leave the constraints unchanged")
+	}
+
+	c, err = NewConstraint(">=3")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if _, err := vs.LatestOn("beta", c); err != ErrNoSatisfyingVersion {
+		t.Errorf("Expected ErrNoSatisfyingVersion, got %v", err)
+	}
+}
@@ -1,5 +1,10 @@
 package semver
 
+import (
+	"errors"
+	"sort"
+)
+
 // Collection is a collection of Version instances and implements the sort
 // interface. See the sort package for more details.
 // https://golang.org/pkg/sort/
@@ -22,3 +27,46 @@ func (c Collection) Less(i, j int) bool {
 func (c Collection) Swap(i, j int) {
 	c[i], c[j] = c[j], c[i]
 }
+
+// Insert adds v to a sorted collection, keeping it sorted, and returns the
+// result. v is placed after any versions with equal precedence. The
+// collection's backing array is reused when it has room, as with append.
+func (c Collection) Insert(v *Version) Collection {
+	i := sort.Search(len(c), func(i int) bool { return c[i].GreaterThan(v) })
+	c = append(c, nil)
+	copy(c[i+1:], c[i:])
+	c[i] = v
+	return c
+}
+
+// ErrNoSatisfyingVersion is returned when no version in a collection
+// satisfies the constraints.
+var ErrNoSatisfyingVersion = errors.New("No version satisfies the constraints")
+
+// Filter returns the versions that satisfy the constraints, in the order they
+// appear in the collection.
+func (c Collection) Filter(cs *Constraints) Collection {
+	var out Collection
+	for _, v := range c {
+		if cs.Check(v) {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+// Latest returns the highest version in the collection that satisfies the
+// constraints. The collection does not need to be sorted. When versions have
+// equal precedence the first one is returned. ErrNoSatisfyingVersion is
+// returned when none satisfy them. It is MaxSatisfying without options.
+func (c Collection) Latest(cs *Constraints) (*Version, error) {
+	return MaxSatisfying(c, cs)
+}
+
+// Oldest returns the lowest version in the collection that satisfies the
+// constraints. The collection does not need to be sorted. When versions have
+// equal precedence the first one is returned. ErrNoSatisfyingVersion is
+// returned when none satisfy them. It is MinSatisfying without options.
+func (c Collection) Oldest(cs *Constraints) (*Version, error) {
+	return MinSatisfying(c, cs)
+}
@@ -44,3 +44,73 @@ func TestCollection(t *testing.T) {
 		t.Error("Sorting Collection failed")
 	}
 }
+
+func TestCollectionFilter(t *testing.T) {
+	c := Collection{
+		MustParse("1.4.0"),
+		MustParse("2.0.0"),
+		MustParse("1.2.0"),
+		MustParse("1.9.0-beta"),
+		MustParse("1.9.0"),
+		MustParse("v1.9.0"),
+	}
+
+	tests := []struct {
+		constraint string
+		filtered   []string
+		latest     string
+		oldest     string
+	}{
+		{"^1.2", []string{"1.4.0", "1.2.0", "1.9.0", "v1.9.0"}, "1.9.0", "1.2.0"},
+		{">=1.9.0-0", []string{"2.0.0", "1.9.0-beta", "1.9.0", "v1.9.0"}, "2.0.0", "1.9.0-beta"},
+		{"^3", nil, "", ""},
+	}
+
+	for _, tc := range tests {
+		cs, err := NewConstraint(tc.constraint)
+		if err != nil {
+			t.Errorf("Error parsing constraint %q: %s", tc.constraint, err)
+			continue
+		}
+
+		f := c.Filter(cs)
+		var got []string
+		for _, v := range f {
+			got = append(got, v.Original())
+		}
+		if !reflect.DeepEqual(got, tc.filtered) {
+			t.Errorf("Expected %q to filter to %v, got %v", tc.constraint, tc.filtered, got)
+		}
+
+		latest, err := c.Latest(cs)
+		oldest, oerr := c.Oldest(cs)
+		if tc.latest == "" {
+			if err != ErrNoSatisfyingVersion || oerr != ErrNoSatisfyingVersion {
+				t.Errorf("Expected ErrNoSatisfyingVersion for %q, got %v and %v", tc.constraint, err, oerr)
+			}
+			continue
+		}
+		if err != nil || latest.Original() != tc.latest {
+			t.Errorf("Expected the latest for %q to be %q, got %v (%v)", tc.constraint, tc.latest, latest, err)
+		}
+		if oerr != nil || oldest.Original() != tc.oldest {
+			t.Errorf("Expected the oldest for %q to be %q, got %v (%v)", tc.constraint, tc.oldest, oldest, oerr)
+		}
+	}
+}
+
+func TestCollectionInsert(t *testing.T) {
+	var c Collection
+	for _, v := range []string{"1.2.0", "1.0.0", "2.0.0-rc.1", "1.2.0+b", "0.9.0", "2.0.0"} {
+		c = c.Insert(MustParse(v))
+	}
+
+	var got []string
+	for _, v := range c {
+		got = append(got, v.Original())
+	}
+	e := []string{"0.9.0", "1.0.0", "1.2.0", "1.2.0+b", "2.0.0-rc.1", "2.0.0"}
+	if !reflect.DeepEqual(got, e) {
+		t.Errorf("Expected %v, got %v", e, got)
+	}
+}
@@ -2,54 +2,74 @@ package semver
 
 import (
 	"bytes"
+	"database/sql/driver"
+	"encoding/json"
 	"errors"
 	"fmt"
-	"regexp"
 	"strings"
 )
 
+var (
+	// ErrConstraintTooLong is returned when a constraint string is longer than
+	// MaxConstraintLength.
+	ErrConstraintTooLong = errors.New("constraint string too long")
+
+	// ErrTooManyConstraintGroups is returned when a constraint string has more
+	// || separated groups than MaxConstraintGroups.
+	ErrTooManyConstraintGroups = errors.New("too many || groups in constraint")
+)
+
+// Limits on the constraint strings NewConstraint accepts. See the limits on
+// version strings for details. A limit of 0 or less disables it, and both are
+// disabled by default. 4096 and 128 are reasonable values for untrusted
+// input.
+var (
+	// MaxConstraintLength is the maximum length in bytes of a constraint
+	// string.
+	MaxConstraintLength = 0
+
+	// MaxConstraintGroups is the maximum number of || separated groups in a
+	// constraint string.
+	MaxConstraintGroups = 0
+)
+
 // Constraints is one or more constraint that a semantic version can be
 // checked against.
 type Constraints struct {
 	constraints [][]*constraint
+
+	// ExclusionsMatchMetadata makes != constraints on an exact version with
+	// build metadata, such as !=1.2.3+broken.build, only exclude versions
+	// with that same metadata. By default build metadata is ignored, per the
+	// spec, so such a constraint excludes every 1.2.3.
+	ExclusionsMatchMetadata bool
+
+	// EqualityMatchesMetadata makes = constraints on an exact version with
+	// build metadata, such as =1.2.3+linux, only admit versions with that
+	// same metadata. By default build metadata is ignored, per the spec, so
+	// such a constraint admits 1.2.3+darwin too.
+	EqualityMatchesMetadata bool
+
+	// IncludePrerelease makes prerelease versions satisfy constraints by
+	// precedence alone, like any other version. By default a prerelease only
+	// satisfies a constraint whose version also has a prerelease, so
+	// >=1.0.0 does not admit 2.0.0-alpha.1. Ranges such as ~1.2.3 keep their
+	// upper bound, so 1.3.0-beta is still not admitted by it.
+	IncludePrerelease bool
+
+	// SnapPartialBounds makes a partial version in a < or >= comparison, such
+	// as <2 or >=1.2, stand for the lowest prerelease of that version, 2.0.0-0
+	// or 1.2.0-0, rather than the release. <2 then excludes 2.0.0-rc.1 and
+	// >=1.2 admits 1.2.0-beta. It only has an effect with IncludePrerelease as
+	// otherwise neither comparison admits prereleases.
+	SnapPartialBounds bool
 }
 
 // NewConstraint returns a Constraints instance that a Version instance can
-// be checked against. If there is a parse error it will be returned.
+// be checked against. If there is a parse error it will be returned. See the
+// grammar documented on constraintParser for the accepted syntax.
 func NewConstraint(c string) (*Constraints, error) {
-
-	// Rewrite - ranges into a comparison operation.
-	c = rewriteRange(c)
-
-	ors := strings.Split(c, "||")
-	or := make([][]*constraint, len(ors))
-	for k, v := range ors {
-
-		// TODO: Find a way to validate and fetch all the constraints in a simpler form
-
-		// Validate the segment
-		if !validConstraintRegex.MatchString(v) {
-			return nil, fmt.Errorf("improper constraint: %s", v)
-		}
-
-		cs := findConstraintRegex.FindAllString(v, -1)
-		if cs == nil {
-			cs = append(cs, v)
-		}
-		result := make([]*constraint, len(cs))
-		for i, s := range cs {
-			pc, err := parseConstraint(s)
-			if err != nil {
-				return nil, err
-			}
-
-			result[i] = pc
-		}
-		or[k] = result
-	}
-
-	o := &Constraints{constraints: or}
-	return o, nil
+	return ParseConstraintIn(c, DialectDefault)
 }
 
 // Check tests if a version satisfies the constraints.
@@ -60,7 +80,7 @@ func (cs Constraints) Check(v *Version) bool {
 	for _, o := range cs.constraints {
 		joy := true
 		for _, c := range o {
-			if check, _ := c.check(v); !check {
+			if check, _ := cs.check(c, v); !check {
 				joy = false
 				break
 			}
@@ -75,7 +95,8 @@ func (cs Constraints) Check(v *Version) bool {
 }
 
 // Validate checks if a version satisfies a constraint. If not a slice of
-// reasons for the failure are returned in addition to a bool.
+// reasons for the failure are returned in addition to a bool. Each reason is
+// a *ConstraintError.
 func (cs Constraints) Validate(v *Version) (bool, []error) {
 	// loop over the ORs and check the inner ANDs
 	var e []error
@@ -88,18 +109,17 @@ func (cs Constraints) Validate(v *Version) (bool, []error) {
 		for _, c := range o {
 			// Before running the check handle the case there the version is
 			// a prerelease and the check is not searching for prereleases.
-			if c.con.pre == "" && v.pre != "" {
+			if !cs.IncludePrerelease && c.con.pre == "" && v.pre != "" {
 				if !prerelesase {
-					em := fmt.Errorf("%s is a prerelease version and the constraint is only looking for release versions", v)
-					e = append(e, em)
+					e = append(e, ReasonPrerelease.err(v, c))
 					prerelesase = true
 				}
 				joy = false
 
 			} else {
 
-				if _, err := c.check(v); err != nil {
-					e = append(e, err)
+				if _, r := cs.check(c, v); r != reasonNone {
+					e = append(e, r.err(v, c))
 					joy = false
 				}
 			}
@@ -113,6 +133,38 @@ func (cs Constraints) Validate(v *Version) (bool, []error) {
 	return false, e
 }
 
+// check tests a single constraint taking the options on cs into account.
+func (cs Constraints) check(c *constraint, v *Version) (bool, Reason) {
+	if cs.IncludePrerelease && v.pre != "" && c.con.pre == "" {
+		c = cs.prereleaseConstraint(c)
+	}
+
+	if cs.ExclusionsMatchMetadata && c.origfunc == "!=" && !c.dirty && c.con.metadata != "" {
+		return constraintNotEqualMetadata(v, c)
+	}
+	if cs.EqualityMatchesMetadata && isEqualityOp(c.origfunc) && !c.dirty && c.con.metadata != "" {
+		return constraintEqualMetadata(v, c)
+	}
+
+	return constraintOps[c.origfunc](v, c)
+}
+
+// prereleaseConstraint returns a copy of c for checking prerelease versions by
+// precedence alone when IncludePrerelease is set.
+func (cs Constraints) prereleaseConstraint(c *constraint) *constraint {
+	cc := *c
+	cc.includePrerelease = true
+	if cs.SnapPartialBounds && c.dirty {
+		switch c.origfunc {
+		case "<", ">=", "=>":
+			con := *c.con
+			con.pre = "0"
+			cc.con = &con
+		}
+	}
+	return &cc
+}
+
 func (cs Constraints) String() string {
 	buf := make([]string, len(cs.constraints))
 	var tmp bytes.Buffer
@@ -134,19 +186,157 @@ func (cs Constraints) String() string {
 	return strings.Join(buf, " || ")
 }
 
-var constraintOps map[string]cfunc
-var constraintRegex *regexp.Regexp
-var constraintRangeRegex *regexp.Regexp
+// constraintsJSON is the JSON structure of Constraints. See MarshalJSON.
+type constraintsJSON struct {
+	AnyOf                   [][]constraintJSON `json:"anyOf"`
+	ExclusionsMatchMetadata bool               `json:"exclusionsMatchMetadata,omitempty"`
+	EqualityMatchesMetadata bool               `json:"equalityMatchesMetadata,omitempty"`
+	IncludePrerelease       bool               `json:"includePrerelease,omitempty"`
+	SnapPartialBounds       bool               `json:"snapPartialBounds,omitempty"`
+}
 
-// Used to find individual constraints within a multi-constraint string
-var findConstraintRegex *regexp.Regexp
+type constraintJSON struct {
+	Op      string `json:"op"`
+	Version string `json:"version"`
+}
+
+// MarshalJSON implements the json.Marshaler interface. Constraints are
+// encoded as a structure rather than a string so they can be stored and
+// edited without the constraint grammar. The structure is
+//
+//	{
+//	  "anyOf": [
+//	    [{"op": ">=", "version": "1.2.3"}, {"op": "!=", "version": "1.4.x"}],
+//	    [{"op": "^", "version": "3"}]
+//	  ],
+//	  "exclusionsMatchMetadata": true
+//	}
+//
+// where a version satisfies the constraints if it satisfies every term of
+// any of the groups in anyOf. Each term has one of the operators accepted by
+// NewConstraint, with "" meaning equality, and a version that may contain
+// wildcards. Hyphen ranges are stored as a >= and a <= term. The options
+// exclusionsMatchMetadata, equalityMatchesMetadata, includePrerelease, and
+// snapPartialBounds are omitted when false.
+func (cs Constraints) MarshalJSON() ([]byte, error) {
+	j := constraintsJSON{
+		AnyOf:                   make([][]constraintJSON, len(cs.constraints)),
+		ExclusionsMatchMetadata: cs.ExclusionsMatchMetadata,
+		EqualityMatchesMetadata: cs.EqualityMatchesMetadata,
+		IncludePrerelease:       cs.IncludePrerelease,
+		SnapPartialBounds:       cs.SnapPartialBounds,
+	}
 
-// Used to validate an segment of ANDs is valid
-var validConstraintRegex *regexp.Regexp
+	for k, v := range cs.constraints {
+		j.AnyOf[k] = make([]constraintJSON, len(v))
+		for kk, c := range v {
+			j.AnyOf[k][kk] = constraintJSON{Op: c.origfunc, Version: c.orig}
+		}
+	}
+
+	// Operators such as >= are left unescaped so the JSON stays readable.
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(j); err != nil {
+		return nil, err
+	}
+
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
 
-const cvRegex string = `v?([0-9|x|X|\*]+)(\.[0-9|x|X|\*]+)?(\.[0-9|x|X|\*]+)?` +
-	`(-([0-9A-Za-z\-]+(\.[0-9A-Za-z\-]+)*))?` +
-	`(\+([0-9A-Za-z\-]+(\.[0-9A-Za-z\-]+)*))?`
+// UnmarshalJSON implements the json.Unmarshaler interface, decoding the
+// structure documented on MarshalJSON.
+func (cs *Constraints) UnmarshalJSON(b []byte) error {
+	var j constraintsJSON
+	if err := json.Unmarshal(b, &j); err != nil {
+		return err
+	}
+
+	if len(j.AnyOf) == 0 {
+		return errors.New("constraints must have at least one group")
+	}
+	if MaxConstraintGroups > 0 && len(j.AnyOf) > MaxConstraintGroups {
+		return ErrTooManyConstraintGroups
+	}
+
+	or := make([][]*constraint, len(j.AnyOf))
+	for k, v := range j.AnyOf {
+		if len(v) == 0 {
+			return errors.New("constraint group must have at least one term")
+		}
+
+		or[k] = make([]*constraint, len(v))
+		for kk, t := range v {
+			if _, ok := constraintOps[t.Op]; !ok {
+				return fmt.Errorf("unknown constraint operator: %q", t.Op)
+			}
+
+			c, err := parseConstraint(t.Op + t.Version)
+			if err != nil {
+				return err
+			}
+			// A version such as ">1.2.3" must not change the operator.
+			if c.origfunc != t.Op || c.orig != t.Version {
+				return fmt.Errorf("improper constraint: %s%s", t.Op, t.Version)
+			}
+			or[k][kk] = c
+		}
+	}
+
+	cs.constraints = or
+	cs.ExclusionsMatchMetadata = j.ExclusionsMatchMetadata
+	cs.EqualityMatchesMetadata = j.EqualityMatchesMetadata
+	cs.IncludePrerelease = j.IncludePrerelease
+	cs.SnapPartialBounds = j.SnapPartialBounds
+
+	return nil
+}
+
+// Scan implements the SQL.Scanner interface. The value must be a constraint
+// string, as written by Value, and the options set on cs are kept.
+func (cs *Constraints) Scan(value interface{}) error {
+	var s string
+	switch t := value.(type) {
+	case string:
+		s = t
+	case []byte:
+		s = string(t)
+	default:
+		return fmt.Errorf("cannot scan %T into Constraints", value)
+	}
+
+	temp, err := NewConstraint(s)
+	if err != nil {
+		return err
+	}
+	cs.constraints = temp.constraints
+	return nil
+}
+
+// Set implements the flag.Value interface so constraints can be used as a
+// command line flag. Like Scan, the options set on cs are kept.
+func (cs *Constraints) Set(s string) error {
+	temp, err := NewConstraint(s)
+	if err != nil {
+		return err
+	}
+	cs.constraints = temp.constraints
+	return nil
+}
+
+// Type returns the name of the flag type for the pflag.Value interface.
+func (cs *Constraints) Type() string {
+	return "constraint"
+}
+
+// Value implements the Driver.Valuer interface. Only the constraint string is
+// stored; options such as IncludePrerelease are not.
+func (cs Constraints) Value() (driver.Value, error) {
+	return cs.String(), nil
+}
+
+var constraintOps map[string]cfunc
 
 func init() {
 	constraintOps = map[string]cfunc{
@@ -163,27 +353,6 @@ func init() {
 		"~>": constraintTilde,
 		"^":  constraintCaret,
 	}
-
-	ops := `=||!=|>|<|>=|=>|<=|=<|~|~>|\^`
-
-	constraintRegex = regexp.MustCompile(fmt.Sprintf(
-		`^\s*(%s)\s*(%s)\s*$`,
-		ops,
-		cvRegex))
-
-	constraintRangeRegex = regexp.MustCompile(fmt.Sprintf(
-		`\s*(%s)\s+-\s+(%s)\s*`,
-		cvRegex, cvRegex))
-
-	findConstraintRegex = regexp.MustCompile(fmt.Sprintf(
-		`(%s)\s*(%s)`,
-		ops,
-		cvRegex))
-
-	validConstraintRegex = regexp.MustCompile(fmt.Sprintf(
-		`^(\s*(%s)\s*(%s)\s*\,?)+$`,
-		ops,
-		cvRegex))
 }
 
 // An individual constraint
@@ -202,11 +371,22 @@ type constraint struct {
 	minorDirty bool
 	dirty      bool
 	patchDirty bool
+
+	// When prerelease versions are checked by precedence alone. See
+	// Constraints.IncludePrerelease.
+	includePrerelease bool
 }
 
 // Check if a version meets the constraint
 func (c *constraint) check(v *Version) (bool, error) {
-	return constraintOps[c.origfunc](v, c)
+	ok, r := constraintOps[c.origfunc](v, c)
+	return ok, r.err(v, c)
+}
+
+// excludesPrerelease reports whether v is a prerelease the constraint rejects
+// because it is only looking for release versions.
+func (c *constraint) excludesPrerelease(v *Version) bool {
+	return v.Prerelease() != "" && c.con.Prerelease() == "" && !c.includePrerelease
 }
 
 // String prints an individual constraint into a string
@@ -214,51 +394,111 @@ func (c *constraint) string() string {
 	return c.origfunc + c.orig
 }
 
-type cfunc func(v *Version, c *constraint) (bool, error)
+type cfunc func(v *Version, c *constraint) (bool, Reason)
+
+// Reason is why a version does not meet a single comparison of a
+// constraint. The constraint functions return one rather than an error so
+// that checks only needing the result, such as Check, do not pay for
+// formatting a message. Validate returns them in ConstraintErrors.
+type Reason int
+
+const (
+	reasonNone Reason = iota
+
+	// ReasonPrerelease is a prerelease checked against a comparison only
+	// looking for releases.
+	ReasonPrerelease
+
+	// ReasonEqual is a version equal to one a != comparison excludes.
+	ReasonEqual
+
+	// ReasonNotEqual is a version different from the one an = comparison
+	// requires.
+	ReasonNotEqual
+
+	// ReasonLessThan is a version below the minimum of a >=, ~ or ^
+	// comparison, and ReasonLessThanOrEqual one not above the minimum of a
+	// > comparison.
+	ReasonLessThan
+	ReasonLessThanOrEqual
+
+	// ReasonGreaterThan is a version above the maximum of a <= comparison,
+	// and ReasonGreaterThanOrEqual one not below the maximum of a <
+	// comparison.
+	ReasonGreaterThan
+	ReasonGreaterThanOrEqual
+
+	// ReasonMajor, ReasonMajorMinor, ReasonMinor and ReasonPatch are a
+	// version outside the major, major and minor, minor, or patch version a
+	// ~ or ^ comparison allows.
+	ReasonMajor
+	ReasonMajorMinor
+	ReasonMinor
+	ReasonPatch
+)
 
-func parseConstraint(c string) (*constraint, error) {
-	if len(c) > 0 {
-		m := constraintRegex.FindStringSubmatch(c)
-		if m == nil {
-			return nil, fmt.Errorf("improper constraint: %s", c)
-		}
+var reasonFormats = [...]string{
+	ReasonPrerelease:         "%s is a prerelease version and the constraint is only looking for release versions",
+	ReasonEqual:              "%s is equal to %s",
+	ReasonNotEqual:           "%s is not equal to %s",
+	ReasonLessThan:           "%s is less than %s",
+	ReasonLessThanOrEqual:    "%s is less than or equal to %s",
+	ReasonGreaterThan:        "%s is greater than %s",
+	ReasonGreaterThanOrEqual: "%s is greater than or equal to %s",
+	ReasonMajor:              "%s does not have same major version as %s",
+	ReasonMajorMinor:         "%s does not have same major and minor version as %s",
+	ReasonMinor:              "%s does not have same minor version as %s. Expected minor versions to match when constraint major version is 0",
+	ReasonPatch:              "%s does not equal %s. Expect version and constraint to equal when major and minor versions are 0",
+}
 
-		cs := &constraint{
-			orig:     m[2],
-			origfunc: m[1],
-		}
+// ConstraintError is the error Validate returns for each comparison a
+// version does not meet. Callers rendering their own messages can get it
+// with a type assertion or errors.As and switch on Reason rather than
+// matching the message.
+type ConstraintError struct {
+	// Version is the version that was checked.
+	Version *Version
 
-		ver := m[2]
-		minorDirty := false
-		patchDirty := false
-		dirty := false
-		if isX(m[3]) || m[3] == "" {
-			ver = "0.0.0"
-			dirty = true
-		} else if isX(strings.TrimPrefix(m[4], ".")) || m[4] == "" {
-			minorDirty = true
-			dirty = true
-			ver = fmt.Sprintf("%s.0.0%s", m[3], m[6])
-		} else if isX(strings.TrimPrefix(m[5], ".")) || m[5] == "" {
-			dirty = true
-			patchDirty = true
-			ver = fmt.Sprintf("%s%s.0%s", m[3], m[4], m[6])
-		}
+	// Bound is the comparison the version does not meet, with the range it
+	// admits.
+	Bound Bound
 
-		con, err := NewVersion(ver)
-		if err != nil {
+	Reason Reason
+}
 
-			// The constraintRegex should catch any regex parsing errors. So,
-			// we should never get here.
-			return nil, errors.New("constraint Parser Error")
-		}
+func (e *ConstraintError) Error() string {
+	if e.Reason == ReasonPrerelease {
+		return fmt.Sprintf(reasonFormats[e.Reason], e.Version)
+	}
+	return fmt.Sprintf(reasonFormats[e.Reason], e.Version, e.Bound.Version)
+}
+
+// err returns the error describing why v does not meet c, or nil for
+// reasonNone.
+func (r Reason) err(v *Version, c *constraint) error {
+	if r == reasonNone {
+		return nil
+	}
+	return &ConstraintError{Version: v, Bound: c.bound(), Reason: r}
+}
 
-		cs.con = con
-		cs.minorDirty = minorDirty
-		cs.patchDirty = patchDirty
-		cs.dirty = dirty
+func parseConstraint(c string) (*constraint, error) {
+	if len(c) > 0 {
+		p := &constraintParser{s: c}
+		p.skipSpace()
+		cs, err := p.parseComparison()
+		if err == errImproperConstraint {
+			return nil, fmt.Errorf("improper constraint: %s", c)
+		} else if err != nil {
+			return nil, err
+		}
+		p.skipSpace()
+		if !p.atEnd() {
+			return nil, fmt.Errorf("improper constraint: %s", c)
+		}
 
-		return cs, nil
+		// A comparison in the default dialect is always a single constraint.
+		return cs[0], nil
 	}
 
 	// The rest is the special case where an empty string was passed in which
@@ -266,8 +506,7 @@ func parseConstraint(c string) (*constraint, error) {
 	con, err := StrictNewVersion("0.0.0")
 	if err != nil {
 
-		// The constraintRegex should catch any regex parsing errors. So,
-		// we should never get here.
+		// 0.0.0 is always a valid version. So, we should never get here.
 		return nil, errors.New("constraint Parser Error")
 	}
 
@@ -282,54 +521,130 @@ func parseConstraint(c string) (*constraint, error) {
 	return cs, nil
 }
 
+// newConstraint creates a constraint from an operator and the parts of a
+// version as they were written. Wildcards and missing parts mark the
+// constraint as dirty so the operator functions can treat it as a range.
+func newConstraint(op string, cv constraintVersion) (*constraint, error) {
+	cs := &constraint{
+		orig:     cv.orig,
+		origfunc: op,
+	}
+
+	pre := ""
+	if cv.pre != "" {
+		pre = "-" + cv.pre
+	}
+
+	ver := cv.orig
+	minorDirty := false
+	patchDirty := false
+	dirty := false
+	if isX(cv.major) {
+		ver = "0.0.0"
+		dirty = true
+	} else if isX(cv.minor) || cv.minor == "" {
+		minorDirty = true
+		dirty = true
+		ver = fmt.Sprintf("%s.0.0%s", cv.major, pre)
+	} else if isX(cv.patch) || cv.patch == "" {
+		dirty = true
+		patchDirty = true
+		ver = fmt.Sprintf("%s.%s.0%s", cv.major, cv.minor, pre)
+	}
+
+	// The parser only produces versions NewVersion can handle, so an error
+	// here is a problem with a value such as an overflowing segment.
+	con, err := NewVersion(ver)
+	if err != nil {
+		return nil, err
+	}
+
+	cs.con = con
+	cs.minorDirty = minorDirty
+	cs.patchDirty = patchDirty
+	cs.dirty = dirty
+
+	return cs, nil
+}
+
 // Constraint functions
-func constraintNotEqual(v *Version, c *constraint) (bool, error) {
+func constraintNotEqual(v *Version, c *constraint) (bool, Reason) {
 	if c.dirty {
 
 		// If there is a pre-release on the version but the constraint isn't looking
 		// for them assume that pre-releases are not compatible. See issue 21 for
 		// more details.
-		if v.Prerelease() != "" && c.con.Prerelease() == "" {
-			return false, fmt.Errorf("%s is a prerelease version and the constraint is only looking for release versions", v)
+		if c.excludesPrerelease(v) {
+			return false, ReasonPrerelease
 		}
 
 		if c.con.Major() != v.Major() {
-			return true, nil
+			return true, reasonNone
 		}
 		if c.con.Minor() != v.Minor() && !c.minorDirty {
-			return true, nil
+			return true, reasonNone
 		} else if c.minorDirty {
-			return false, fmt.Errorf("%s is equal to %s", v, c.orig)
+			return false, ReasonEqual
 		} else if c.con.Patch() != v.Patch() && !c.patchDirty {
-			return true, nil
+			return true, reasonNone
 		} else if c.patchDirty {
-			// Need to handle prereleases if present
-			if v.Prerelease() != "" || c.con.Prerelease() != "" {
+			// Need to handle prereleases if present, unless they are
+			// being checked by precedence alone
+			if (v.Prerelease() != "" || c.con.Prerelease() != "") && !c.includePrerelease {
 				eq := comparePrerelease(v.Prerelease(), c.con.Prerelease()) != 0
 				if eq {
-					return true, nil
+					return true, reasonNone
 				}
-				return false, fmt.Errorf("%s is equal to %s", v, c.orig)
+				return false, ReasonEqual
 			}
-			return false, fmt.Errorf("%s is equal to %s", v, c.orig)
+			return false, ReasonEqual
 		}
 	}
 
 	eq := v.Equal(c.con)
 	if eq {
-		return false, fmt.Errorf("%s is equal to %s", v, c.orig)
+		return false, ReasonEqual
 	}
 
-	return true, nil
+	return true, reasonNone
 }
 
-func constraintGreaterThan(v *Version, c *constraint) (bool, error) {
+// constraintNotEqualMetadata is != where build metadata must also match for
+// a version to be excluded.
+func constraintNotEqualMetadata(v *Version, c *constraint) (bool, Reason) {
+	if v.Equal(c.con) && v.Metadata() == c.con.Metadata() {
+		return false, ReasonEqual
+	}
+
+	return true, reasonNone
+}
+
+// constraintEqualMetadata is = where build metadata must also match for a
+// version to be admitted.
+func constraintEqualMetadata(v *Version, c *constraint) (bool, Reason) {
+	if c.excludesPrerelease(v) {
+		return false, ReasonPrerelease
+	}
+
+	if v.Identical(c.con) {
+		return true, reasonNone
+	}
+
+	return false, ReasonNotEqual
+}
+
+// isEqualityOp reports whether op is one of the = operators.
+func isEqualityOp(op string) bool {
+	return op == "" || op == "="
+}
+
+func constraintGreaterThan(v *Version, c *constraint) (bool, Reason) {
 
 	// If there is a pre-release on the version but the constraint isn't looking
 	// for them assume that pre-releases are not compatible. See issue 21 for
 	// more details.
-	if v.Prerelease() != "" && c.con.Prerelease() == "" {
-		return false, fmt.Errorf("%s is a prerelease version and the constraint is only looking for release versions", v)
+	if c.excludesPrerelease(v) {
+		return false, ReasonPrerelease
 	}
 
 	var eq bool
@@ -337,75 +652,75 @@ func constraintGreaterThan(v *Version, c *constraint) (bool, error) {
 	if !c.dirty {
 		eq = v.Compare(c.con) == 1
 		if eq {
-			return true, nil
+			return true, reasonNone
 		}
-		return false, fmt.Errorf("%s is less than or equal to %s", v, c.orig)
+		return false, ReasonLessThanOrEqual
 	}
 
 	if v.Major() > c.con.Major() {
-		return true, nil
+		return true, reasonNone
 	} else if v.Major() < c.con.Major() {
-		return false, fmt.Errorf("%s is less than or equal to %s", v, c.orig)
+		return false, ReasonLessThanOrEqual
 	} else if c.minorDirty {
 		// This is a range case such as >11. When the version is something like
 		// 11.1.0 is it not > 11. For that we would need 12 or higher
-		return false, fmt.Errorf("%s is less than or equal to %s", v, c.orig)
+		return false, ReasonLessThanOrEqual
 	} else if c.patchDirty {
 		// This is for ranges such as >11.1. A version of 11.1.1 is not greater
 		// which one of 11.2.1 is greater
 		eq = v.Minor() > c.con.Minor()
 		if eq {
-			return true, nil
+			return true, reasonNone
 		}
-		return false, fmt.Errorf("%s is less than or equal to %s", v, c.orig)
+		return false, ReasonLessThanOrEqual
 	}
 
 	// If we have gotten here we are not comparing pre-preleases and can use the
 	// Compare function to accomplish that.
 	eq = v.Compare(c.con) == 1
 	if eq {
-		return true, nil
+		return true, reasonNone
 	}
-	return false, fmt.Errorf("%s is less than or equal to %s", v, c.orig)
+	return false, ReasonLessThanOrEqual
 }
 
-func constraintLessThan(v *Version, c *constraint) (bool, error) {
+func constraintLessThan(v *Version, c *constraint) (bool, Reason) {
 	// If there is a pre-release on the version but the constraint isn't looking
 	// for them assume that pre-releases are not compatible. See issue 21 for
 	// more details.
-	if v.Prerelease() != "" && c.con.Prerelease() == "" {
-		return false, fmt.Errorf("%s is a prerelease version and the constraint is only looking for release versions", v)
+	if c.excludesPrerelease(v) {
+		return false, ReasonPrerelease
 	}
 
 	eq := v.Compare(c.con) < 0
 	if eq {
-		return true, nil
+		return true, reasonNone
 	}
-	return false, fmt.Errorf("%s is greater than or equal to %s", v, c.orig)
+	return false, ReasonGreaterThanOrEqual
 }
 
-func constraintGreaterThanEqual(v *Version, c *constraint) (bool, error) {
+func constraintGreaterThanEqual(v *Version, c *constraint) (bool, Reason) {
 
 	// If there is a pre-release on the version but the constraint isn't looking
 	// for them assume that pre-releases are not compatible. See issue 21 for
 	// more details.
-	if v.Prerelease() != "" && c.con.Prerelease() == "" {
-		return false, fmt.Errorf("%s is a prerelease version and the constraint is only looking for release versions", v)
+	if c.excludesPrerelease(v) {
+		return false, ReasonPrerelease
 	}
 
 	eq := v.Compare(c.con) >= 0
 	if eq {
-		return true, nil
+		return true, reasonNone
 	}
-	return false, fmt.Errorf("%s is less than %s", v, c.orig)
+	return false, ReasonLessThan
 }
 
-func constraintLessThanEqual(v *Version, c *constraint) (bool, error) {
+func constraintLessThanEqual(v *Version, c *constraint) (bool, Reason) {
 	// If there is a pre-release on the version but the constraint isn't looking
 	// for them assume that pre-releases are not compatible. See issue 21 for
 	// more details.
-	if v.Prerelease() != "" && c.con.Prerelease() == "" {
-		return false, fmt.Errorf("%s is a prerelease version and the constraint is only looking for release versions", v)
+	if c.excludesPrerelease(v) {
+		return false, ReasonPrerelease
 	}
 
 	var eq bool
@@ -413,18 +728,18 @@ func constraintLessThanEqual(v *Version, c *constraint) (bool, error) {
 	if !c.dirty {
 		eq = v.Compare(c.con) <= 0
 		if eq {
-			return true, nil
+			return true, reasonNone
 		}
-		return false, fmt.Errorf("%s is greater than %s", v, c.orig)
+		return false, ReasonGreaterThan
 	}
 
 	if v.Major() > c.con.Major() {
-		return false, fmt.Errorf("%s is greater than %s", v, c.orig)
+		return false, ReasonGreaterThan
 	} else if v.Major() == c.con.Major() && v.Minor() > c.con.Minor() && !c.minorDirty {
-		return false, fmt.Errorf("%s is greater than %s", v, c.orig)
+		return false, ReasonGreaterThan
 	}
 
-	return true, nil
+	return true, reasonNone
 }
 
 // ~*, ~>* --> >= 0.0.0 (any)
@@ -433,44 +748,44 @@ func constraintLessThanEqual(v *Version, c *constraint) (bool, error) {
 // ~1.2, ~1.2.x, ~>1.2, ~>1.2.x --> >=1.2.0, <1.3.0
 // ~1.2.3, ~>1.2.3 --> >=1.2.3, <1.3.0
 // ~1.2.0, ~>1.2.0 --> >=1.2.0, <1.3.0
-func constraintTilde(v *Version, c *constraint) (bool, error) {
+func constraintTilde(v *Version, c *constraint) (bool, Reason) {
 	// If there is a pre-release on the version but the constraint isn't looking
 	// for them assume that pre-releases are not compatible. See issue 21 for
 	// more details.
-	if v.Prerelease() != "" && c.con.Prerelease() == "" {
-		return false, fmt.Errorf("%s is a prerelease version and the constraint is only looking for release versions", v)
+	if c.excludesPrerelease(v) {
+		return false, ReasonPrerelease
 	}
 
 	if v.LessThan(c.con) {
-		return false, fmt.Errorf("%s is less than %s", v, c.orig)
+		return false, ReasonLessThan
 	}
 
 	// ~0.0.0 is a special case where all constraints are accepted. It's
 	// equivalent to >= 0.0.0.
 	if c.con.Major() == 0 && c.con.Minor() == 0 && c.con.Patch() == 0 &&
 		!c.minorDirty && !c.patchDirty {
-		return true, nil
+		return true, reasonNone
 	}
 
 	if v.Major() != c.con.Major() {
-		return false, fmt.Errorf("%s does not have same major version as %s", v, c.orig)
+		return false, ReasonMajor
 	}
 
 	if v.Minor() != c.con.Minor() && !c.minorDirty {
-		return false, fmt.Errorf("%s does not have same major and minor version as %s", v, c.orig)
+		return false, ReasonMajorMinor
 	}
 
-	return true, nil
+	return true, reasonNone
 }
 
 // When there is a .x (dirty) status it automatically opts in to ~. Otherwise
 // it's a straight =
-func constraintTildeOrEqual(v *Version, c *constraint) (bool, error) {
+func constraintTildeOrEqual(v *Version, c *constraint) (bool, Reason) {
 	// If there is a pre-release on the version but the constraint isn't looking
 	// for them assume that pre-releases are not compatible. See issue 21 for
 	// more details.
-	if v.Prerelease() != "" && c.con.Prerelease() == "" {
-		return false, fmt.Errorf("%s is a prerelease version and the constraint is only looking for release versions", v)
+	if c.excludesPrerelease(v) {
+		return false, ReasonPrerelease
 	}
 
 	if c.dirty {
@@ -479,10 +794,10 @@ func constraintTildeOrEqual(v *Version, c *constraint) (bool, error) {
 
 	eq := v.Equal(c.con)
 	if eq {
-		return true, nil
+		return true, reasonNone
 	}
 
-	return false, fmt.Errorf("%s is not equal to %s", v, c.orig)
+	return false, ReasonNotEqual
 }
 
 // ^*      -->  (any)
@@ -494,17 +809,17 @@ func constraintTildeOrEqual(v *Version, c *constraint) (bool, error) {
 // ^0.0.3  -->  >=0.0.3 <0.0.4
 // ^0.0    -->  >=0.0.0 <0.1.0
 // ^0      -->  >=0.0.0 <1.0.0
-func constraintCaret(v *Version, c *constraint) (bool, error) {
+func constraintCaret(v *Version, c *constraint) (bool, Reason) {
 	// If there is a pre-release on the version but the constraint isn't looking
 	// for them assume that pre-releases are not compatible. See issue 21 for
 	// more details.
-	if v.Prerelease() != "" && c.con.Prerelease() == "" {
-		return false, fmt.Errorf("%s is a prerelease version and the constraint is only looking for release versions", v)
+	if c.excludesPrerelease(v) {
+		return false, ReasonPrerelease
 	}
 
 	// This less than handles prereleases
 	if v.LessThan(c.con) {
-		return false, fmt.Errorf("%s is less than %s", v, c.orig)
+		return false, ReasonLessThan
 	}
 
 	var eq bool
@@ -517,31 +832,31 @@ func constraintCaret(v *Version, c *constraint) (bool, error) {
 		// that greater but not within the same major range.
 		eq = v.Major() == c.con.Major()
 		if eq {
-			return true, nil
+			return true, reasonNone
 		}
-		return false, fmt.Errorf("%s does not have same major version as %s", v, c.orig)
+		return false, ReasonMajor
 	}
 
 	// ^ when the major is 0 and minor > 0 is >=0.y.z < 0.y+1
 	if c.con.Major() == 0 && v.Major() > 0 {
-		return false, fmt.Errorf("%s does not have same major version as %s", v, c.orig)
+		return false, ReasonMajor
 	}
 	// If the con Minor is > 0 it is not dirty
 	if c.con.Minor() > 0 || c.patchDirty {
 		eq = v.Minor() == c.con.Minor()
 		if eq {
-			return true, nil
+			return true, reasonNone
 		}
-		return false, fmt.Errorf("%s does not have same minor version as %s. Expected minor versions to match when constraint major version is 0", v, c.orig)
+		return false, ReasonMinor
 	}
 
 	// At this point the major is 0 and the minor is 0 and not dirty. The patch
 	// is not dirty so we need to check if they are equal. If they are not equal
 	eq = c.con.Patch() == v.Patch()
 	if eq {
-		return true, nil
+		return true, reasonNone
 	}
-	return false, fmt.Errorf("%s does not equal %s. Expect version and constraint to equal when major and minor versions are 0", v, c.orig)
+	return false, ReasonPatch
 }
 
 func isX(x string) bool {
@@ -553,16 +868,307 @@ func isX(x string) bool {
 	}
 }
 
-func rewriteRange(i string) string {
-	m := constraintRangeRegex.FindAllStringSubmatch(i, -1)
-	if m == nil {
-		return i
+// constraintParser parses constraint strings. It implements the following
+// grammar, written in EBNF:
+//
+//	constraints = and { "||" and } ;
+//	and         = term { [ "," ] term } ;
+//	term        = range | comparison ;
+//	range       = version ws "-" ws version ;
+//	comparison  = [ op ] version ;
+//	op          = "=" | "!=" | ">" | "<" | ">=" | "=>" | "<=" | "=<" |
+//	              "~" | "~>" | "^" ;
+//	version     = [ "v" ] part [ "." part [ "." part ] ]
+//	              [ "-" identifiers ] [ "+" identifiers ] ;
+//	part        = digit { digit } | "x" | "X" | "*" ;
+//	identifiers = identifier { "." identifier } ;
+//	identifier  = ( letter | digit | "-" ) { letter | digit | "-" } ;
+//	ws          = ( " " | "\t" | "\n" | "\f" | "\r" ) { ws } ;
+//
+// Whitespace may appear around any token. The "-" in a range must have
+// whitespace on both sides, otherwise it begins a prerelease. Terms in an and
+// are separated by whitespace, a comma, or both, although a term that starts
+// with an operator may directly follow the previous one.
+//
+// The range binds tightest and is rewritten into ">= a" and "<= b". Whitespace
+// and comma separated terms are both AND and have the same precedence. "||"
+// is OR and has the lowest precedence. Both AND and OR are associative so
+// the grouping of repeated operators does not change the result.
+type constraintParser struct {
+	s       string
+	pos     int
+	dialect Dialect
+}
+
+// errImproperConstraint is returned by the parser for syntax errors. It is
+// replaced with an error naming the offending OR group before being returned
+// to callers.
+var errImproperConstraint = errors.New("improper constraint")
+
+// constraintVersion holds the parts of a version in a constraint as they
+// were written. Parts that were not present are empty.
+type constraintVersion struct {
+	orig                string
+	major, minor, patch string
+	pre                 string
+}
+
+// parse parses the whole string as per the constraints production and
+// returns the OR groups of AND constraints.
+func (p *constraintParser) parse() ([][]*constraint, error) {
+	var or [][]*constraint
+	for {
+		start := p.pos
+		and, err := p.parseAnd()
+		if err == errImproperConstraint {
+			return nil, fmt.Errorf("improper constraint: %s", p.segment(start))
+		} else if err != nil {
+			return nil, err
+		}
+		or = append(or, and)
+
+		if MaxConstraintGroups > 0 && len(or) > MaxConstraintGroups {
+			return nil, ErrTooManyConstraintGroups
+		}
+
+		if p.atEnd() {
+			return or, nil
+		}
+
+		// parseAnd only stops at the end of the string or at an OR.
+		if !p.dialect.hasOr() {
+			return nil, fmt.Errorf("|| is not supported in %s constraints", p.dialect)
+		}
+		p.pos += len("||")
+	}
+}
+
+// parseAnd parses the and production. It stops at the end of the string or
+// at the "||" that ends the group.
+func (p *constraintParser) parseAnd() ([]*constraint, error) {
+	var and []*constraint
+
+	p.skipSpace()
+	if p.atEnd() || p.atOr() {
+		return nil, errImproperConstraint
 	}
-	o := i
-	for _, v := range m {
-		t := fmt.Sprintf(">= %s, <= %s", v[1], v[11])
-		o = strings.Replace(o, v[0], t, 1)
+
+	for {
+		cs, err := p.parseTerm()
+		if err != nil {
+			return nil, err
+		}
+		and = append(and, cs...)
+
+		sep := p.skipSpace()
+		comma := p.peek() == ','
+		if comma {
+			p.pos++
+			sep = true
+			p.skipSpace()
+		}
+
+		// A comma must be followed by another term.
+		if p.atEnd() || p.atOr() {
+			if comma {
+				return nil, errImproperConstraint
+			}
+			return and, nil
+		}
+
+		if !sep && !isOpStart(p.peek()) {
+			return nil, errImproperConstraint
+		}
+	}
+}
+
+// parseTerm parses the term production. A range produces two constraints.
+func (p *constraintParser) parseTerm() ([]*constraint, error) {
+	if isOpStart(p.peek()) {
+		return p.parseComparison()
+	}
+
+	lo, ok := p.parseVersion()
+	if !ok {
+		return nil, errImproperConstraint
+	}
+
+	// Look ahead for the " - " of a range. If it is not there the version is
+	// a comparison with no operator.
+	end := p.pos
+	if !p.skipSpace() || p.peek() != '-' {
+		p.pos = end
+		return p.comparison("", lo)
+	}
+	if !p.dialect.hasRanges() {
+		return nil, fmt.Errorf("ranges are not supported in %s constraints", p.dialect)
+	}
+	p.pos++
+	if !p.skipSpace() {
+		return nil, errImproperConstraint
+	}
+
+	hi, ok := p.parseVersion()
+	if !ok {
+		return nil, errImproperConstraint
+	}
+
+	min, err := newConstraint(">=", lo)
+	if err != nil {
+		return nil, err
 	}
+	max, err := newConstraint("<=", hi)
+	if err != nil {
+		return nil, err
+	}
+	return []*constraint{min, max}, nil
+}
+
+// parseComparison parses the comparison production. Some dialects expand a
+// comparison into more than one constraint.
+func (p *constraintParser) parseComparison() ([]*constraint, error) {
+	op := p.parseOp()
+	p.skipSpace()
+
+	cv, ok := p.parseVersion()
+	if !ok {
+		return nil, errImproperConstraint
+	}
+
+	return p.comparison(op, cv)
+}
+
+// parseOp parses the op production, preferring the longest operator. An
+// empty string is returned when there is no operator.
+func (p *constraintParser) parseOp() string {
+	rest := p.s[p.pos:]
+	for _, op := range []string{"!=", ">=", "=>", "<=", "=<", "~>", "=", ">", "<", "~", "^"} {
+		if strings.HasPrefix(rest, op) {
+			p.pos += len(op)
+			return op
+		}
+	}
+	return ""
+}
+
+// parseVersion parses the version production. Optional parts that are not
+// followed by what they require are left unconsumed.
+func (p *constraintParser) parseVersion() (constraintVersion, bool) {
+	start := p.pos
+	var cv constraintVersion
+
+	if p.peek() == 'v' {
+		p.pos++
+	}
+
+	cv.major = p.parsePart()
+	if cv.major == "" {
+		p.pos = start
+		return cv, false
+	}
+
+	if p.peek() == '.' && isPartStart(p.peekAt(1)) {
+		p.pos++
+		cv.minor = p.parsePart()
+
+		if p.peek() == '.' && isPartStart(p.peekAt(1)) {
+			p.pos++
+			cv.patch = p.parsePart()
+		}
+	}
+
+	if p.peek() == '-' && isIdentifierChar(p.peekAt(1)) {
+		p.pos++
+		cv.pre = p.parseIdentifiers()
+	}
+
+	if p.peek() == '+' && isIdentifierChar(p.peekAt(1)) {
+		p.pos++
+		p.parseIdentifiers()
+	}
+
+	cv.orig = p.s[start:p.pos]
+	return cv, true
+}
+
+// parsePart parses the part production and returns an empty string if there
+// is no part at the current position.
+func (p *constraintParser) parsePart() string {
+	start := p.pos
+	if isX(string(p.peek())) {
+		p.pos++
+		return p.s[start:p.pos]
+	}
+
+	for p.pos < len(p.s) && p.s[p.pos] >= '0' && p.s[p.pos] <= '9' {
+		p.pos++
+	}
+	return p.s[start:p.pos]
+}
+
+// parseIdentifiers parses the identifiers production. The caller ensures at
+// least one identifier character is present.
+func (p *constraintParser) parseIdentifiers() string {
+	start := p.pos
+	for {
+		for isIdentifierChar(p.peek()) {
+			p.pos++
+		}
+
+		if p.peek() != '.' || !isIdentifierChar(p.peekAt(1)) {
+			return p.s[start:p.pos]
+		}
+		p.pos++
+	}
+}
+
+// skipSpace skips over whitespace and reports whether any was found.
+func (p *constraintParser) skipSpace() bool {
+	start := p.pos
+	for p.pos < len(p.s) && strings.IndexByte(" \t\n\f\r", p.s[p.pos]) != -1 {
+		p.pos++
+	}
+	return p.pos > start
+}
+
+func (p *constraintParser) atEnd() bool {
+	return p.pos >= len(p.s)
+}
+
+func (p *constraintParser) atOr() bool {
+	return strings.HasPrefix(p.s[p.pos:], "||")
+}
+
+// peek returns the byte at the current position or 0 at the end.
+func (p *constraintParser) peek() byte {
+	return p.peekAt(0)
+}
+
+// peekAt returns the byte n bytes past the current position or 0 if that is
+// past the end.
+func (p *constraintParser) peekAt(n int) byte {
+	if p.pos+n >= len(p.s) {
+		return 0
+	}
+	return p.s[p.pos+n]
+}
+
+// segment returns the OR group starting at start for use in errors.
+func (p *constraintParser) segment(start int) string {
+	if i := strings.Index(p.s[start:], "||"); i != -1 {
+		return p.s[start : start+i]
+	}
+	return p.s[start:]
+}
+
+func isOpStart(b byte) bool {
+	return b != 0 && strings.IndexByte("=!<>~^", b) != -1
+}
+
+func isPartStart(b byte) bool {
+	return (b >= '0' && b <= '9') || b == 'x' || b == 'X' || b == '*'
+}
 
-	return o
+func isIdentifierChar(b byte) bool {
+	return b != 0 && strings.IndexByte(allowed, b) != -1
 }
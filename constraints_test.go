@@ -1,7 +1,12 @@
 package semver
 
 import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"io/ioutil"
 	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -401,25 +406,215 @@ func TestConstraintsCheck(t *testing.T) {
 	}
 }
 
-func TestRewriteRange(t *testing.T) {
+func TestConstraintsExclusionsMatchMetadata(t *testing.T) {
 	tests := []struct {
-		c  string
-		nc string
+		constraint string
+		version    string
+		check      bool
+		exact      bool
+	}{
+		{"!=1.2.3+broken.build", "1.2.3+broken.build", false, false},
+		{"!=1.2.3+broken.build", "1.2.3+other.build", false, true},
+		{"!=1.2.3+broken.build", "1.2.3", false, true},
+		{"!=1.2.3+broken.build", "1.2.4", true, true},
+		{"!=1.2.3", "1.2.3+broken.build", false, false},
+		{"!=1.2.x+broken.build", "1.2.3+other.build", false, false},
+		{">=1.0.0, !=1.2.3+broken.build", "1.2.3+fixed", false, true},
+	}
+
+	for _, tc := range tests {
+		c, err := NewConstraint(tc.constraint)
+		if err != nil {
+			t.Errorf("err: %s", err)
+			continue
+		}
+
+		v, err := NewVersion(tc.version)
+		if err != nil {
+			t.Errorf("err: %s", err)
+			continue
+		}
+
+		if a := c.Check(v); a != tc.check {
+			t.Errorf("Constraint '%s' failing with '%s' by default", tc.constraint, tc.version)
+		}
+
+		c.ExclusionsMatchMetadata = true
+		e := tc.check || tc.exact
+		if a := c.Check(v); a != e {
+			t.Errorf("Constraint '%s' failing with '%s' when matching metadata", tc.constraint, tc.version)
+		}
+		if a, _ := c.Validate(v); a != e {
+			t.Errorf("Constraint '%s' failing validation with '%s' when matching metadata", tc.constraint, tc.version)
+		}
+	}
+}
+
+func TestConstraintsEqualityMatchesMetadata(t *testing.T) {
+	tests := []struct {
+		constraint string
+		version    string
+		check      bool
+		exact      bool
 	}{
-		{"2 - 3", ">= 2, <= 3"},
-		{"2 - 3, 2 - 3", ">= 2, <= 3,>= 2, <= 3"},
-		{"2 - 3, 4.0.0 - 5.1", ">= 2, <= 3,>= 4.0.0, <= 5.1"},
+		{"1.0.0+linux", "1.0.0+linux", true, true},
+		{"=1.0.0+linux", "1.0.0+darwin", true, false},
+		{"1.0.0+linux", "1.0.0", true, false},
+		{"1.0.0+linux", "1.0.1+linux", false, false},
+		{"1.0.0", "1.0.0+darwin", true, true},
+		{"1.0.x+linux", "1.0.1+darwin", true, true},
+		{"1.0.0-rc.1+linux", "1.0.0-rc.1+darwin", true, false},
+		{">=0.9.0 1.0.0+linux || 2.x", "1.0.0+darwin", true, false},
+		{">=1.0.0+linux", "1.0.0+darwin", true, true},
 	}
 
 	for _, tc := range tests {
-		o := rewriteRange(tc.c)
+		c, err := NewConstraint(tc.constraint)
+		if err != nil {
+			t.Errorf("err: %s", err)
+			continue
+		}
 
-		if o != tc.nc {
-			t.Errorf("Range %s rewritten incorrectly as '%s'", tc.c, o)
+		v, err := NewVersion(tc.version)
+		if err != nil {
+			t.Errorf("err: %s", err)
+			continue
+		}
+
+		if a := c.Check(v); a != tc.check {
+			t.Errorf("Constraint '%s' failing with '%s' by default", tc.constraint, tc.version)
+		}
+
+		c.EqualityMatchesMetadata = true
+		if a := c.Check(v); a != tc.exact {
+			t.Errorf("Constraint '%s' failing with '%s' when matching metadata", tc.constraint, tc.version)
+		}
+		if a, _ := c.Validate(v); a != tc.exact {
+			t.Errorf("Constraint '%s' failing validation with '%s' when matching metadata", tc.constraint, tc.version)
+		}
+		if a := c.Compile().Check(v); a != tc.exact {
+			t.Errorf("Compiled constraint '%s' failing with '%s' when matching metadata", tc.constraint, tc.version)
 		}
 	}
 }
 
+func TestConstraintGrammar(t *testing.T) {
+	tests := []struct {
+		constraint string
+		st         string
+		err        bool
+	}{
+		// Ranges bind tighter than AND and OR
+		{"2 - 3", ">=2 <=3", false},
+		{"2 - 3, 2 - 3", ">=2 <=3 >=2 <=3", false},
+		{"2 - 3, 4.0.0 - 5.1", ">=2 <=3 >=4.0.0 <=5.1", false},
+		{"1 - 2 || 3 - 4", ">=1 <=2 || >=3 <=4", false},
+		{"<5 1 - 2", "<5 >=1 <=2", false},
+		{"0 - 1\t1", ">=0 <=1 1", false},
+		{"1.2.3-beta - 2", ">=1.2.3-beta <=2", false},
+		{"x||* - x", "x || >=* <=x", false},
+		{"1 - 2 - 3", "", true},
+		{">1 - 2", "", true},
+		{"1 -2", "", true},
+		{"1 - ", "", true},
+
+		// A hyphen without surrounding whitespace is a prerelease
+		{"1.1-3", "1.1-3", false},
+
+		// Whitespace and commas are both AND
+		{">1 <2", ">1 <2", false},
+		{">1, <2", ">1 <2", false},
+		{">1 , <2", ">1 <2", false},
+		{">1,<2", ">1 <2", false},
+		{">1<2", ">1 <2", false},
+		{">1 <2,", "", true},
+		{">1 <2, ", "", true},
+		{">1, || <3", "", true},
+		{">1,, <2", "", true},
+		{", >1", "", true},
+
+		// OR has the lowest precedence
+		{">1 <2 || >3, <4", ">1 <2 || >3 <4", false},
+		{">1||<2", ">1 || <2", false},
+		{"||", "", true},
+		{">1 ||", "", true},
+		{"|| >1", "", true},
+		{">1 | <2", "", true},
+		{"", "", true},
+
+		// Terms must be separated unless the next starts with an operator
+		{"1.2.31.2", "", true},
+		{"1v2", "", true},
+		{"1x", "", true},
+		{"*.|", "", true},
+
+		// Operators prefer the longest match
+		{"=>1", "=>1", false},
+		{"=<1", "=<1", false},
+		{"~>1", "~>1", false},
+		{"!1", "", true},
+	}
+
+	for _, tc := range tests {
+		c, err := NewConstraint(tc.constraint)
+		if tc.err {
+			if err == nil {
+				t.Errorf("expected error for %q but got %q", tc.constraint, c)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("unexpected error for %q: %s", tc.constraint, err)
+			continue
+		}
+
+		if c.String() != tc.st {
+			t.Errorf("expected %q to parse as %q but got %q", tc.constraint, tc.st, c.String())
+		}
+	}
+}
+
+func TestConstraintLimits(t *testing.T) {
+	defer func(l, g, v int) {
+		MaxConstraintLength = l
+		MaxConstraintGroups = g
+		MaxVersionLength = v
+	}(MaxConstraintLength, MaxConstraintGroups, MaxVersionLength)
+	MaxConstraintLength = 4096
+	MaxConstraintGroups = 128
+	MaxVersionLength = 256
+
+	long := ">=1.2.3" + strings.Repeat(" ", MaxConstraintLength)
+	if _, err := NewConstraint(long); err != ErrConstraintTooLong {
+		t.Errorf("Expected ErrConstraintTooLong, got %v", err)
+	}
+
+	many := strings.TrimSuffix(strings.Repeat("1 || ", MaxConstraintGroups+1), " || ")
+	if _, err := NewConstraint(many); err != ErrTooManyConstraintGroups {
+		t.Errorf("Expected ErrTooManyConstraintGroups, got %v", err)
+	}
+
+	enough := strings.TrimSuffix(strings.Repeat("1 || ", MaxConstraintGroups), " || ")
+	if _, err := NewConstraint(enough); err != nil {
+		t.Errorf("Unexpected error at the group limit: %s", err)
+	}
+
+	// Limits on versions apply to the versions within a constraint.
+	if _, err := NewConstraint(">=1.2.3-" + strings.Repeat("a", MaxVersionLength)); err != ErrVersionTooLong {
+		t.Errorf("Expected ErrVersionTooLong, got %v", err)
+	}
+
+	MaxConstraintLength = 0
+	MaxConstraintGroups = 0
+
+	if _, err := NewConstraint(long); err != nil {
+		t.Errorf("Unexpected error with the length limit disabled: %s", err)
+	}
+	if _, err := NewConstraint(many); err != nil {
+		t.Errorf("Unexpected error with the group limit disabled: %s", err)
+	}
+}
+
 func TestIsX(t *testing.T) {
 	tests := []struct {
 		t string
@@ -635,6 +830,47 @@ func TestConstraintsValidate(t *testing.T) {
 	}
 }
 
+func TestConstraintError(t *testing.T) {
+	tests := []struct {
+		constraint, version string
+		reason              Reason
+		bound               string
+	}{
+		{">=1.2.3", "1.2.0", ReasonLessThan, "[1.2.3, )"},
+		{">1.2.3", "1.2.3", ReasonLessThanOrEqual, "(1.2.3, )"},
+		{"<=1.2", "1.3.0", ReasonGreaterThan, "(, 1.3.0)"},
+		{"<1.2.3", "1.2.3", ReasonGreaterThanOrEqual, "(, 1.2.3)"},
+		{"!=1.2.3", "1.2.3", ReasonEqual, "not [1.2.3, 1.2.3]"},
+		{"=1.2.3", "1.2.4", ReasonNotEqual, "[1.2.3, 1.2.3]"},
+		{"^1.2.3", "2.0.0", ReasonMajor, "[1.2.3, 2.0.0)"},
+		{"^1.2.3", "1.3.0-beta", ReasonPrerelease, "[1.2.3, 2.0.0)"},
+	}
+
+	for _, tc := range tests {
+		c, err := NewConstraint(tc.constraint)
+		if err != nil {
+			t.Fatalf("Error parsing constraint %q: %s", tc.constraint, err)
+		}
+
+		_, errs := c.Validate(MustParse(tc.version))
+		if len(errs) != 1 {
+			t.Errorf("Expected one error validating %s against %q, got %v", tc.version, tc.constraint, errs)
+			continue
+		}
+		e, ok := errs[0].(*ConstraintError)
+		if !ok {
+			t.Errorf("Expected a *ConstraintError validating %s against %q, got %T", tc.version, tc.constraint, errs[0])
+			continue
+		}
+		if e.Reason != tc.reason || e.Version.String() != tc.version {
+			t.Errorf("Expected reason %d for %s against %q, got %d for %s", tc.reason, tc.version, tc.constraint, e.Reason, e.Version)
+		}
+		if b := formatBound(e.Bound); b != tc.bound {
+			t.Errorf("Expected the bound of %q to be %s, got %s", tc.constraint, tc.bound, b)
+		}
+	}
+}
+
 func TestConstraintString(t *testing.T) {
 	tests := []struct {
 		constraint string
@@ -664,3 +900,302 @@ func TestConstraintString(t *testing.T) {
 		}
 	}
 }
+
+func TestConstraintsSQL(t *testing.T) {
+	tests := []struct {
+		value    interface{}
+		expected string
+		err      bool
+	}{
+		{"^1.2 || >=3.0.0", "^1.2 || >=3.0.0", false},
+		{[]byte(">=1.0.0, <2.0.0"), ">=1.0.0 <2.0.0", false},
+		{"^^", "", true},
+		{nil, "", true},
+		{42, "", true},
+	}
+
+	for _, tc := range tests {
+		cs := Constraints{IncludePrerelease: true}
+		err := cs.Scan(tc.value)
+		if tc.err {
+			if err == nil {
+				t.Errorf("Expected error scanning %#v", tc.value)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Error scanning %#v: %s", tc.value, err)
+			continue
+		}
+		if !cs.IncludePrerelease {
+			t.Errorf("Expected scanning %#v to keep the options", tc.value)
+		}
+
+		got, err := cs.Value()
+		if err != nil {
+			t.Errorf("Error getting value of %#v: %s", tc.value, err)
+			continue
+		}
+		if got != tc.expected {
+			t.Errorf("Expected %#v to have value %q, got %q", tc.value, tc.expected, got)
+		}
+	}
+}
+
+func TestConstraintsFlag(t *testing.T) {
+	cs := Constraints{IncludePrerelease: true}
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(ioutil.Discard)
+	fs.Var(&cs, "constraint", "version constraint")
+
+	if err := fs.Parse([]string{"--constraint", ">=1.2, <2"}); err != nil {
+		t.Fatalf("Error parsing flags: %s", err)
+	}
+	if cs.String() != ">=1.2 <2" {
+		t.Errorf("Expected flag to be set to >=1.2 <2, got %q", cs.String())
+	}
+	if !cs.IncludePrerelease {
+		t.Error("Expected setting the flag to keep the options")
+	}
+	if !cs.Check(MustParse("1.5.0-rc.1")) {
+		t.Error("Expected the flag constraints to admit 1.5.0-rc.1")
+	}
+	if cs.Type() != "constraint" {
+		t.Errorf("Expected type constraint, got %q", cs.Type())
+	}
+
+	err := fs.Parse([]string{"--constraint", ">=1.2 ||"})
+	if err == nil || !strings.Contains(err.Error(), "improper constraint") {
+		t.Errorf("Expected the parse error in the flag error, got %v", err)
+	}
+}
+
+func TestConstraintsJSON(t *testing.T) {
+	c, err := NewConstraint(">=1.2.3, !=1.4.x || ^3")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	c.ExclusionsMatchMetadata = true
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(c); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	b := bytes.TrimSpace(buf.Bytes())
+
+	expected := `{"anyOf":[[{"op":">=","version":"1.2.3"},{"op":"!=","version":"1.4.x"}],[{"op":"^","version":"3"}]],"exclusionsMatchMetadata":true}`
+	if string(b) != expected {
+		t.Errorf("Expected JSON %s, got %s", expected, b)
+	}
+
+	tests := []string{
+		">=1.2.3, !=1.4.x || ^3",
+		"1.2.3 - 2.3.4",
+		"~1.2.x-beta",
+		"1.2",
+		"*",
+		"=>v1.0.0 =<2",
+	}
+
+	for _, tc := range tests {
+		c, err := NewConstraint(tc)
+		if err != nil {
+			t.Errorf("Error parsing constraint %q: %s", tc, err)
+			continue
+		}
+
+		b, err := json.Marshal(c)
+		if err != nil {
+			t.Errorf("Error marshaling %q: %s", tc, err)
+			continue
+		}
+
+		var u Constraints
+		if err := json.Unmarshal(b, &u); err != nil {
+			t.Errorf("Error unmarshaling %s: %s", b, err)
+			continue
+		}
+
+		if u.String() != c.String() {
+			t.Errorf("Expected %q to round trip, got %q", c, u.String())
+		}
+		if u.ExclusionsMatchMetadata {
+			t.Errorf("Expected ExclusionsMatchMetadata to be false for %q", tc)
+		}
+	}
+
+	var u Constraints
+	if err := json.Unmarshal([]byte(expected), &u); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if !u.ExclusionsMatchMetadata {
+		t.Error("Expected ExclusionsMatchMetadata to be true")
+	}
+
+	c.EqualityMatchesMetadata = true
+	b, err = json.Marshal(c)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	var m Constraints
+	if err := json.Unmarshal(b, &m); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if !m.EqualityMatchesMetadata {
+		t.Errorf("Expected EqualityMatchesMetadata to round trip in %s", b)
+	}
+	if !u.Check(MustParse("3.1.0")) || u.Check(MustParse("1.4.2")) {
+		t.Errorf("Unmarshaled constraint %q checks versions incorrectly", u.String())
+	}
+
+	invalid := []string{
+		`[]`,
+		`{}`,
+		`{"anyOf":[]}`,
+		`{"anyOf":[[]]}`,
+		`{"anyOf":[[{"op":"?","version":"1.2.3"}]]}`,
+		`{"anyOf":[[{"op":">","version":"foo"}]]}`,
+		`{"anyOf":[[{"op":"","version":">1.2.3"}]]}`,
+		`{"anyOf":[[{"op":"","version":"1 - 2"}]]}`,
+		`{"anyOf":[[{"op":"=","version":""}]]}`,
+	}
+	for _, s := range invalid {
+		if err := json.Unmarshal([]byte(s), &u); err == nil {
+			t.Errorf("Expected error unmarshaling %s", s)
+		}
+	}
+}
+
+func TestConstraintsIncludePrerelease(t *testing.T) {
+	tests := []struct {
+		constraint string
+		version    string
+		check      bool
+	}{
+		{">=1.0.0", "2.0.0-alpha.1", true},
+		{">=1.0.0", "1.0.0-alpha.1", false},
+		{"<2.0.0", "2.0.0-rc.1", true},
+		{">1.2.3", "1.2.4-beta", true},
+		{">1.2.3", "1.2.3-beta", false},
+		{">1.2", "1.3.0-beta", true},
+		{"~1.2.3", "1.2.5-beta", true},
+		{"~1.2.3", "1.3.0-beta", false},
+		{"^1.2.3", "1.9.0-rc", true},
+		{"^1.2.3", "2.0.0-rc", false},
+		{"^1.2.3", "1.2.3-beta", false},
+		{"1.x", "1.5.0-beta", true},
+		{"*", "1.0.0-alpha", true},
+		{"=1.2.3", "1.2.3-beta", false},
+		{"!=1.2.3", "1.2.3-beta", true},
+		{"!=1.2.x", "1.2.3-beta", false},
+		{"<=1.2", "1.2.5-beta", true},
+		{"1.2.3 - 2.3.4", "2.0.0-beta", true},
+		{">=1.0.0-beta.2", "1.0.0-beta.1", false},
+		{">=1.0.0, <2.0.0 || >=3", "3.1.0-rc.1", true},
+	}
+
+	for _, tc := range tests {
+		c, err := NewConstraint(tc.constraint)
+		if err != nil {
+			t.Errorf("Error parsing constraint %q: %s", tc.constraint, err)
+			continue
+		}
+		v := MustParse(tc.version)
+
+		if c.Check(v) && !strings.Contains(tc.constraint, "-") && !strings.HasPrefix(tc.constraint, "!=") && tc.constraint != "*" {
+			t.Errorf("Expected %q not to admit %q by default", tc.constraint, tc.version)
+		}
+
+		c.IncludePrerelease = true
+		if a := c.Check(v); a != tc.check {
+			t.Errorf("Expected %q to admit %q: %t, got %t", tc.constraint, tc.version, tc.check, a)
+		}
+		if a, errs := c.Validate(v); a != tc.check || a == (len(errs) > 0) {
+			t.Errorf("Expected %q to validate %q: %t, got %t with %v", tc.constraint, tc.version, tc.check, a, errs)
+		}
+	}
+}
+
+func TestConstraintsIncludePrereleaseJSON(t *testing.T) {
+	var c Constraints
+	if err := json.Unmarshal([]byte(`{"anyOf":[[{"op":">=","version":"1.0.0"}]],"includePrerelease":true}`), &c); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	if !c.IncludePrerelease || !c.Check(MustParse("2.0.0-alpha.1")) {
+		t.Error("Expected the includePrerelease option to be unmarshaled")
+	}
+
+	b, err := json.Marshal(c)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if !strings.Contains(string(b), `"includePrerelease":true`) {
+		t.Errorf("Expected the includePrerelease option to be marshaled, got %s", b)
+	}
+}
+
+func TestConstraintsSnapPartialBounds(t *testing.T) {
+	tests := []struct {
+		constraint string
+		version    string
+		unsnapped  bool
+		snapped    bool
+	}{
+		{"<2", "2.0.0-rc.1", true, false},
+		{"<2.x", "2.0.0-rc.1", true, false},
+		{"<1.2", "1.2.0-beta", true, false},
+		{"<2.0.0", "2.0.0-rc.1", true, true},
+		{">=1.2", "1.2.0-beta", false, true},
+		{"=>1", "1.0.0-0", false, true},
+		{">=1.2.0", "1.2.0-beta", false, false},
+		{">=1.2", "1.1.9-beta", false, false},
+		{"<2", "1.9.0", true, true},
+		{">=1.2", "1.2.0", true, true},
+		{">=1, <2", "2.0.0-rc.1", true, false},
+	}
+
+	for _, tc := range tests {
+		c, err := NewConstraint(tc.constraint)
+		if err != nil {
+			t.Errorf("Error parsing constraint %q: %s", tc.constraint, err)
+			continue
+		}
+		v := MustParse(tc.version)
+		c.IncludePrerelease = true
+
+		if a := c.Check(v); a != tc.unsnapped {
+			t.Errorf("Expected %q to admit %q without snapping: %t, got %t", tc.constraint, tc.version, tc.unsnapped, a)
+		}
+
+		c.SnapPartialBounds = true
+		if a := c.Check(v); a != tc.snapped {
+			t.Errorf("Expected %q to admit %q with snapping: %t, got %t", tc.constraint, tc.version, tc.snapped, a)
+		}
+
+		// Without IncludePrerelease prereleases are never admitted.
+		c.IncludePrerelease = false
+		if v.Prerelease() != "" && c.Check(v) {
+			t.Errorf("Expected %q not to admit %q without IncludePrerelease", tc.constraint, tc.version)
+		}
+	}
+}
+
+func TestConstraintsCheckAllocs(t *testing.T) {
+	c, err := NewConstraint("^1.2 || >=3.0.0, !=3.1.0")
+	if err != nil {
+		t.Fatalf("Error parsing constraint: %s", err)
+	}
+	vs := []*Version{MustParse("1.5.0"), MustParse("2.0.0"), MustParse("3.1.0"), MustParse("3.2.0-rc.1")}
+
+	allocs := testing.AllocsPerRun(100, func() {
+		for _, v := range vs {
+			c.Check(v)
+		}
+	})
+	if allocs != 0 {
+		t.Errorf("Expected Check not to allocate, got %v allocations", allocs)
+	}
+}
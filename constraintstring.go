@@ -0,0 +1,55 @@
+package semver
+
+import (
+	"fmt"
+)
+
+// ConstraintString holds constraints that are encoded as the string they
+// were parsed from, such as "^1.2", rather than the JSON structure written
+// by Constraints.MarshalJSON. It implements encoding.TextUnmarshaler and
+// encoding.TextMarshaler, and the go-yaml interfaces, so a manifest field
+// such as
+//
+//	version: "^1.2"
+//
+// can be decoded straight into it by encoding/json, go-yaml and
+// BurntSushi/toml. The string is parsed with NewConstraint when it is
+// decoded, and the error for a string that cannot be parsed includes it.
+type ConstraintString struct {
+	// Constraints is nil until a string has been decoded.
+	Constraints *Constraints
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+func (c *ConstraintString) UnmarshalText(text []byte) error {
+	cs, err := NewConstraint(string(text))
+	if err != nil {
+		return fmt.Errorf("invalid constraint %q: %s", text, err)
+	}
+	c.Constraints = cs
+	return nil
+}
+
+// MarshalText implements the encoding.TextMarshaler interface. Constraints
+// that are nil are written as an empty string.
+func (c ConstraintString) MarshalText() ([]byte, error) {
+	if c.Constraints == nil {
+		return []byte{}, nil
+	}
+	return []byte(c.Constraints.String()), nil
+}
+
+// UnmarshalYAML implements the yaml.Unmarshaler interface of go-yaml.
+func (c *ConstraintString) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var s string
+	if err := unmarshal(&s); err != nil {
+		return err
+	}
+	return c.UnmarshalText([]byte(s))
+}
+
+// MarshalYAML implements the yaml.Marshaler interface of go-yaml.
+func (c ConstraintString) MarshalYAML() (interface{}, error) {
+	b, err := c.MarshalText()
+	return string(b), err
+}
@@ -0,0 +1,55 @@
+package semver
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestConstraintStringEncoding(t *testing.T) {
+	type manifest struct {
+		Version  ConstraintString  `json:"version"`
+		Optional *ConstraintString `json:"optional,omitempty"`
+	}
+
+	var m manifest
+	if err := json.Unmarshal([]byte(`{"version": "^1.2, !=1.4.0"}`), &m); err != nil {
+		t.Fatalf("Error unmarshaling: %s", err)
+	}
+	if m.Version.Constraints == nil || !m.Version.Constraints.Check(MustParse("1.5.0")) ||
+		m.Version.Constraints.Check(MustParse("1.4.0")) {
+		t.Errorf("Unexpected constraints %v", m.Version.Constraints)
+	}
+	if m.Optional != nil {
+		t.Errorf("Expected no optional constraints, got %v", m.Optional.Constraints)
+	}
+
+	b, err := json.Marshal(m)
+	if err != nil {
+		t.Fatalf("Error marshaling: %s", err)
+	}
+	if string(b) != `{"version":"^1.2 !=1.4.0"}` {
+		t.Errorf("Unexpected JSON %s", b)
+	}
+
+	err = json.Unmarshal([]byte(`{"version": "^1.2 ||| foo"}`), &m)
+	if err == nil {
+		t.Fatal("Expected error unmarshaling invalid constraints")
+	}
+	if expected := `invalid constraint "^1.2 ||| foo": improper constraint: | foo`; err.Error() != expected {
+		t.Errorf("Expected error %q, got %q", expected, err)
+	}
+
+	var c ConstraintString
+	if b, err := c.MarshalText(); err != nil || len(b) != 0 {
+		t.Errorf("Expected empty text for nil constraints, got %q (%v)", b, err)
+	}
+	if err := c.UnmarshalYAML(yamlString("~1.2.3")); err != nil {
+		t.Fatalf("Error unmarshaling YAML: %s", err)
+	}
+	if out, err := c.MarshalYAML(); err != nil || out != "~1.2.3" {
+		t.Errorf("Expected ~1.2.3, got %v (%v)", out, err)
+	}
+	if err := c.UnmarshalYAML(yamlString(">=foo")); err == nil {
+		t.Error("Expected error unmarshaling invalid constraints from YAML")
+	}
+}
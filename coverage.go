@@ -0,0 +1,67 @@
+package semver
+
+import (
+	"sort"
+)
+
+// CoverageReport summarizes which of a set of available versions a
+// constraint admits. It is returned by Constraints.Coverage.
+type CoverageReport struct {
+	// Available is the number of versions checked and Admitted the number
+	// the constraint admits.
+	Available, Admitted int
+
+	// Oldest and Newest are the lowest and highest admitted versions. They
+	// are nil when no versions are admitted.
+	Oldest, Newest *Version
+
+	// NextExcluded is the lowest release higher than Newest, which the
+	// constraint does not admit. Prereleases are not considered. It is nil
+	// when Newest is the highest release or no versions are admitted.
+	NextExcluded *Version
+}
+
+// Coverage reports how many of the available versions, such as those
+// published to a registry, the constraints admit along with the oldest and
+// newest of them and the first newer release they exclude.
+func (cs Constraints) Coverage(available Collection) *CoverageReport {
+	sorted := make(Collection, len(available))
+	copy(sorted, available)
+	sort.Sort(sorted)
+
+	r := &CoverageReport{Available: len(sorted)}
+	for _, v := range sorted {
+		if !cs.Check(v) {
+			continue
+		}
+
+		r.Admitted++
+		if r.Oldest == nil {
+			r.Oldest = v
+		}
+		r.Newest = v
+	}
+
+	if r.Newest == nil {
+		return r
+	}
+
+	for _, v := range sorted {
+		if v.Prerelease() == "" && v.GreaterThan(r.Newest) {
+			r.NextExcluded = v
+			break
+		}
+	}
+
+	return r
+}
+
+// MinimumSupported returns the oldest of the published versions the
+// constraints admit. Library authors can use it to see how far back a
+// consumer's declared range reaches before dropping support for old
+// releases. The bool is false when no published version is admitted. It is
+// MinSatisfying with the bool in place of the error.
+func (cs Constraints) MinimumSupported(published Collection) (*Version, bool) {
+	min, err := MinSatisfying(published, &cs)
+	return min, err == nil
+}
@@ -0,0 +1,94 @@
+package semver
+
+import (
+	"testing"
+)
+
+func TestConstraintsCoverage(t *testing.T) {
+	var available Collection
+	for _, s := range []string{"2.1.0", "1.0.0", "1.2.0", "1.1.0", "2.0.0-rc.1", "2.0.0", "3.0.0-beta"} {
+		available = append(available, MustParse(s))
+	}
+
+	tests := []struct {
+		constraint   string
+		admitted     int
+		oldest       string
+		newest       string
+		nextExcluded string
+	}{
+		{"^1.1", 2, "1.1.0", "1.2.0", "2.0.0"},
+		{"*", 5, "1.0.0", "2.1.0", ""},
+		{">=1.0.0-0", 7, "1.0.0", "3.0.0-beta", ""},
+		{"~2.0.0-0", 2, "2.0.0-rc.1", "2.0.0", "2.1.0"},
+		{"^4", 0, "", "", ""},
+	}
+
+	for _, tc := range tests {
+		c, err := NewConstraint(tc.constraint)
+		if err != nil {
+			t.Errorf("Error parsing constraint %q: %s", tc.constraint, err)
+			continue
+		}
+
+		r := c.Coverage(available)
+		if r.Available != len(available) {
+			t.Errorf("Expected %d available versions, got %d", len(available), r.Available)
+		}
+		if r.Admitted != tc.admitted {
+			t.Errorf("Expected %q to admit %d versions, got %d", tc.constraint, tc.admitted, r.Admitted)
+		}
+
+		check := func(name string, v *Version, expected string) {
+			if expected == "" {
+				if v != nil {
+					t.Errorf("Expected no %s version for %q, got %q", name, tc.constraint, v)
+				}
+				return
+			}
+			if v == nil || v.String() != expected {
+				t.Errorf("Expected %s version %q for %q, got %v", name, expected, tc.constraint, v)
+			}
+		}
+		check("oldest", r.Oldest, tc.oldest)
+		check("newest", r.Newest, tc.newest)
+		check("next excluded", r.NextExcluded, tc.nextExcluded)
+	}
+}
+
+func TestConstraintsMinimumSupported(t *testing.T) {
+	var published Collection
+	for _, s := range []string{"1.4.0", "1.2.0", "2.0.0", "1.3.0-rc.1", "1.3.0"} {
+		published = append(published, MustParse(s))
+	}
+
+	tests := []struct {
+		constraint string
+		expected   string
+	}{
+		{">=1.3", "1.3.0"},
+		{">=1.3.0-0", "1.3.0-rc.1"},
+		{"^1", "1.2.0"},
+		{"^2 || ^1.4", "1.4.0"},
+		{"^3", ""},
+	}
+
+	for _, tc := range tests {
+		c, err := NewConstraint(tc.constraint)
+		if err != nil {
+			t.Errorf("Error parsing constraint %q: %s", tc.constraint, err)
+			continue
+		}
+
+		v, ok := c.MinimumSupported(published)
+		if tc.expected == "" {
+			if ok || v != nil {
+				t.Errorf("Expected no version for %q, got %v", tc.constraint, v)
+			}
+			continue
+		}
+		if !ok || v.String() != tc.expected {
+			t.Errorf("Expected %q to reach back to %q, got %v", tc.constraint, tc.expected, v)
+		}
+	}
+}
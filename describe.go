@@ -0,0 +1,143 @@
+package semver
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Describe returns a plain English description of the constraints suitable
+// for showing to people, such as in a UI or a pull request description. For
+// example, ">=1.2.3, <2.0.0, !=1.4.7" is described as "at least 1.2.3 and
+// below 2.0.0, excluding 1.4.7". Groups separated by || are joined with
+// "; or ".
+func (cs Constraints) Describe() string {
+	buf := make([]string, len(cs.constraints))
+
+	for k, v := range cs.constraints {
+		var incl, excl []string
+		for _, c := range v {
+			if c.origfunc == "!=" && c.isAny() {
+				// != with a wildcard major only excludes 0.0.0. See
+				// constraintNotEqual.
+				excl = append(excl, c.con.String())
+			} else if c.origfunc == "!=" {
+				excl = append(excl, c.describeVersion())
+			} else {
+				incl = append(incl, c.describe())
+			}
+		}
+
+		d := strings.Join(incl, " and ")
+		if len(excl) > 0 {
+			if d == "" {
+				d = "any version"
+			} else {
+				d += ","
+			}
+			d += " excluding " + strings.Join(excl, " and ")
+		}
+		buf[k] = d
+	}
+
+	return strings.Join(buf, "; or ")
+}
+
+// describe returns the description of a single constraint other than an
+// exclusion.
+func (c *constraint) describe() string {
+	var d string
+
+	switch c.origfunc {
+	case "", "=":
+		if !c.dirty {
+			d = "exactly " + c.con.String()
+		} else if c.isAny() {
+			d = "any version"
+		} else {
+			d = fmt.Sprintf("any %s version", c.describeVersion())
+		}
+	case ">":
+		if c.isAny() {
+			d = "above " + c.con.String()
+		} else {
+			d = "above " + c.describeVersion()
+		}
+	case "<":
+		d = "below " + c.con.String()
+	case ">=", "=>":
+		d = "at least " + c.con.String()
+	case "<=", "=<":
+		if c.isAny() {
+			// <=* only admits 0.0.z versions. See constraintLessThanEqual.
+			d = fmt.Sprintf("below %s", c.con.IncMinor())
+		} else {
+			d = "at most " + c.describeVersion()
+		}
+	case "~", "~>":
+		if c.con.Major() == 0 && c.con.Minor() == 0 && c.con.Patch() == 0 &&
+			!c.minorDirty && !c.patchDirty {
+			// ~0.0.0 and ~* admit everything. See constraintTilde.
+			d = "any version"
+		} else {
+			d = fmt.Sprintf("at least %s and below %s", c.con, c.tildeUpper())
+		}
+	case "^":
+		if c.caretSamePatch() {
+			d = fmt.Sprintf("any 0.x.%d version from %s", c.con.Patch(), c.con)
+		} else {
+			d = fmt.Sprintf("at least %s and below %s", c.con, c.caretUpper())
+		}
+	}
+
+	if c.con.Prerelease() != "" {
+		d += " including prereleases"
+	}
+
+	return d
+}
+
+// describeVersion returns the version of the constraint with any missing or
+// wildcard parts written as x.
+func (c *constraint) describeVersion() string {
+	switch {
+	case !c.dirty:
+		return c.con.String()
+	case c.minorDirty:
+		return fmt.Sprintf("%d.x", c.con.Major())
+	case c.patchDirty:
+		return fmt.Sprintf("%d.%d.x", c.con.Major(), c.con.Minor())
+	}
+	return "*"
+}
+
+// isAny reports whether the major version is a wildcard, in which case the
+// equal operator admits any version.
+func (c *constraint) isAny() bool {
+	return c.dirty && !c.minorDirty && !c.patchDirty
+}
+
+// tildeUpper returns the exclusive upper bound of a tilde constraint.
+func (c *constraint) tildeUpper() Version {
+	if c.minorDirty {
+		return c.con.IncMajor()
+	}
+	return c.con.IncMinor()
+}
+
+// caretUpper returns the exclusive upper bound of a caret constraint. When
+// caretSamePatch is true the constraint also admits versions up to 1.0.0.
+func (c *constraint) caretUpper() Version {
+	if c.con.Major() > 0 || c.minorDirty {
+		return c.con.IncMajor()
+	}
+	if c.con.Minor() > 0 || c.patchDirty {
+		return c.con.IncMinor()
+	}
+	return Version{patch: c.con.Patch() + 1}
+}
+
+// caretSamePatch reports whether a caret constraint only checks the patch of
+// the 0.y.z versions above it, as ^0.0.3 and ^* do. See constraintCaret.
+func (c *constraint) caretSamePatch() bool {
+	return c.con.Major() == 0 && c.con.Minor() == 0 && !c.minorDirty && !c.patchDirty
+}
@@ -0,0 +1,53 @@
+package semver
+
+import (
+	"testing"
+)
+
+func TestConstraintsDescribe(t *testing.T) {
+	tests := []struct {
+		constraint string
+		desc       string
+	}{
+		{">=1.2.3, <2.0.0, !=1.4.7", "at least 1.2.3 and below 2.0.0, excluding 1.4.7"},
+		{"1.2.3", "exactly 1.2.3"},
+		{"=1.2.3", "exactly 1.2.3"},
+		{"1.2", "any 1.2.x version"},
+		{"1.x", "any 1.x version"},
+		{"*", "any version"},
+		{"!=1.2.3", "any version excluding 1.2.3"},
+		{"!=1.2.3 !=1.3.x", "any version excluding 1.2.3 and 1.3.x"},
+		{">1.2.3", "above 1.2.3"},
+		{">1.2", "above 1.2.x"},
+		{"<2", "below 2.0.0"},
+		{"<=2.1", "at most 2.1.x"},
+		{"<=2.1.0", "at most 2.1.0"},
+		{"~1.2.3", "at least 1.2.3 and below 1.3.0"},
+		{"~1", "at least 1.0.0 and below 2.0.0"},
+		{"~*", "any version"},
+		{"^1.2.3", "at least 1.2.3 and below 2.0.0"},
+		{"^0.2.3", "at least 0.2.3 and below 0.3.0"},
+		{"^0.0.3", "any 0.x.3 version from 0.0.3"},
+		{"^*", "any 0.x.0 version from 0.0.0"},
+		{"<=*", "below 0.1.0"},
+		{">*", "above 0.0.0"},
+		{"!=*", "any version excluding 0.0.0"},
+		{"^0.0", "at least 0.0.0 and below 0.1.0"},
+		{"^0", "at least 0.0.0 and below 1.0.0"},
+		{">=1.2.3-beta.1", "at least 1.2.3-beta.1 including prereleases"},
+		{"1 - 2", "at least 1.0.0 and at most 2.x"},
+		{"^1.2 || >=3.0.0", "at least 1.2.0 and below 2.0.0; or at least 3.0.0"},
+	}
+
+	for _, tc := range tests {
+		c, err := NewConstraint(tc.constraint)
+		if err != nil {
+			t.Errorf("Error parsing constraint %q: %s", tc.constraint, err)
+			continue
+		}
+
+		if d := c.Describe(); d != tc.desc {
+			t.Errorf("Expected %q to be described as %q, got %q", tc.constraint, tc.desc, d)
+		}
+	}
+}
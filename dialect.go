@@ -0,0 +1,577 @@
+package semver
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Dialect is a constraint syntax used by a package ecosystem. The same
+// string can mean different things in different dialects. For example, 1.2.3
+// is exactly 1.2.3 to this package and RubyGems but ^1.2.3 to Cargo.
+type Dialect int
+
+const (
+	// DialectDefault is the syntax accepted by NewConstraint.
+	DialectDefault Dialect = iota
+
+	// DialectCargo is the syntax of Cargo, the Rust package manager. A
+	// version without an operator is a caret constraint. Terms are ANDed and
+	// there is no || or range syntax.
+	DialectCargo
+
+	// DialectRubyGems is the syntax of RubyGems and Bundler. A partial
+	// version is padded with zeros, so > 1.2 admits 1.2.1, and ~> 1.2 is
+	// >= 1.2.0, < 2.0.0. Terms are ANDed and there are no wildcards, || or
+	// range syntax.
+	DialectRubyGems
+
+	// DialectComposer is the syntax of Composer, the PHP package manager. A
+	// partial version without a wildcard is padded with zeros, ~1.2 is
+	// >=1.2.0 <2.0.0, and | and || are both OR.
+	DialectComposer
+
+	// DialectNpm is the syntax of npm. It is the default dialect without !=
+	// and with terms separated by spaces rather than commas.
+	DialectNpm
+
+	// DialectMaven is the version range syntax of Maven, such as [1.2,2.0)
+	// or (,1.0],[1.5,). Constraints can be written in it but not parsed.
+	DialectMaven
+
+	// DialectPEP440 is the version specifier syntax of pip and other Python
+	// tools, such as >=1.2, <2.0, !=1.4.*. Terms are ANDed and there is no
+	// OR. Constraints can be written in it but not parsed.
+	DialectPEP440
+)
+
+// String returns the name of the dialect.
+func (d Dialect) String() string {
+	switch d {
+	case DialectDefault:
+		return "default"
+	case DialectCargo:
+		return "Cargo"
+	case DialectRubyGems:
+		return "RubyGems"
+	case DialectComposer:
+		return "Composer"
+	case DialectNpm:
+		return "npm"
+	case DialectMaven:
+		return "Maven"
+	case DialectPEP440:
+		return "PEP 440"
+	}
+	return fmt.Sprintf("Dialect(%d)", int(d))
+}
+
+// dialectOps lists the operators each dialect accepts. Every operator is
+// accepted by the default dialect.
+var dialectOps = map[Dialect][]string{
+	DialectCargo:    {"", "=", ">", "<", ">=", "<=", "~", "^"},
+	DialectRubyGems: {"", "=", "!=", ">", "<", ">=", "<=", "~>"},
+	DialectComposer: {"", "=", "!=", ">", "<", ">=", "<=", "~", "^"},
+	DialectNpm:      {"", "=", ">", "<", ">=", "<=", "~", "~>", "^"},
+}
+
+func (d Dialect) hasOp(op string) bool {
+	ops, ok := dialectOps[d]
+	if !ok {
+		return true
+	}
+	for _, o := range ops {
+		if o == op {
+			return true
+		}
+	}
+	return false
+}
+
+func (d Dialect) hasOr() bool {
+	return d == DialectDefault || d == DialectComposer || d == DialectNpm ||
+		d == DialectMaven
+}
+
+func (d Dialect) hasRanges() bool {
+	return d == DialectDefault || d == DialectComposer || d == DialectNpm
+}
+
+// composerReplacer rewrites the Composer operators with a different spelling
+// in the default dialect. "||" is listed first so it is left alone.
+var composerReplacer = strings.NewReplacer("||", "||", "|", "||", "==", "=", "<>", "!=")
+
+// ParseConstraintIn parses a constraint string written in dialect d. The
+// result is checked the way the ecosystem checks it. Its String method
+// returns the equivalent constraint in the default dialect while StringIn
+// writes it back in a dialect. An error is returned for syntax the dialect
+// does not support.
+func ParseConstraintIn(c string, d Dialect) (*Constraints, error) {
+	if MaxConstraintLength > 0 && len(c) > MaxConstraintLength {
+		return nil, ErrConstraintTooLong
+	}
+
+	if d == DialectMaven || d == DialectPEP440 {
+		return nil, fmt.Errorf("parsing %s constraints is not supported", d)
+	}
+
+	if d == DialectComposer {
+		c = composerReplacer.Replace(c)
+	}
+
+	p := &constraintParser{s: c, dialect: d}
+	or, err := p.parse()
+	if err != nil {
+		return nil, err
+	}
+
+	o := &Constraints{constraints: or}
+	return o, nil
+}
+
+// comparison creates the constraints for a comparison written in the
+// parser's dialect.
+func (p *constraintParser) comparison(op string, cv constraintVersion) ([]*constraint, error) {
+	if !p.dialect.hasOp(op) {
+		return nil, fmt.Errorf("the %s operator is not supported in %s constraints", op, p.dialect)
+	}
+
+	switch p.dialect {
+	case DialectCargo:
+		if op == "" && !cv.wildcard() {
+			op = "^"
+		}
+	case DialectRubyGems:
+		if cv.wildcard() {
+			return nil, errImproperConstraint
+		}
+		if op == "~>" {
+			return pessimistic(cv)
+		}
+		cv = cv.padded()
+	case DialectComposer:
+		if op == "~" {
+			if cv.wildcard() {
+				return nil, errImproperConstraint
+			}
+			return pessimistic(cv)
+		}
+		if op != "^" && !cv.wildcard() {
+			cv = cv.padded()
+		}
+	}
+
+	c, err := newConstraint(op, cv)
+	if err != nil {
+		return nil, err
+	}
+	return []*constraint{c}, nil
+}
+
+// pessimistic expands a RubyGems ~> or Composer ~ comparison, which allows
+// the last part written to increase, into its bounds. ~> 1.2 is
+// >= 1.2.0, < 2.0.0, ~> 1.2.3 is >= 1.2.3, < 1.3.0 and ~> 1.2.3-beta is
+// >= 1.2.3-beta, < 1.3.0-0.
+func pessimistic(cv constraintVersion) ([]*constraint, error) {
+	min, err := newConstraint(">=", cv.padded())
+	if err != nil {
+		return nil, err
+	}
+
+	var upper string
+	if cv.patch == "" {
+		if min.con.Major() == math.MaxUint64 {
+			return nil, ErrSegmentOverflow
+		}
+		upper = strconv.FormatUint(min.con.Major()+1, 10) + ".0.0"
+	} else {
+		if min.con.Minor() == math.MaxUint64 {
+			return nil, ErrSegmentOverflow
+		}
+		upper = fmt.Sprintf("%d.%d.0", min.con.Major(), min.con.Minor()+1)
+	}
+
+	// A bound without a prerelease rejects every prerelease, so when prereleases
+	// are admitted the bound is the lowest prerelease of the upper version.
+	parts := strings.Split(upper, ".")
+	ucv := constraintVersion{
+		orig:  upper,
+		major: parts[0],
+		minor: parts[1],
+		patch: parts[2],
+	}
+	if cv.pre != "" {
+		ucv.orig += "-0"
+		ucv.pre = "0"
+	}
+	max, err := newConstraint("<", ucv)
+	if err != nil {
+		return nil, err
+	}
+
+	return []*constraint{min, max}, nil
+}
+
+// wildcard reports whether any part of the version is a wildcard.
+func (cv constraintVersion) wildcard() bool {
+	return isX(cv.major) || isX(cv.minor) || isX(cv.patch)
+}
+
+// padded returns the version with missing minor and patch parts set to 0 so
+// it is an exact version rather than a partial one.
+func (cv constraintVersion) padded() constraintVersion {
+	n := strings.IndexAny(cv.orig, "-+")
+	if n == -1 {
+		n = len(cv.orig)
+	}
+	num, rest := cv.orig[:n], cv.orig[n:]
+
+	if cv.minor == "" {
+		cv.minor = "0"
+		num += ".0"
+	}
+	if cv.patch == "" {
+		cv.patch = "0"
+		num += ".0"
+	}
+	cv.orig = num + rest
+	return cv
+}
+
+// canonicalOps maps the operators with more than one spelling to a single
+// one.
+var canonicalOps = map[string]string{
+	"":   "=",
+	"=>": ">=",
+	"=<": "<=",
+	"~>": "~",
+}
+
+// StringIn writes the constraints in dialect d. The result admits the same
+// versions as the constraints, although it may be spelled differently from
+// the string they were parsed from. Options such as IncludePrerelease are
+// not included. An error is returned when the constraints cannot be written
+// in d, such as a != when d is DialectCargo.
+func (cs Constraints) StringIn(d Dialect) (string, error) {
+	if d == DialectDefault {
+		return cs.String(), nil
+	}
+	if len(cs.constraints) > 1 && !d.hasOr() {
+		return "", fmt.Errorf("|| is not supported in %s constraints", d)
+	}
+	if d == DialectMaven {
+		return cs.stringMaven()
+	}
+
+	sep := ", "
+	if d == DialectNpm {
+		sep = " "
+	}
+
+	buf := make([]string, len(cs.constraints))
+	for k, v := range cs.constraints {
+		var terms []string
+		for _, c := range v {
+			t, err := c.stringIn(d)
+			if err != nil {
+				return "", err
+			}
+			terms = append(terms, t...)
+		}
+		buf[k] = strings.Join(terms, sep)
+	}
+
+	return strings.Join(buf, " || "), nil
+}
+
+// stringIn writes a single constraint in dialect d. A constraint may need
+// more than one term in d.
+func (c *constraint) stringIn(d Dialect) ([]string, error) {
+	op := c.origfunc
+	if o, ok := canonicalOps[op]; ok {
+		op = o
+	}
+
+	if c.dirty && c.con.Prerelease() != "" {
+		return nil, fmt.Errorf("%s cannot be written in %s syntax", c.string(), d)
+	}
+
+	// =*, >=*, ~* and ~0.0.0 admit every release. The other operators give
+	// a wildcard major version a narrower meaning, such as <=* being <0.1.0,
+	// and are written below.
+	if c.con.Prerelease() == "" && op != "^" && (c.isAny() && (op == "=" || op == "~" || op == ">=") ||
+		(op == "~" && c.con.Major() == 0 && c.con.Minor() == 0 && c.con.Patch() == 0 &&
+			!c.minorDirty && !c.patchDirty)) {
+		switch d {
+		case DialectRubyGems:
+			return []string{">= 0"}, nil
+		case DialectPEP440:
+			return []string{">=0"}, nil
+		}
+		return []string{"*"}, nil
+	}
+
+	if d == DialectPEP440 {
+		return c.stringPEP440(op)
+	}
+
+	v := c.con.String()
+
+	if d == DialectCargo || d == DialectNpm {
+		// Partial versions mean the same in Cargo and npm as they do here.
+		switch op {
+		case "!=":
+			return nil, fmt.Errorf("the != operator is not supported in %s constraints", d)
+		case "=":
+			if c.dirty {
+				return []string{c.partialVersion() + ".*"}, nil
+			}
+			return []string{"=" + v}, nil
+		}
+		return []string{op + c.partialVersion()}, nil
+	}
+
+	// RubyGems separates the operator from the version with a space.
+	sp := ""
+	if d == DialectRubyGems {
+		sp = " "
+	}
+
+	switch op {
+	case "=":
+		if !c.dirty {
+			if d == DialectRubyGems {
+				return []string{"= " + v}, nil
+			}
+			return []string{v}, nil
+		}
+		if d == DialectComposer {
+			return []string{c.partialVersion() + ".*"}, nil
+		}
+		return []string{"~> " + c.pessimisticVersion()}, nil
+	case "~":
+		// ~0.0.0-beta admits everything from 0.0.0-beta on, as pessimistic
+		// comparisons do not.
+		if c.con.Major() == 0 && c.con.Minor() == 0 && c.con.Patch() == 0 &&
+			!c.minorDirty && !c.patchDirty {
+			return []string{">=" + sp + v}, nil
+		}
+		if d == DialectRubyGems {
+			return []string{"~> " + c.pessimisticVersion()}, nil
+		}
+		return []string{"~" + c.pessimisticVersion()}, nil
+	case "^":
+		if d == DialectComposer {
+			return []string{"^" + c.partialVersion()}, nil
+		}
+		if c.caretSamePatch() {
+			return nil, fmt.Errorf("%s cannot be written in %s syntax", c.string(), d)
+		}
+		return []string{">= " + v, "< " + c.upperString(c.caretUpper())}, nil
+	case ">":
+		// The version after 1.2.x is the upper bound of ~1.2. >* is >0.0.0.
+		if c.dirty && !c.isAny() {
+			return []string{">=" + sp + c.tildeUpper().String()}, nil
+		}
+	case "<=":
+		if c.dirty {
+			return []string{"<" + sp + c.tildeUpper().String()}, nil
+		}
+	case "!=":
+		if c.dirty {
+			return nil, fmt.Errorf("%s cannot be written in %s syntax", c.string(), d)
+		}
+	}
+
+	return []string{op + sp + v}, nil
+}
+
+// upperString writes u as the exclusive upper bound of the constraint. When
+// the constraint has a prerelease it admits prereleases below u, which a
+// bound without a prerelease would reject, so the lowest prerelease of u is
+// written instead.
+func (c *constraint) upperString(u Version) string {
+	if c.con.Prerelease() != "" {
+		return u.String() + "-0"
+	}
+	return u.String()
+}
+
+// partialVersion returns the version of the constraint with only the parts
+// that were written, or * for a wildcard major version.
+func (c *constraint) partialVersion() string {
+	switch {
+	case c.isAny():
+		return "*"
+	case c.minorDirty:
+		return strconv.FormatUint(c.con.Major(), 10)
+	case c.patchDirty:
+		return fmt.Sprintf("%d.%d", c.con.Major(), c.con.Minor())
+	}
+	return c.con.String()
+}
+
+// pessimisticVersion returns the version for a RubyGems ~> or Composer ~
+// comparison with the same range as the constraint used as a tilde range.
+// ~1 is ~> 1.0, ~1.2 is ~> 1.2.0 and ~1.2.3 is ~> 1.2.3.
+func (c *constraint) pessimisticVersion() string {
+	switch {
+	case c.minorDirty:
+		return fmt.Sprintf("%d.0", c.con.Major())
+	case c.patchDirty:
+		return fmt.Sprintf("%d.%d.0", c.con.Major(), c.con.Minor())
+	}
+	return c.con.String()
+}
+
+// stringPEP440 writes a single constraint as PEP 440 specifiers. op is the
+// canonical operator of the constraint.
+func (c *constraint) stringPEP440(op string) ([]string, error) {
+	switch {
+	case op == "!=" && c.isAny(), op == "^" && c.caretSamePatch():
+		return nil, fmt.Errorf("%s cannot be written in %s syntax", c.string(), DialectPEP440)
+	case op == "~" && c.con.Major() == 0 && c.con.Minor() == 0 && c.con.Patch() == 0 &&
+		!c.minorDirty && !c.patchDirty:
+		// ~0.0.0-beta admits everything from 0.0.0-beta on.
+		op = ">="
+	case c.dirty:
+		switch op {
+		case "=":
+			return []string{"==" + c.partialVersion() + ".*"}, nil
+		case "!=":
+			return []string{"!=" + c.partialVersion() + ".*"}, nil
+		case "~":
+			// ~=1.2 is >=1.2, ==1.*, the same range as ~1.
+			return []string{"~=" + c.pessimisticVersion()}, nil
+		case ">":
+			// >* is >0.0.0.
+			if !c.isAny() {
+				return []string{">=" + c.tildeUpper().String()}, nil
+			}
+		case "<=":
+			return []string{"<" + c.tildeUpper().String()}, nil
+		}
+	}
+
+	v, err := pep440Version(c.con)
+	if err != nil {
+		return nil, err
+	}
+
+	switch op {
+	case "=":
+		return []string{"==" + v}, nil
+	case "~":
+		return []string{"~=" + v}, nil
+	case "^":
+		return []string{">=" + v, "<" + c.caretUpper().String()}, nil
+	}
+	return []string{op + v}, nil
+}
+
+// pep440Tags maps the prerelease tags with a PEP 440 spelling to it.
+var pep440Tags = map[string]string{
+	"alpha": "a",
+	"a":     "a",
+	"beta":  "b",
+	"b":     "b",
+	"rc":    "rc",
+	"c":     "rc",
+}
+
+// pep440Version writes v as a PEP 440 version. Only prereleases such as
+// alpha, beta.2 and rc.1, which are 1.2.3a0, 1.2.3b2 and 1.2.3rc1 in PEP 440,
+// can be written. Build metadata cannot.
+func pep440Version(v *Version) (string, error) {
+	if v.Metadata() != "" {
+		return "", fmt.Errorf("build metadata in %s cannot be written in %s syntax", v, DialectPEP440)
+	}
+
+	s := fmt.Sprintf("%d.%d.%d", v.Major(), v.Minor(), v.Patch())
+	if v.Prerelease() == "" {
+		return s, nil
+	}
+
+	ids := strings.Split(v.Prerelease(), ".")
+	tag, ok := pep440Tags[ids[0]]
+	if !ok || len(ids) > 2 {
+		return "", fmt.Errorf("the prerelease in %s cannot be written in %s syntax", v, DialectPEP440)
+	}
+	n := "0"
+	if len(ids) == 2 {
+		if _, err := strconv.ParseUint(ids[1], 10, 64); err != nil {
+			return "", fmt.Errorf("the prerelease in %s cannot be written in %s syntax", v, DialectPEP440)
+		}
+		n = ids[1]
+	}
+
+	return s + tag + n, nil
+}
+
+// stringMaven writes the constraints as Maven version ranges. Maven has no
+// AND or exclusions, so each group is written as the ranges left after
+// removing its != terms from the intersection of the others, and all the
+// ranges are joined with commas, which Maven treats as OR.
+func (cs Constraints) stringMaven() (string, error) {
+	var buf []string
+	for _, v := range cs.constraints {
+		ranges := []versionRange{{}}
+		for _, c := range v {
+			b := c.bound()
+			if b.SamePatch {
+				return "", fmt.Errorf("%s cannot be written in %s syntax", c.string(), DialectMaven)
+			}
+			var next []versionRange
+			for _, r := range ranges {
+				if b.Excluded {
+					next = append(next, r.subtract(b)...)
+				} else if r = r.intersect(b); !r.empty() {
+					next = append(next, r)
+				}
+			}
+			ranges = next
+		}
+
+		for _, r := range ranges {
+			buf = append(buf, r.mavenString())
+		}
+	}
+
+	if len(buf) == 0 {
+		return "", fmt.Errorf("%s admits no versions and cannot be written in %s syntax", cs.String(), DialectMaven)
+	}
+	return strings.Join(buf, ","), nil
+}
+
+// mavenString writes the range in Maven syntax, such as [1.2.0,2.0.0) or
+// [1.2.3] for a single version.
+func (r versionRange) mavenString() string {
+	if r.min != nil && r.max != nil && r.min.Equal(r.max) {
+		return "[" + r.min.String() + "]"
+	}
+
+	var sb strings.Builder
+	switch {
+	case r.min == nil && r.max == nil:
+		// Maven has no range without bounds.
+		return "[0,)"
+	case r.min == nil || !r.incMin:
+		sb.WriteByte('(')
+	default:
+		sb.WriteByte('[')
+	}
+	if r.min != nil {
+		sb.WriteString(r.min.String())
+	}
+	sb.WriteByte(',')
+	if r.max != nil {
+		sb.WriteString(r.max.String())
+	}
+	if r.max != nil && r.incMax {
+		sb.WriteByte(']')
+	} else {
+		sb.WriteByte(')')
+	}
+	return sb.String()
+}
@@ -0,0 +1,389 @@
+package semver
+
+import (
+	"math/rand"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestParseConstraintIn(t *testing.T) {
+	tests := []struct {
+		constraint string
+		dialect    Dialect
+		version    string
+		check      bool
+	}{
+		{"1.2.3", DialectDefault, "1.4.0", false},
+		{"1.2.3", DialectCargo, "1.4.0", true},
+		{"1.2.3", DialectCargo, "2.0.0", false},
+		{"=1.2.3", DialectCargo, "1.4.0", false},
+		{"1.2.*", DialectCargo, "1.2.9", true},
+		{"1.2.*", DialectCargo, "1.3.0", false},
+		{"0.2", DialectCargo, "0.2.9", true},
+		{"0.2", DialectCargo, "0.3.0", false},
+		{">=1.2, <1.5", DialectCargo, "1.4.9", true},
+		{"~1.2.3", DialectCargo, "1.3.0", false},
+		{"~> 1.2", DialectRubyGems, "1.9.0", true},
+		{"~> 1.2", DialectRubyGems, "1.1.0", false},
+		{"~> 1.2", DialectRubyGems, "2.0.0", false},
+		{"~> 1.2.3", DialectRubyGems, "1.2.9", true},
+		{"~> 1.2.3", DialectRubyGems, "1.3.0", false},
+		{"~> 1", DialectRubyGems, "1.9.0", true},
+		{"~> 1", DialectRubyGems, "2.0.0", false},
+		{"~> 1.2.3-beta", DialectRubyGems, "1.2.3-beta.2", true},
+		{"~> 1.2.3-beta", DialectRubyGems, "1.2.4-alpha", true},
+		{"~> 1.2.3-beta", DialectRubyGems, "1.3.0-alpha", false},
+		{"1.2", DialectRubyGems, "1.2.0", true},
+		{"1.2", DialectRubyGems, "1.2.1", false},
+		{"> 1.2", DialectRubyGems, "1.2.1", true},
+		{"> 1.2", DialectDefault, "1.2.1", false},
+		{"~> 1.2, != 1.4.0", DialectRubyGems, "1.4.0", false},
+		{"~> 1.2, != 1.4.0", DialectRubyGems, "1.4.1", true},
+		{"~1.2", DialectComposer, "1.9.0", true},
+		{"~1.2", DialectComposer, "2.0.0", false},
+		{"~1.2.3", DialectComposer, "1.3.0", false},
+		{"~1.2.3-beta", DialectComposer, "1.2.4-alpha", true},
+		{"^0.3", DialectComposer, "0.3.5", true},
+		{"^0.3", DialectComposer, "0.4.0", false},
+		{"1.0.*", DialectComposer, "1.0.7", true},
+		{"1.2", DialectComposer, "1.2.1", false},
+		{"<>1.2.3", DialectComposer, "1.2.3", false},
+		{"==1.2.3", DialectComposer, "1.2.3", true},
+		{"^1.0 | ^2.0", DialectComposer, "2.5.0", true},
+		{"^1.0 || ^2.0", DialectComposer, "3.0.0", false},
+		{"1.0 - 2.0", DialectComposer, "2.0.9", true},
+		{"^1.2.3 <1.5", DialectNpm, "1.4.9", true},
+		{"^1.2.3 <1.5", DialectNpm, "1.5.0", false},
+		{"1.2.x || >=2.1.0", DialectNpm, "2.1.0", true},
+		{"~>1.2", DialectNpm, "1.3.0", false},
+	}
+
+	for _, tc := range tests {
+		c, err := ParseConstraintIn(tc.constraint, tc.dialect)
+		if err != nil {
+			t.Errorf("Error parsing %s constraint %q: %s", tc.dialect, tc.constraint, err)
+			continue
+		}
+
+		v, err := NewVersion(tc.version)
+		if err != nil {
+			t.Errorf("Error parsing version: %s", err)
+			continue
+		}
+
+		if a := c.Check(v); a != tc.check {
+			t.Errorf("Expected %s constraint %q check of %q to be %t", tc.dialect, tc.constraint, tc.version, tc.check)
+		}
+	}
+}
+
+func TestParseConstraintInErrors(t *testing.T) {
+	tests := []struct {
+		constraint string
+		dialect    Dialect
+		err        string
+	}{
+		{"^1 || ^2", DialectCargo, "|| is not supported in Cargo constraints"},
+		{"1 - 2", DialectCargo, "ranges are not supported in Cargo constraints"},
+		{"!=1.2.3", DialectCargo, "the != operator is not supported in Cargo constraints"},
+		{"^1.2", DialectRubyGems, "the ^ operator is not supported in RubyGems constraints"},
+		{"~ 1.2", DialectRubyGems, "the ~ operator is not supported in RubyGems constraints"},
+		{"1.x", DialectRubyGems, "improper constraint: 1.x"},
+		{"~> 1.2 || ~> 2.0", DialectRubyGems, "|| is not supported in RubyGems constraints"},
+		{"~>1.2", DialectComposer, "the ~> operator is not supported in Composer constraints"},
+		{"~1.*", DialectComposer, "improper constraint: ~1.*"},
+		{"!=1.2.3", DialectNpm, "the != operator is not supported in npm constraints"},
+		{"[1.2,2.0)", DialectMaven, "parsing Maven constraints is not supported"},
+		{">=1.2, <2.0", DialectPEP440, "parsing PEP 440 constraints is not supported"},
+	}
+
+	for _, tc := range tests {
+		_, err := ParseConstraintIn(tc.constraint, tc.dialect)
+		if err == nil {
+			t.Errorf("Expected error parsing %s constraint %q", tc.dialect, tc.constraint)
+			continue
+		}
+		if err.Error() != tc.err {
+			t.Errorf("Expected error %q parsing %s constraint %q, got %q", tc.err, tc.dialect, tc.constraint, err)
+		}
+	}
+}
+
+func TestConstraintsStringIn(t *testing.T) {
+	tests := []struct {
+		constraint string
+		dialect    Dialect
+		expected   string
+		err        bool
+	}{
+		{"^1.2 || >=3.0.0", DialectDefault, "^1.2 || >=3.0.0", false},
+		{"^1.2", DialectCargo, "^1.2", false},
+		{"1.2.3", DialectCargo, "=1.2.3", false},
+		{"1.2.x", DialectCargo, "1.2.*", false},
+		{"*", DialectCargo, "*", false},
+		{"~>1.2, <1.2.5", DialectCargo, "~1.2, <1.2.5", false},
+		{"!=1.2.3", DialectCargo, "", true},
+		{"^1 || ^2", DialectCargo, "", true},
+		{"1.2.3", DialectRubyGems, "= 1.2.3", false},
+		{"1.2.x", DialectRubyGems, "~> 1.2.0", false},
+		{"1.x", DialectRubyGems, "~> 1.0", false},
+		{"~1.2.3", DialectRubyGems, "~> 1.2.3", false},
+		{"^1.2", DialectRubyGems, ">= 1.2.0, < 2.0.0", false},
+		{"^0.1.3", DialectRubyGems, ">= 0.1.3, < 0.2.0", false},
+		{"^0.0.3", DialectRubyGems, "", true},
+		{"^1.3.0-alpha", DialectRubyGems, ">= 1.3.0-alpha, < 2.0.0-0", false},
+		{"<=*", DialectRubyGems, "< 0.1.0", false},
+		{">*", DialectRubyGems, "> 0.0.0", false},
+		{"~0.0.0-beta", DialectRubyGems, ">= 0.0.0-beta", false},
+		{">1.2", DialectRubyGems, ">= 1.3.0", false},
+		{"<=1", DialectRubyGems, "< 2.0.0", false},
+		{"<1.2, !=1.1.0", DialectRubyGems, "< 1.2.0, != 1.1.0", false},
+		{"*", DialectRubyGems, ">= 0", false},
+		{"~0.0.0", DialectRubyGems, ">= 0", false},
+		{"!=1.x", DialectRubyGems, "", true},
+		{"1.2.3", DialectComposer, "1.2.3", false},
+		{"1.x", DialectComposer, "1.*", false},
+		{"~1", DialectComposer, "~1.0", false},
+		{"~1.2", DialectComposer, "~1.2.0", false},
+		{"^0.2 || >=1.2 <=1.4", DialectComposer, "^0.2 || >=1.2.0, <1.5.0", false},
+		{"1.2.x-beta", DialectComposer, "", true},
+		{"<=x.x", DialectComposer, "<0.1.0", false},
+		{"^x.1", DialectComposer, "^*", false},
+		{"^1.2 || 1.2.3 - 1.4", DialectNpm, "^1.2 || >=1.2.3 <=1.4", false},
+		{">=1.2.3, <2", DialectNpm, ">=1.2.3 <2", false},
+		{"1.2.x", DialectNpm, "1.2.*", false},
+		{"!=1.2.3", DialectNpm, "", true},
+		{"<=x.x", DialectNpm, "<=*", false},
+		{"=x.0.3", DialectNpm, "*", false},
+		{"<0.1.X", DialectNpm, "<0.1", false},
+	}
+
+	for _, tc := range tests {
+		c, err := NewConstraint(tc.constraint)
+		if err != nil {
+			t.Errorf("Error parsing constraint %q: %s", tc.constraint, err)
+			continue
+		}
+
+		s, err := c.StringIn(tc.dialect)
+		if tc.err {
+			if err == nil {
+				t.Errorf("Expected error writing %q in %s syntax, got %q", tc.constraint, tc.dialect, s)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Error writing %q in %s syntax: %s", tc.constraint, tc.dialect, err)
+			continue
+		}
+		if s != tc.expected {
+			t.Errorf("Expected %q in %s syntax to be %q, got %q", tc.constraint, tc.dialect, tc.expected, s)
+		}
+
+		// Writing the constraints in a dialect and parsing them back
+		// must not change what they admit.
+		back, err := ParseConstraintIn(s, tc.dialect)
+		if err != nil {
+			t.Errorf("Error parsing %q in %s syntax: %s", s, tc.dialect, err)
+			continue
+		}
+		for _, ver := range []string{"0.0.3", "0.2.5", "1.0.0", "1.1.0", "1.2.0", "1.2.3", "1.2.9", "1.3.0", "1.4.5", "2.0.0", "3.1.0"} {
+			v := MustParse(ver)
+			if c.Check(v) != back.Check(v) {
+				t.Errorf("Expected %q and %s %q to agree on %s", tc.constraint, tc.dialect, s, ver)
+			}
+		}
+	}
+}
+
+func TestConstraintsStringInWriteOnly(t *testing.T) {
+	tests := []struct {
+		constraint string
+		dialect    Dialect
+		expected   string
+		err        bool
+	}{
+		{"^1.2", DialectMaven, "[1.2.0,2.0.0)", false},
+		{"1.2.3", DialectMaven, "[1.2.3]", false},
+		{">1.2.3", DialectMaven, "(1.2.3,)", false},
+		{"<=1.0 || >=1.5", DialectMaven, "(,1.1.0),[1.5.0,)", false},
+		{"<=1.0.0 || >=1.5", DialectMaven, "(,1.0.0],[1.5.0,)", false},
+		{">=1.2, <2, !=1.4.0", DialectMaven, "[1.2.0,1.4.0),(1.4.0,2.0.0)", false},
+		{"^1.2, !=1.x", DialectMaven, "", true},
+		{"~1.2.3, !=1.2.x", DialectMaven, "", true},
+		{"!=1.2.x", DialectMaven, "(,1.2.0),[1.3.0,)", false},
+		{"*", DialectMaven, "[0,)", false},
+		{">=1.0.0-rc.1, <1.0.0", DialectMaven, "[1.0.0-rc.1,1.0.0)", false},
+		{"^0.0.3", DialectMaven, "", true},
+		{"<=x.x", DialectMaven, "(,0.1.0)", false},
+		{"^1.2", DialectPEP440, ">=1.2.0, <2.0.0", false},
+		{"1.2.x", DialectPEP440, "==1.2.*", false},
+		{"1.2.3", DialectPEP440, "==1.2.3", false},
+		{"~1", DialectPEP440, "~=1.0", false},
+		{"~1.2.3, !=1.2.5", DialectPEP440, "~=1.2.3, !=1.2.5", false},
+		{"!=1.x", DialectPEP440, "!=1.*", false},
+		{">1.2, <=2", DialectPEP440, ">=1.3.0, <3.0.0", false},
+		{">=1.0.0-rc.1", DialectPEP440, ">=1.0.0rc1", false},
+		{">=1.0.0-beta", DialectPEP440, ">=1.0.0b0", false},
+		{"*", DialectPEP440, ">=0", false},
+		{"<=x.x", DialectPEP440, "<0.1.0", false},
+		{">*", DialectPEP440, ">0.0.0", false},
+		{"!=*", DialectPEP440, "", true},
+		{"^*", DialectPEP440, "", true},
+		{">=1.0.0-dev.1", DialectPEP440, "", true},
+		{"=1.0.0+build.1", DialectPEP440, "", true},
+		{"^1 || ^2", DialectPEP440, "", true},
+	}
+
+	for _, tc := range tests {
+		c, err := NewConstraint(tc.constraint)
+		if err != nil {
+			t.Errorf("Error parsing constraint %q: %s", tc.constraint, err)
+			continue
+		}
+
+		s, err := c.StringIn(tc.dialect)
+		if tc.err {
+			if err == nil {
+				t.Errorf("Expected error writing %q in %s syntax, got %q", tc.constraint, tc.dialect, s)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Error writing %q in %s syntax: %s", tc.constraint, tc.dialect, err)
+			continue
+		}
+		if s != tc.expected {
+			t.Errorf("Expected %q in %s syntax to be %q, got %q", tc.constraint, tc.dialect, tc.expected, s)
+		}
+	}
+}
+
+func TestConstraintsStringInGenerated(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	opts := &GenerateOptions{MaxSegment: 3, Prerelease: 0.3, MaxIdentifiers: 2}
+
+	var versions []*Version
+	for i := 0; i < 200; i++ {
+		versions = append(versions, GenerateVersion(r, opts))
+	}
+
+	for _, d := range []Dialect{DialectCargo, DialectRubyGems, DialectComposer, DialectNpm} {
+		for i := 0; i < 1000; i++ {
+			c := GenerateConstraint(r, opts)
+			s, err := c.StringIn(d)
+			if err != nil {
+				continue
+			}
+
+			back, err := ParseConstraintIn(s, d)
+			if err != nil {
+				t.Errorf("Error parsing %q, written from %q, in %s syntax: %s", s, c, d, err)
+				continue
+			}
+			for _, v := range versions {
+				if c.Check(v) != back.Check(v) {
+					t.Errorf("Expected %q and %s %q to agree on %s", c, d, s, v)
+					break
+				}
+			}
+		}
+	}
+}
+
+func TestConstraintsStringInWriteOnlyGenerated(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	opts := &GenerateOptions{MaxSegment: 3, Prerelease: 0.3, MaxIdentifiers: 2}
+
+	// Maven and PEP 440 order prereleases differently, so only releases are
+	// compared.
+	var versions []*Version
+	for x := uint64(0); x <= 4; x++ {
+		for y := uint64(0); y <= 4; y++ {
+			for z := uint64(0); z <= 4; z++ {
+				versions = append(versions, newRelease([3]uint64{x, y, z}))
+			}
+		}
+	}
+
+	for _, d := range []Dialect{DialectMaven, DialectPEP440} {
+		for i := 0; i < 1000; i++ {
+			c := GenerateConstraint(r, opts)
+			s, err := c.StringIn(d)
+			if err != nil {
+				continue
+			}
+
+			back, err := NewConstraint(writeOnlyToDefault(s, d))
+			if err != nil {
+				t.Errorf("Error reading %q, written from %q, in %s syntax: %s", s, c, d, err)
+				continue
+			}
+			for _, v := range versions {
+				if c.Check(v) != back.Check(v) {
+					t.Errorf("Expected %q and %s %q to agree on %s", c, d, s, v)
+					break
+				}
+			}
+		}
+	}
+}
+
+// pep440Pre matches the prereleases pep440Version writes, such as rc1.
+var pep440Pre = regexp.MustCompile(`^([0-9.]+)(a|b|rc)([0-9]+)$`)
+
+// writeOnlyToDefault translates constraints written by StringIn in Maven or
+// PEP 440 syntax to the default dialect.
+func writeOnlyToDefault(s string, d Dialect) string {
+	if d == DialectMaven {
+		var or []string
+		for _, r := range regexp.MustCompile(`[\[(][^\])]*[\])]`).FindAllString(s, -1) {
+			body := r[1 : len(r)-1]
+			if !strings.Contains(body, ",") {
+				or = append(or, "="+body)
+				continue
+			}
+			parts := strings.SplitN(body, ",", 2)
+			var and []string
+			if parts[0] != "" {
+				op := ">"
+				if r[0] == '[' {
+					op = ">="
+				}
+				and = append(and, op+parts[0])
+			}
+			if parts[1] != "" {
+				op := "<"
+				if r[len(r)-1] == ']' {
+					op = "<="
+				}
+				and = append(and, op+parts[1])
+			}
+			or = append(or, strings.Join(and, " "))
+		}
+		return strings.Join(or, " || ")
+	}
+
+	var and []string
+	for _, term := range strings.Split(s, ", ") {
+		n := strings.IndexAny(term, "0123456789")
+		op, v := term[:n], term[n:]
+		num := v
+		if m := pep440Pre.FindStringSubmatch(v); m != nil {
+			num = m[1]
+			v = m[1] + "-" + map[string]string{"a": "alpha", "b": "beta", "rc": "rc"}[m[2]] + "." + m[3]
+		}
+		switch op {
+		case "==":
+			op = "="
+		case "~=":
+			// ~=1.2 is >=1.2, ==1.* and ~=1.2.3 is >=1.2.3, ==1.2.*.
+			parts := strings.Split(num, ".")
+			and = append(and, ">="+v, "="+strings.Join(parts[:len(parts)-1], ".")+".x")
+			continue
+		}
+		and = append(and, op+strings.Replace(v, "*", "x", 1))
+	}
+	return strings.Join(and, " ")
+}
@@ -0,0 +1,116 @@
+package semver
+
+import (
+	"math"
+)
+
+// Change is the most significant part of a version that differs between two
+// versions.
+type Change int
+
+const (
+	// ChangeNone is no change in precedence. The build metadata may differ.
+	ChangeNone Change = iota
+
+	// ChangePrerelease is a change to the prerelease only, such as
+	// 1.2.3-rc.1 to 1.2.3-rc.2 or 1.2.3.
+	ChangePrerelease
+
+	// ChangePatch is a change to the patch version.
+	ChangePatch
+
+	// ChangeMinor is a change to the minor version.
+	ChangeMinor
+
+	// ChangeMajor is a change to the major version.
+	ChangeMajor
+)
+
+// String returns the name of the change.
+func (c Change) String() string {
+	switch c {
+	case ChangeNone:
+		return "none"
+	case ChangePrerelease:
+		return "prerelease"
+	case ChangePatch:
+		return "patch"
+	case ChangeMinor:
+		return "minor"
+	case ChangeMajor:
+		return "major"
+	}
+	return "unknown"
+}
+
+// VersionDiff describes how one version differs from another. It is returned
+// by Diff.
+type VersionDiff struct {
+	// Change is the most significant part that differs.
+	Change Change
+
+	// Delta is how much the number for Change moved, such as 2 for 1.2.3 to
+	// 1.4.0 or -1 for 2.0.0 to 1.0.0. It saturates at the bounds of int64
+	// and is 0 for ChangeNone and ChangePrerelease.
+	Delta int64
+
+	// Direction is the result of comparing the second version to the
+	// first, so 1 is an upgrade and -1 a downgrade.
+	Direction int
+}
+
+// Diff reports the most significant part of the version that differs between
+// a and b and by how much. Build metadata is ignored, as it is by Compare.
+func Diff(a, b *Version) VersionDiff {
+	d := VersionDiff{Direction: b.Compare(a)}
+
+	switch {
+	case a.major != b.major:
+		d.Change, d.Delta = ChangeMajor, delta(a.major, b.major)
+	case a.minor != b.minor:
+		d.Change, d.Delta = ChangeMinor, delta(a.minor, b.minor)
+	case a.patch != b.patch:
+		d.Change, d.Delta = ChangePatch, delta(a.patch, b.patch)
+	case a.pre != b.pre:
+		d.Change = ChangePrerelease
+	}
+
+	return d
+}
+
+// IsBreakingUpgrade reports whether going from one version to a higher one
+// may break compatibility, following the same rules as the ^ operator. A
+// change to the major version is breaking, as is a change to the minor
+// version while the major version is 0 and a change to the patch version
+// while both are 0. Going to a prerelease of such a version, such as 1.5.0 to
+// 2.0.0-rc.1, is breaking too. A downgrade is never an upgrade and so is not
+// reported as breaking.
+func IsBreakingUpgrade(from, to *Version) bool {
+	if !to.GreaterThan(from) {
+		return false
+	}
+
+	switch Diff(from, to).Change {
+	case ChangeMajor:
+		return true
+	case ChangeMinor:
+		return from.major == 0
+	case ChangePatch:
+		return from.major == 0 && from.minor == 0
+	}
+	return false
+}
+
+// delta returns b - a, saturating at the bounds of int64.
+func delta(a, b uint64) int64 {
+	if b >= a {
+		if b-a > math.MaxInt64 {
+			return math.MaxInt64
+		}
+		return int64(b - a)
+	}
+	if a-b > math.MaxInt64 {
+		return math.MinInt64
+	}
+	return -int64(a - b)
+}
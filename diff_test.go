@@ -0,0 +1,59 @@
+package semver
+
+import (
+	"math"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	tests := []struct {
+		a, b      string
+		change    Change
+		delta     int64
+		direction int
+	}{
+		{"1.2.3", "1.2.3", ChangeNone, 0, 0},
+		{"1.2.3+a", "1.2.3+b", ChangeNone, 0, 0},
+		{"1.2.3", "1.2.4", ChangePatch, 1, 1},
+		{"1.2.3", "1.4.0", ChangeMinor, 2, 1},
+		{"1.2.3", "3.0.0", ChangeMajor, 2, 1},
+		{"2.0.0", "1.9.9", ChangeMajor, -1, -1},
+		{"1.2.3-rc.1", "1.2.3", ChangePrerelease, 0, 1},
+		{"1.2.3", "1.2.3-rc.1", ChangePrerelease, 0, -1},
+		{"1.2.3-rc.1", "1.2.4-rc.1", ChangePatch, 1, 1},
+		{"0.0.0", "18446744073709551615.0.0", ChangeMajor, math.MaxInt64, 1},
+		{"18446744073709551615.0.0", "0.0.0", ChangeMajor, math.MinInt64, -1},
+	}
+
+	for _, tc := range tests {
+		d := Diff(MustParse(tc.a), MustParse(tc.b))
+		if d.Change != tc.change || d.Delta != tc.delta || d.Direction != tc.direction {
+			t.Errorf("Expected diff of %s and %s to be %s by %d in direction %d, got %s by %d in direction %d",
+				tc.a, tc.b, tc.change, tc.delta, tc.direction, d.Change, d.Delta, d.Direction)
+		}
+	}
+}
+
+func TestIsBreakingUpgrade(t *testing.T) {
+	tests := []struct {
+		from, to string
+		expected bool
+	}{
+		{"1.2.3", "1.2.4", false},
+		{"1.2.3", "1.9.0", false},
+		{"1.2.3", "2.0.0", true},
+		{"1.5.0", "2.0.0-rc.1", true},
+		{"1.2.3-rc.1", "1.2.3", false},
+		{"0.2.3", "0.2.4", false},
+		{"0.2.3", "0.3.0", true},
+		{"0.0.3", "0.0.4", true},
+		{"2.0.0", "1.0.0", false},
+		{"1.2.3", "1.2.3+build", false},
+	}
+
+	for _, tc := range tests {
+		if a := IsBreakingUpgrade(MustParse(tc.from), MustParse(tc.to)); a != tc.expected {
+			t.Errorf("Expected IsBreakingUpgrade(%s, %s) to be %t", tc.from, tc.to, tc.expected)
+		}
+	}
+}
@@ -0,0 +1,151 @@
+package semver
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"testing"
+)
+
+var updateDivergences = flag.Bool("update-divergences", false, "rewrite testdata/differential_divergences.txt")
+
+// differentialCase is a single entry in testdata/differential.json. The corpus
+// records the results reference implementations give for an operation so this
+// package can be checked against them. It is generated by the programs in
+// testdata/differential; run "make differential" to regenerate it.
+//
+// The op field is one of:
+//
+//   - "valid": version is parsed with StrictNewVersion and the result is
+//     whether it parsed
+//   - "compare": a is compared to b and the result is -1, 0, or 1
+//   - "satisfies": version is checked against the constraint in range and the
+//     result is whether it is admitted
+//
+// The node and masterminds fields hold the results from node-semver and
+// Masterminds/semver. A result is null when the implementation rejected one
+// of the inputs.
+type differentialCase struct {
+	Op          string          `json:"op"`
+	Version     string          `json:"version"`
+	A           string          `json:"a"`
+	B           string          `json:"b"`
+	Constraint  string          `json:"range"`
+	Node        json.RawMessage `json:"node"`
+	Masterminds json.RawMessage `json:"masterminds"`
+}
+
+// result returns the result of the case from this package, or nil when it
+// rejects one of the inputs.
+func (tc differentialCase) result() interface{} {
+	switch tc.Op {
+	case "valid":
+		_, err := StrictNewVersion(tc.Version)
+		return err == nil
+	case "compare":
+		a, err := NewVersion(tc.A)
+		if err != nil {
+			return nil
+		}
+		b, err := NewVersion(tc.B)
+		if err != nil {
+			return nil
+		}
+		return a.Compare(b)
+	case "satisfies":
+		v, err := NewVersion(tc.Version)
+		if err != nil {
+			return nil
+		}
+		c, err := NewConstraint(tc.Constraint)
+		if err != nil {
+			return nil
+		}
+		return c.Check(v)
+	}
+	return nil
+}
+
+func (tc differentialCase) inputs() string {
+	switch tc.Op {
+	case "compare":
+		return fmt.Sprintf("%q %q", tc.A, tc.B)
+	case "satisfies":
+		return fmt.Sprintf("%q %q", tc.Version, tc.Constraint)
+	}
+	return fmt.Sprintf("%q", tc.Version)
+}
+
+// TestDifferential checks this package against the results recorded in the
+// corpus. Every divergence from a reference implementation is listed in
+// testdata/differential_divergences.txt, and the test fails when the list
+// changes so that new divergences are reviewed before they reach users. Run
+// the test with -update-divergences to rewrite the list after reviewing them.
+func TestDifferential(t *testing.T) {
+	data, err := ioutil.ReadFile("testdata/differential.json")
+	if err != nil {
+		t.Fatalf("Error reading corpus: %s", err)
+	}
+
+	var cases []differentialCase
+	if err := json.Unmarshal(data, &cases); err != nil {
+		t.Fatalf("Error parsing corpus: %s", err)
+	}
+
+	var divergences []string
+	for _, tc := range cases {
+		switch tc.Op {
+		case "valid", "compare", "satisfies":
+		default:
+			t.Fatalf("Unknown corpus operation %q", tc.Op)
+		}
+
+		got, err := json.Marshal(tc.result())
+		if err != nil {
+			t.Fatalf("Error encoding result for %+v: %s", tc, err)
+		}
+		for _, ref := range []struct {
+			name   string
+			result json.RawMessage
+		}{
+			{"node-semver", tc.Node},
+			{"Masterminds", tc.Masterminds},
+		} {
+			if string(ref.result) != string(got) {
+				divergences = append(divergences, fmt.Sprintf("%s %s: %s gives %s, got %s", tc.Op, tc.inputs(), ref.name, ref.result, got))
+			}
+		}
+	}
+
+	out := strings.Join(divergences, "\n") + "\n"
+	if *updateDivergences {
+		if err := ioutil.WriteFile("testdata/differential_divergences.txt", []byte(out), 0644); err != nil {
+			t.Fatalf("Error writing divergences: %s", err)
+		}
+		return
+	}
+
+	data, err = ioutil.ReadFile("testdata/differential_divergences.txt")
+	if err != nil {
+		t.Fatalf("Error reading divergences: %s", err)
+	}
+	known := map[string]bool{}
+	for _, d := range strings.Split(strings.TrimSuffix(string(data), "\n"), "\n") {
+		known[d] = true
+	}
+
+	found := map[string]bool{}
+	for _, d := range divergences {
+		found[d] = true
+		if !known[d] {
+			t.Errorf("New divergence: %s", d)
+		}
+	}
+	for d := range known {
+		if d != "" && !found[d] {
+			t.Errorf("Known divergence no longer occurs: %s", d)
+		}
+	}
+}
@@ -0,0 +1,195 @@
+package semver
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidDistroVersion is returned by ParseDistroVersion when the epoch
+// or revision of a version is invalid.
+var ErrInvalidDistroVersion = errors.New("Invalid distribution package version")
+
+// DistroVersion is the version of a Debian or RPM style distribution
+// package, such as 2:1.4.0~rc.1-3. It is an upstream version with an
+// optional epoch before it, which overrides the ordering of the upstream
+// versions when they change scheme, and an optional packaging revision after
+// it, which counts the builds of the same upstream version.
+type DistroVersion struct {
+	// Epoch is 0 when the version has no epoch.
+	Epoch uint64
+
+	// Upstream is the version packaged. Constraints can be checked against
+	// it as usual.
+	Upstream *Version
+
+	// Revision is "" when the version has no revision.
+	Revision string
+}
+
+// ParseDistroVersion parses a distribution package version written as
+// [epoch:]upstream[-revision]. Unlike NewVersion the text after the last
+// hyphen is the revision rather than a prerelease, so 1.4.0-3 is the third
+// build of 1.4.0. A prerelease of the upstream version is written after a
+// tilde instead, as in 1.4.0~rc.1, and otherwise the upstream version is
+// parsed with NewVersion.
+func ParseDistroVersion(v string) (*DistroVersion, error) {
+	if len(v) == 0 {
+		return nil, ErrEmptyString
+	}
+	if MaxVersionLength > 0 && len(v) > MaxVersionLength {
+		return nil, ErrVersionTooLong
+	}
+
+	d := &DistroVersion{}
+	s := v
+
+	if i := strings.IndexByte(s, ':'); i != -1 {
+		if i == 0 || !containsOnly(s[:i], num) {
+			return nil, ErrInvalidDistroVersion
+		}
+		e, err := strconv.ParseUint(s[:i], 10, 64)
+		if err != nil {
+			return nil, ErrInvalidDistroVersion
+		}
+		d.Epoch, s = e, s[i+1:]
+	}
+
+	if i := strings.LastIndexByte(s, '-'); i != -1 {
+		d.Revision, s = s[i+1:], s[:i]
+		if d.Revision == "" || !containsOnly(d.Revision, distroRevisionChars) {
+			return nil, ErrInvalidDistroVersion
+		}
+	}
+
+	// The upstream version must start with a digit, so a v prefix is not
+	// allowed.
+	if s == "" || !strings.ContainsAny(s[:1], num) {
+		return nil, ErrInvalidSemVer
+	}
+	up, err := NewVersion(strings.Replace(s, "~", "-", 1))
+	if err != nil {
+		return nil, err
+	}
+	up.original = s
+	d.Upstream = up
+
+	return d, nil
+}
+
+// distroRevisionChars are the characters allowed in a revision by dpkg.
+const distroRevisionChars = allowed + ".+~"
+
+// String returns the version as written to ParseDistroVersion, without an
+// epoch when it is 0.
+func (d *DistroVersion) String() string {
+	var sb strings.Builder
+	if d.Epoch != 0 {
+		sb.WriteString(strconv.FormatUint(d.Epoch, 10))
+		sb.WriteByte(':')
+	}
+	sb.WriteString(d.Upstream.Original())
+	if d.Revision != "" {
+		sb.WriteByte('-')
+		sb.WriteString(d.Revision)
+	}
+	return sb.String()
+}
+
+// Compare compares this version to another one. It returns -1, 0, or 1 if
+// the version is lower than, equal to, or higher than the other. Epochs are
+// compared first, then the upstream versions with Version.Compare, so build
+// metadata is ignored, and then the revisions in the order dpkg uses.
+func (d *DistroVersion) Compare(o *DistroVersion) int {
+	if d.Epoch != o.Epoch {
+		if d.Epoch < o.Epoch {
+			return -1
+		}
+		return 1
+	}
+	if n := d.Upstream.Compare(o.Upstream); n != 0 {
+		return n
+	}
+	return compareDpkg(d.Revision, o.Revision)
+}
+
+// CompareDpkg compares this version to another one in the order dpkg uses,
+// which compares the upstream versions as written rather than by semantic
+// version precedence. They differ for versions such as 1.4.0+dfsg, which is
+// higher than 1.4.0 to dpkg but has build metadata that Compare ignores.
+func (d *DistroVersion) CompareDpkg(o *DistroVersion) int {
+	if d.Epoch != o.Epoch {
+		if d.Epoch < o.Epoch {
+			return -1
+		}
+		return 1
+	}
+	if n := compareDpkg(d.Upstream.Original(), o.Upstream.Original()); n != 0 {
+		return n
+	}
+	return compareDpkg(d.Revision, o.Revision)
+}
+
+// compareDpkg compares two upstream versions or revisions the way dpkg
+// does. The strings are split into alternating runs of non-digits and
+// digits. Non-digit runs are compared byte by byte with letters sorting
+// before other bytes and ~ before anything, even the end of the string, and
+// digit runs are compared as numbers.
+func compareDpkg(a, b string) int {
+	for a != "" || b != "" {
+		for a != "" && !isDigit(a[0]) || b != "" && !isDigit(b[0]) {
+			ac, bc := dpkgOrder(a), dpkgOrder(b)
+			if ac != bc {
+				if ac < bc {
+					return -1
+				}
+				return 1
+			}
+			a, b = a[1:], b[1:]
+		}
+
+		a, b = strings.TrimLeft(a, "0"), strings.TrimLeft(b, "0")
+		diff := 0
+		for a != "" && isDigit(a[0]) && b != "" && isDigit(b[0]) {
+			if diff == 0 {
+				diff = int(a[0]) - int(b[0])
+			}
+			a, b = a[1:], b[1:]
+		}
+		if a != "" && isDigit(a[0]) {
+			return 1
+		}
+		if b != "" && isDigit(b[0]) {
+			return -1
+		}
+		if diff != 0 {
+			if diff < 0 {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+// dpkgOrder returns the weight of the first byte of s when comparing
+// non-digit runs. The end of the string and digits weigh 0.
+func dpkgOrder(s string) int {
+	if s == "" {
+		return 0
+	}
+	c := s[0]
+	switch {
+	case isDigit(c):
+		return 0
+	case c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z':
+		return int(c)
+	case c == '~':
+		return -1
+	}
+	return int(c) + 256
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
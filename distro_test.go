@@ -0,0 +1,100 @@
+package semver
+
+import (
+	"testing"
+)
+
+func TestParseDistroVersion(t *testing.T) {
+	tests := []struct {
+		version  string
+		epoch    uint64
+		upstream string
+		revision string
+		err      bool
+	}{
+		{"1.4.0", 0, "1.4.0", "", false},
+		{"2:1.4.0-3", 2, "1.4.0", "3", false},
+		{"1.4.0-3ubuntu0.1", 0, "1.4.0", "3ubuntu0.1", false},
+		{"1.4.0~rc.1-1", 0, "1.4.0-rc.1", "1", false},
+		{"1:1.4-1+deb12u1", 1, "1.4.0", "1+deb12u1", false},
+		{"1.4.0+dfsg-2", 0, "1.4.0+dfsg", "2", false},
+		{"1.4.0-rc.1-2", 0, "1.4.0-rc.1", "2", false},
+		{"", 0, "", "", true},
+		{":1.4.0", 0, "", "", true},
+		{"a:1.4.0", 0, "", "", true},
+		{"1.4.0-", 0, "", "", true},
+		{"1.4.0-3_1", 0, "", "", true},
+		{"v1.4.0", 0, "", "", true},
+		{"2:-1", 0, "", "", true},
+		{"1.4.0.1-1", 0, "", "", true},
+	}
+
+	for _, tc := range tests {
+		d, err := ParseDistroVersion(tc.version)
+		if tc.err {
+			if err == nil {
+				t.Errorf("Expected error parsing %q", tc.version)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Error parsing %q: %s", tc.version, err)
+			continue
+		}
+
+		if d.Epoch != tc.epoch || d.Upstream.String() != tc.upstream || d.Revision != tc.revision {
+			t.Errorf("Expected %q to be epoch %d, upstream %q and revision %q, got %d, %q and %q",
+				tc.version, tc.epoch, tc.upstream, tc.revision, d.Epoch, d.Upstream, d.Revision)
+		}
+		if d.String() != tc.version {
+			t.Errorf("Expected %q to round trip, got %q", tc.version, d)
+		}
+	}
+}
+
+func TestDistroVersionCompare(t *testing.T) {
+	tests := []struct {
+		v1, v2      string
+		compare     int
+		compareDpkg int
+	}{
+		{"1.4.0", "1.4.0", 0, 0},
+		{"1.4.0", "1.4.0-0", 0, 0},
+		{"1:0.1.0", "2.0.0", 1, 1},
+		{"1.4.0-1", "1.4.0-2", -1, -1},
+		{"1.4.0-9", "1.4.0-10", -1, -1},
+		{"1.4.0-1", "1.4.0-1.1", -1, -1},
+		{"1.4.0-1", "1.4.0-1ubuntu1", -1, -1},
+		{"1.4.0-1~bpo1", "1.4.0-1", -1, -1},
+		{"1.4.0~rc.1-5", "1.4.0-1", -1, -1},
+		{"1.4.0~rc.2", "1.4.0~rc.10", -1, -1},
+		{"1.4.0+dfsg", "1.4.0", 0, 1},
+		{"1.10.0", "1.9.0", 1, 1},
+		{"1.4.0~beta.11", "1.4.0~beta.2", 1, 1},
+		{"1.4.0~rc.1.x", "1.4.0~rc.1.5", 1, 1},
+	}
+
+	for _, tc := range tests {
+		a, err := ParseDistroVersion(tc.v1)
+		if err != nil {
+			t.Fatalf("Error parsing %q: %s", tc.v1, err)
+		}
+		b, err := ParseDistroVersion(tc.v2)
+		if err != nil {
+			t.Fatalf("Error parsing %q: %s", tc.v2, err)
+		}
+
+		if n := a.Compare(b); n != tc.compare {
+			t.Errorf("Expected Compare of %q and %q to be %d, got %d", tc.v1, tc.v2, tc.compare, n)
+		}
+		if n := b.Compare(a); n != -tc.compare {
+			t.Errorf("Expected Compare of %q and %q to be %d, got %d", tc.v2, tc.v1, -tc.compare, n)
+		}
+		if n := a.CompareDpkg(b); n != tc.compareDpkg {
+			t.Errorf("Expected CompareDpkg of %q and %q to be %d, got %d", tc.v1, tc.v2, tc.compareDpkg, n)
+		}
+		if n := b.CompareDpkg(a); n != -tc.compareDpkg {
+			t.Errorf("Expected CompareDpkg of %q and %q to be %d, got %d", tc.v2, tc.v1, -tc.compareDpkg, n)
+		}
+	}
+}
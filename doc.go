@@ -97,6 +97,10 @@ comparison that's greater than or equal to 1.2 and less than 3.0.0 or is
 greater than or equal to 4.2.3. This can also be written as
 `">= 1.2, < 3.0.0 || >= 4.2.3"`
 
+Space and comma separated AND comparisons have the same precedence and bind
+tighter than ||. Hyphen ranges, described below, bind tighter than both. The
+full grammar is documented in the source of the constraint parser.
+
 The basic comparisons are:
 
     * `=`: equal (aliased to no operator)
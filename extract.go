@@ -0,0 +1,64 @@
+package semver
+
+import (
+	"regexp"
+)
+
+// extractRegex matches the shape of a strict semantic version, with an
+// optional leading v, anywhere in a string.
+var extractRegex = regexp.MustCompile(`v?(0|[1-9][0-9]*)\.(0|[1-9][0-9]*)\.(0|[1-9][0-9]*)` +
+	`(-[0-9A-Za-z-]+(\.[0-9A-Za-z-]+)*)?` +
+	`(\+[0-9A-Za-z-]+(\.[0-9A-Za-z-]+)*)?`)
+
+// FoundVersion is a version found in text by ExtractAll.
+type FoundVersion struct {
+	Version *Version
+
+	// Start and End are the byte offsets of the version in the text, such
+	// that text[Start:End] is the version as written.
+	Start, End int
+}
+
+// ExtractAll scans free text, such as build logs or the output of strings on
+// a binary, for semantic versions and returns them in the order they appear.
+// Only complete versions with a major, minor, and patch number are found so
+// that other numbers in the text are not mistaken for versions. A version
+// must not be part of a longer word or dotted number, so neither 1.2.3.4 nor
+// abc1.2.3 contain a version. A leading v is included in the version. Text
+// after a hyphen that is valid as a prerelease is taken to be part of the
+// version, so foo-1.2.3-linux.tar.gz contains 1.2.3-linux.tar.gz.
+func ExtractAll(s string) []FoundVersion {
+	var found []FoundVersion
+
+	for _, m := range extractRegex.FindAllStringIndex(s, -1) {
+		start, end := m[0], m[1]
+
+		if start > 0 && isExtractWordChar(s[start-1]) {
+			continue
+		}
+		// A trailing dot is allowed, such as at the end of a sentence, but
+		// not when it continues a dotted name or number.
+		if end < len(s) && isExtractWordChar(s[end]) &&
+			(s[end] != '.' || (end+1 < len(s) && isExtractWordChar(s[end+1]))) {
+			continue
+		}
+
+		v, err := NewVersion(s[start:end])
+		if err != nil {
+			continue
+		}
+		if _, err := StrictNewVersion(v.String()); err != nil {
+			continue
+		}
+
+		found = append(found, FoundVersion{Version: v, Start: start, End: end})
+	}
+
+	return found
+}
+
+// isExtractWordChar reports whether b joins a version to the text around it.
+func isExtractWordChar(b byte) bool {
+	return b == '.' || b == '_' ||
+		(b >= '0' && b <= '9') || (b >= 'a' && b <= 'z') || (b >= 'A' && b <= 'Z')
+}
@@ -0,0 +1,47 @@
+package semver
+
+import (
+	"testing"
+)
+
+func TestExtractAll(t *testing.T) {
+	tests := []struct {
+		text     string
+		expected []string
+	}{
+		{"Installing foo 1.2.3 and bar v2.0.0-rc.1+build.5.", []string{"1.2.3", "v2.0.0-rc.1+build.5"}},
+		{`<span class="version">10.20.30</span>`, []string{"10.20.30"}},
+		{"libfoo.so.1.2.3", nil},
+		{"host 10.0.0.1 is up", nil},
+		{"abc1.2.3 1.2.3abc", nil},
+		{"1.2 and 1 are not versions", nil},
+		{"01.2.3 1.02.3", nil},
+		{"1.2.3-01 is invalid but 1.2.3-1 is fine", []string{"1.2.3-1"}},
+		{"(1.2.3),[4.5.6]", []string{"1.2.3", "4.5.6"}},
+		{"1.2.3-", []string{"1.2.3"}},
+		{"go1.2.3", nil},
+		{"foo-1.2.3.tar.gz", nil},
+		{"foo-1.2.3-linux.tar.gz", []string{"1.2.3-linux.tar.gz"}},
+		{"Released 1.2.3.", []string{"1.2.3"}},
+		{"foo-1.2.3_linux", nil},
+		{"foo/v1.2.3/bar", []string{"v1.2.3"}},
+		{"", nil},
+	}
+
+	for _, tc := range tests {
+		found := ExtractAll(tc.text)
+		if len(found) != len(tc.expected) {
+			t.Errorf("Expected %v in %q, got %d versions", tc.expected, tc.text, len(found))
+			continue
+		}
+
+		for i, f := range found {
+			if s := tc.text[f.Start:f.End]; s != tc.expected[i] {
+				t.Errorf("Expected %q at %d:%d in %q, got %q", tc.expected[i], f.Start, f.End, tc.text, s)
+			}
+			if f.Version.Original() != tc.expected[i] {
+				t.Errorf("Expected version %q in %q, got %q", tc.expected[i], tc.text, f.Version.Original())
+			}
+		}
+	}
+}
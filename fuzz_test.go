@@ -0,0 +1,203 @@
+//go:build go1.18
+// +build go1.18
+
+package semver
+
+import (
+	"strings"
+	"testing"
+)
+
+// The fuzz targets check that parsing untrusted input never panics and that
+// whatever is accepted round trips through its string form.
+
+func FuzzNewVersion(f *testing.F) {
+	for _, s := range []string{"1.2.3", "v1.2", "1.2.3-beta.1+build.5", "1", "01.2.3", "1.2.3-", ""} {
+		f.Add(s)
+	}
+
+	f.Fuzz(func(t *testing.T, s string) {
+		v, err := NewVersion(s)
+		if err != nil {
+			return
+		}
+
+		p, err := NewVersion(v.String())
+		if err != nil {
+			t.Fatalf("Version %q parsed from %q does not parse: %s", v, s, err)
+		}
+		if p.CompareTotal(v) != 0 {
+			t.Fatalf("Version %q parsed from %q does not round trip, got %q", v, s, p)
+		}
+	})
+}
+
+func FuzzStrictNewVersion(f *testing.F) {
+	for _, s := range []string{"1.2.3", "1.2.3-beta.1+build.5", "1.2.3-0", "1.2.3+01", "v1.2.3", ""} {
+		f.Add(s)
+	}
+
+	f.Fuzz(func(t *testing.T, s string) {
+		v, err := StrictNewVersion(s)
+		if err != nil {
+			return
+		}
+
+		if v.String() != s {
+			t.Fatalf("Strict version %q does not round trip, got %q", s, v)
+		}
+		if _, err := NewVersion(s); err != nil {
+			t.Fatalf("Strict version %q is rejected by NewVersion: %s", s, err)
+		}
+	})
+}
+
+func FuzzNewConstraint(f *testing.F) {
+	for _, s := range []string{">=1.2.3, <2", "^1.2 || ~3.4.x", "1.2.3 - 2.3.4", "!=1.2.3+build", "*", "", "=>1.x-beta"} {
+		f.Add(s, "1.2.3")
+	}
+
+	f.Fuzz(func(t *testing.T, s, vs string) {
+		c, err := NewConstraint(s)
+		if err != nil {
+			return
+		}
+
+		p, err := NewConstraint(c.String())
+		if err != nil {
+			t.Fatalf("Constraint %q parsed from %q does not parse: %s", c, s, err)
+		}
+
+		v, err := NewVersion(vs)
+		if err != nil {
+			return
+		}
+
+		ok, errs := c.Validate(v)
+		if ok != c.Check(v) {
+			t.Fatalf("Check and Validate of %q against %q disagree", v, c)
+		}
+		if !ok && len(errs) == 0 {
+			t.Fatalf("Validate of %q against %q failed without a reason", v, c)
+		}
+		if p.Check(v) != ok {
+			t.Fatalf("Constraint %q and its string form %q disagree on %q", s, c, v)
+		}
+		_ = c.Describe()
+	})
+}
+
+func FuzzNewPattern(f *testing.F) {
+	for _, s := range []string{"1.2.x", "1.*.*-rc.*", "*", "v1", "1.2.3-"} {
+		f.Add(s, "1.2.3")
+	}
+
+	f.Fuzz(func(t *testing.T, s, vs string) {
+		p, err := NewPattern(s)
+		if err != nil {
+			return
+		}
+
+		v, err := NewVersion(vs)
+		if err != nil {
+			return
+		}
+
+		// A pattern converted to a constraint must admit the same versions.
+		m := p.Match(v)
+		if c, err := p.Constraint(); err == nil && c.Check(v) != m {
+			t.Fatalf("Pattern %q and constraint %q disagree on %q", p, c, v)
+		}
+	})
+}
+
+func FuzzFromSortableString(f *testing.F) {
+	for _, s := range []string{"1.2.3", "1.2.3-beta.1", "0.0.0-0.a-b", "1.0.0-99999999999999999999"} {
+		e, _ := ToSortableString(MustParse(s))
+		f.Add(e)
+	}
+
+	f.Fuzz(func(t *testing.T, s string) {
+		v, err := FromSortableString(s)
+		if err != nil {
+			return
+		}
+
+		e, err := ToSortableString(v)
+		if err != nil {
+			t.Fatalf("Version %q decoded from %q does not encode: %s", v, s, err)
+		}
+		if e != s {
+			t.Fatalf("Sortable string %q does not round trip, got %q", s, e)
+		}
+	})
+}
+
+func FuzzExtractAll(f *testing.F) {
+	for _, s := range []string{"foo 1.2.3 bar", "v1.2.3-rc.1.", "1.2.3.4", "a1.2.3"} {
+		f.Add(s)
+	}
+
+	f.Fuzz(func(t *testing.T, s string) {
+		for _, fv := range ExtractAll(s) {
+			if fv.Start < 0 || fv.End > len(s) || fv.Start >= fv.End {
+				t.Fatalf("Invalid offsets %d:%d in %q", fv.Start, fv.End, s)
+			}
+			if fv.Version.Original() != s[fv.Start:fv.End] {
+				t.Fatalf("Expected %q at %d:%d, got %q", s[fv.Start:fv.End], fv.Start, fv.End, fv.Version.Original())
+			}
+		}
+	})
+}
+
+func FuzzConstraintsUnmarshalBinary(f *testing.F) {
+	for _, s := range []string{">=1.2.3, !=1.4.x || ^3", "1.2.3 - 2.3.4", "~1.2.x-beta", "*"} {
+		c, _ := NewConstraint(s)
+		b, _ := c.MarshalBinary()
+		f.Add(b)
+	}
+
+	f.Fuzz(func(t *testing.T, b []byte) {
+		var c Constraints
+		if err := c.UnmarshalBinary(b); err != nil {
+			return
+		}
+		c.Check(MustParse("1.2.3-beta"))
+
+		e, err := c.MarshalBinary()
+		if err != nil {
+			t.Fatalf("Constraints %q decoded from %x do not encode: %s", c.String(), b, err)
+		}
+		var u Constraints
+		if err := u.UnmarshalBinary(e); err != nil {
+			t.Fatalf("Constraints %q encoded as %x do not decode: %s", c.String(), e, err)
+		}
+		if u.String() != c.String() {
+			t.Fatalf("Constraints %q do not round trip, got %q", c.String(), u.String())
+		}
+	})
+}
+
+func FuzzValidate(f *testing.F) {
+	for _, s := range []string{"1.2.3", "v01.2", "1.2.3-beta..01+", "1.2.3.4", ""} {
+		f.Add(s)
+	}
+
+	f.Fuzz(func(t *testing.T, s string) {
+		vs := Validate(s)
+		for _, v := range vs {
+			if v.Start < 0 || v.End > len(s) || v.Start > v.End {
+				t.Fatalf("Violation %q of %q has invalid offsets %d:%d", v, s, v.Start, v.End)
+			}
+		}
+
+		// The limits are not part of the spec.
+		if MaxVersionLength > 0 && len(s) > MaxVersionLength ||
+			MaxPrereleaseIdentifiers > 0 && strings.Count(s, ".") >= MaxPrereleaseIdentifiers {
+			return
+		}
+		if _, err := StrictNewVersion(s); (err == nil) != (len(vs) == 0) {
+			t.Fatalf("Expected Validate and StrictNewVersion to agree on %q, got %v and %v", s, vs, err)
+		}
+	})
+}
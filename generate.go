@@ -0,0 +1,391 @@
+package semver
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// GenerateOptions controls the versions and constraints produced by
+// GenerateVersion and GenerateConstraint. The zero value generates releases
+// with small numbers.
+type GenerateOptions struct {
+	// MaxSegment is the largest major, minor, or patch number generated. It
+	// defaults to 10 when it is 0.
+	MaxSegment uint64
+
+	// Prerelease and Metadata are the probabilities, between 0 and 1, that a
+	// generated version has a prerelease or metadata.
+	Prerelease, Metadata float64
+
+	// MaxIdentifiers is the largest number of identifiers generated in a
+	// prerelease or metadata. It defaults to 3 when it is 0, and is lowered
+	// to MaxPrereleaseIdentifiers when that is set.
+	MaxIdentifiers int
+}
+
+// generateWords are used as alphanumeric identifiers so generated versions
+// look like the ones found in practice.
+var generateWords = []string{"alpha", "beta", "rc", "pre", "dev", "build", "x-y", "0a"}
+
+// generateOps are the operators used by GenerateConstraint.
+var generateOps = []string{"", "=", "!=", ">", "<", ">=", "<=", "~", "^"}
+
+// GenerateVersion returns a random version produced using the source of
+// randomness r. If opts is nil the zero value options are used. The version
+// is shortened to fit MaxVersionLength when that is set.
+func GenerateVersion(r *rand.Rand, opts *GenerateOptions) *Version {
+	o := opts.withDefaults()
+
+	v := &Version{
+		major: generateSegment(r, o.MaxSegment),
+		minor: generateSegment(r, o.MaxSegment),
+		patch: generateSegment(r, o.MaxSegment),
+	}
+
+	if r.Float64() < o.Prerelease {
+		v.pre = generateIdentifiers(r, o, false)
+	}
+	if r.Float64() < o.Metadata {
+		v.metadata = generateIdentifiers(r, o, true)
+	}
+	for MaxVersionLength > 0 && len(v.String()) > MaxVersionLength {
+		if !generateShorten(v) {
+			break
+		}
+	}
+	v.original = v.String()
+
+	return v
+}
+
+// generateShorten removes the last metadata or prerelease identifier of v, or when
+// it has neither shortens its largest number, so a generated version fits in
+// MaxVersionLength. It returns false when v cannot be shortened.
+func generateShorten(v *Version) bool {
+	trim := func(ids string) string {
+		if i := strings.LastIndexByte(ids, '.'); i != -1 {
+			return ids[:i]
+		}
+		return ""
+	}
+
+	switch {
+	case v.metadata != "":
+		v.metadata = trim(v.metadata)
+	case v.pre != "":
+		v.pre = trim(v.pre)
+	case v.major >= v.minor && v.major >= v.patch && v.major > 0:
+		v.major /= 10
+	case v.minor >= v.patch && v.minor > 0:
+		v.minor /= 10
+	case v.patch > 0:
+		v.patch /= 10
+	default:
+		return false
+	}
+	return true
+}
+
+// GenerateConstraint returns a random constraint produced using the source
+// of randomness r. The constraint is made of comparisons, ranges, and
+// wildcards combined with , and ||. If opts is nil the zero value options
+// are used. Metadata is never generated in constraints. Terms and groups are
+// left out as needed to stay within MaxConstraintLength and
+// MaxConstraintGroups when they are set, and * is returned when no term
+// fits.
+func GenerateConstraint(r *rand.Rand, opts *GenerateOptions) *Constraints {
+	o := opts.withDefaults()
+	o.Metadata = 0
+
+	fits := func(s string) bool {
+		return MaxConstraintLength <= 0 || len(s) <= MaxConstraintLength
+	}
+
+	n := r.Intn(3) + 1
+	if MaxConstraintGroups > 0 && n > MaxConstraintGroups {
+		n = MaxConstraintGroups
+	}
+
+	var str string
+	for i := 0; i < n; i++ {
+		sep := ""
+		if str != "" {
+			sep = " || "
+		}
+
+		var group string
+		for j := r.Intn(3) + 1; j > 0; j-- {
+			term := generateTerm(r, o)
+			if group != "" {
+				term = ", " + term
+			}
+			if fits(str + sep + group + term) {
+				group += term
+			}
+		}
+		if group != "" {
+			str += sep + group
+		}
+	}
+	if str == "" {
+		str = "*"
+	}
+
+	c, err := NewConstraint(str)
+	if err != nil {
+		// GenerateConstraint only produces valid constraints.
+		panic(err)
+	}
+
+	return c
+}
+
+// generateTerm returns a single comparison, range, or wildcard for
+// GenerateConstraint.
+func generateTerm(r *rand.Rand, o GenerateOptions) string {
+	switch r.Intn(6) {
+	case 0:
+		lo := GenerateVersion(r, &o)
+		hi := GenerateVersion(r, &o)
+		return lo.String() + " - " + hi.String()
+	case 1:
+		return generateOps[r.Intn(len(generateOps))] + generateWildcard(r, o)
+	default:
+		return generateOps[r.Intn(len(generateOps))] + GenerateVersion(r, &o).String()
+	}
+}
+
+// ErrNoViolatingVersion is returned by GenerateViolating when every version
+// satisfies the constraints.
+var ErrNoViolatingVersion = errors.New("No version violates the constraints")
+
+// GenerateSatisfying returns a random version that satisfies cs, produced
+// using the source of randomness r, for sampling the versions a constraint
+// describes in property tests. Versions spread over the space described by
+// opts are tried first, and then versions at and around the ends of the
+// ranges of cs, so open bounds, exclusions and the prerelease rules are
+// exercised. opts also sets how often prereleases and metadata are preferred.
+// If opts is nil the zero value options are used. ErrNoSatisfyingVersion is
+// returned when no version satisfies cs.
+func GenerateSatisfying(r *rand.Rand, cs *Constraints, opts *GenerateOptions) (*Version, error) {
+	v := generateChecked(r, cs, opts, true)
+	if v == nil {
+		return nil, ErrNoSatisfyingVersion
+	}
+	return v, nil
+}
+
+// GenerateViolating returns a random version that does not satisfy cs in the
+// same way as GenerateSatisfying. ErrNoViolatingVersion is returned when
+// every version satisfies cs.
+func GenerateViolating(r *rand.Rand, cs *Constraints, opts *GenerateOptions) (*Version, error) {
+	v := generateChecked(r, cs, opts, false)
+	if v == nil {
+		return nil, ErrNoViolatingVersion
+	}
+	return v, nil
+}
+
+// generateChecked returns a random version for which cs.Check returns want,
+// or nil if there is none. Whether a version satisfies cs is always decided
+// by Check, so the result follows every option on cs.
+func generateChecked(r *rand.Rand, cs *Constraints, opts *GenerateOptions, want bool) *Version {
+	o := opts.withDefaults()
+
+	found := func(v *Version) *Version {
+		if r.Float64() < o.Metadata {
+			m := *v
+			m.metadata = generateIdentifiers(r, o, true)
+			m.original = m.String()
+			if cs.Check(&m) == want {
+				return &m
+			}
+		}
+		return v
+	}
+
+	for i := 0; i < 32; i++ {
+		if v := GenerateVersion(r, &o); cs.Check(v) == want {
+			return found(v)
+		}
+	}
+
+	// Every range of versions cs admits starts and stops at one of the
+	// candidates, so when none of them has the wanted result no version has.
+	releases, pres := cs.generateCandidates()
+	if r.Float64() < o.Prerelease {
+		releases, pres = pres, releases
+	}
+	for _, cands := range [][]*Version{releases, pres} {
+		for _, i := range r.Perm(len(cands)) {
+			if v := generateJitter(r, cands[i], o.MaxSegment); cs.Check(v) == want {
+				return found(v)
+			}
+			if cs.Check(cands[i]) == want {
+				return found(cands[i])
+			}
+		}
+	}
+
+	return nil
+}
+
+// generateCandidates returns the releases and prereleases at which cs can
+// start or stop admitting versions. Each release is paired with its lowest
+// prerelease, and each prerelease in a constraint with the next one after
+// it, so ranges that only admit prereleases are found too.
+func (cs Constraints) generateCandidates() (releases, pres []*Version) {
+	releases = cs.adjacentCandidates(newRelease([3]uint64{}))
+
+	for _, v := range releases {
+		p := *v
+		p.pre = "0"
+		p.original = p.String()
+		pres = append(pres, &p)
+	}
+
+	for _, o := range cs.constraints {
+		for _, c := range o {
+			if c.con.pre == "" {
+				continue
+			}
+			p := *c.con
+			p.metadata = ""
+			p.original = p.String()
+			next := p
+			next.pre += ".0"
+			next.original = next.String()
+			pres = append(pres, &p, &next)
+		}
+	}
+
+	return releases, pres
+}
+
+// generateJitter returns a copy of v with its patch, or sometimes its minor,
+// number moved up or down by a random amount up to max so versions near a
+// boundary are generated rather than only the boundary itself.
+func generateJitter(r *rand.Rand, v *Version, max uint64) *Version {
+	j := *v
+	seg := &j.patch
+	if r.Intn(4) == 0 {
+		seg = &j.minor
+	}
+
+	d := generateSegment(r, max)
+	if r.Intn(2) == 0 {
+		if *seg > math.MaxUint64-d {
+			*seg = math.MaxUint64
+		} else {
+			*seg += d
+		}
+	} else if *seg < d {
+		*seg = 0
+	} else {
+		*seg -= d
+	}
+
+	j.original = j.String()
+	return &j
+}
+
+// Generate implements the testing/quick Generator interface so versions can
+// be generated in property tests. The size bounds the numbers generated.
+func (Version) Generate(r *rand.Rand, size int) reflect.Value {
+	o := &GenerateOptions{
+		MaxSegment: uint64(size),
+		Prerelease: 0.3,
+		Metadata:   0.1,
+	}
+
+	return reflect.ValueOf(*GenerateVersion(r, o))
+}
+
+// Generate implements the testing/quick Generator interface so constraints
+// can be generated in property tests. The size bounds the numbers generated.
+func (Constraints) Generate(r *rand.Rand, size int) reflect.Value {
+	o := &GenerateOptions{
+		MaxSegment: uint64(size),
+		Prerelease: 0.2,
+	}
+
+	return reflect.ValueOf(*GenerateConstraint(r, o))
+}
+
+// withDefaults returns a copy of the options with the defaults filled in.
+func (o *GenerateOptions) withDefaults() GenerateOptions {
+	var d GenerateOptions
+	if o != nil {
+		d = *o
+	}
+	if d.MaxSegment == 0 {
+		d.MaxSegment = 10
+	}
+	if d.MaxIdentifiers <= 0 {
+		d.MaxIdentifiers = 3
+	}
+	if MaxPrereleaseIdentifiers > 0 && d.MaxIdentifiers > MaxPrereleaseIdentifiers {
+		d.MaxIdentifiers = MaxPrereleaseIdentifiers
+	}
+	return d
+}
+
+// generateSegment returns a number up to max, favouring small numbers as
+// those are the most common.
+func generateSegment(r *rand.Rand, max uint64) uint64 {
+	if r.Intn(2) == 0 {
+		max = max/10 + 1
+	}
+	if max >= 1<<63-1 {
+		return uint64(r.Int63())
+	}
+	return uint64(r.Int63n(int64(max) + 1))
+}
+
+// generateIdentifiers returns dot separated prerelease or metadata
+// identifiers. Metadata allows numeric identifiers with leading zeros.
+func generateIdentifiers(r *rand.Rand, o GenerateOptions, metadata bool) string {
+	ids := make([]string, r.Intn(o.MaxIdentifiers)+1)
+	for i := range ids {
+		switch r.Intn(3) {
+		case 0:
+			ids[i] = strconv.Itoa(r.Intn(20))
+			if metadata && r.Intn(4) == 0 {
+				ids[i] = "0" + ids[i]
+			}
+		default:
+			ids[i] = generateWords[r.Intn(len(generateWords))]
+		}
+	}
+	return strings.Join(ids, ".")
+}
+
+// generateWildcard returns a version with wildcard or missing parts, such as
+// 1.2.x, 1.*, or 2.
+func generateWildcard(r *rand.Rand, o GenerateOptions) string {
+	wildcards := []string{"x", "X", "*"}
+
+	parts := []string{strconv.FormatUint(generateSegment(r, o.MaxSegment), 10)}
+	if r.Intn(4) == 0 {
+		parts[0] = wildcards[r.Intn(len(wildcards))]
+	}
+
+	n := r.Intn(3)
+	for i := 0; i < n; i++ {
+		if r.Intn(3) == 0 {
+			parts = append(parts, strconv.FormatUint(generateSegment(r, o.MaxSegment), 10))
+		} else {
+			parts = append(parts, wildcards[r.Intn(len(wildcards))])
+		}
+	}
+
+	w := strings.Join(parts, ".")
+	if MaxVersionLength > 0 && len(w) > MaxVersionLength {
+		return "*"
+	}
+	return w
+}
@@ -0,0 +1,177 @@
+package semver
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"testing"
+	"testing/quick"
+)
+
+func TestGenerateVersion(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	o := &GenerateOptions{MaxSegment: 100, Prerelease: 0.5, Metadata: 0.5}
+
+	var pre, meta int
+	for i := 0; i < 1000; i++ {
+		v := GenerateVersion(r, o)
+
+		if v.Major() > 100 || v.Minor() > 100 || v.Patch() > 100 {
+			t.Errorf("Expected segments of %q to be at most 100", v)
+		}
+		if _, err := StrictNewVersion(v.String()); err != nil {
+			t.Errorf("Generated invalid version %q: %s", v, err)
+		}
+		if v.Prerelease() != "" {
+			pre++
+		}
+		if v.Metadata() != "" {
+			meta++
+		}
+	}
+
+	if pre == 0 || meta == 0 {
+		t.Errorf("Expected prereleases and metadata to be generated, got %d and %d", pre, meta)
+	}
+
+	for i := 0; i < 100; i++ {
+		v := GenerateVersion(r, nil)
+		if v.Prerelease() != "" || v.Metadata() != "" {
+			t.Errorf("Expected only releases with nil options, got %q", v)
+		}
+	}
+}
+
+func TestGenerateConstraint(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	o := &GenerateOptions{Prerelease: 0.3, Metadata: 1}
+
+	for i := 0; i < 1000; i++ {
+		c := GenerateConstraint(r, o)
+
+		// The string form must parse back to the same constraint.
+		if _, err := NewConstraint(c.String()); err != nil {
+			t.Errorf("Generated constraint %q does not round trip: %s", c, err)
+		}
+	}
+}
+
+func TestGenerateLimits(t *testing.T) {
+	defer func(vl, pi, cl, cg int) {
+		MaxVersionLength = vl
+		MaxPrereleaseIdentifiers = pi
+		MaxConstraintLength = cl
+		MaxConstraintGroups = cg
+	}(MaxVersionLength, MaxPrereleaseIdentifiers, MaxConstraintLength, MaxConstraintGroups)
+	MaxVersionLength = 12
+	MaxPrereleaseIdentifiers = 2
+	MaxConstraintLength = 20
+	MaxConstraintGroups = 1
+
+	r := rand.New(rand.NewSource(1))
+	o := &GenerateOptions{MaxSegment: math.MaxUint64, Prerelease: 0.5, Metadata: 0.5, MaxIdentifiers: 10}
+
+	for i := 0; i < 1000; i++ {
+		v := GenerateVersion(r, o)
+		if _, err := NewVersion(v.String()); err != nil {
+			t.Errorf("Generated version %q does not parse: %s", v, err)
+		}
+
+		// GenerateConstraint panics when it produces a constraint that does
+		// not parse.
+		c := GenerateConstraint(r, o)
+		if _, err := NewConstraint(c.String()); err != nil {
+			t.Errorf("Generated constraint %q does not round trip: %s", c, err)
+		}
+	}
+}
+
+func TestQuickGenerate(t *testing.T) {
+	// CompareTotal is a total order so it must be antisymmetric.
+	f := func(a, b Version) bool {
+		return a.CompareTotal(&b) == -b.CompareTotal(&a)
+	}
+	if err := quick.Check(f, nil); err != nil {
+		t.Error(err)
+	}
+
+	// A constraint is equivalent to the parse of its string form.
+	g := func(c Constraints, v Version) bool {
+		p, err := NewConstraint(c.String())
+		return err == nil && p.Check(&v) == c.Check(&v)
+	}
+	if err := quick.Check(g, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestGenerateSatisfying(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	o := &GenerateOptions{Prerelease: 0.3, Metadata: 0.3}
+
+	// Every version with small numbers, and their lowest prereleases, to
+	// check that a version is generated whenever one exists.
+	var universe Collection
+	for major := uint64(0); major <= 11; major++ {
+		for minor := uint64(0); minor <= 11; minor++ {
+			for patch := uint64(0); patch <= 11; patch++ {
+				universe = append(universe, newRelease([3]uint64{major, minor, patch}))
+				universe = append(universe, &Version{major: major, minor: minor, patch: patch, pre: "0"})
+			}
+		}
+	}
+	sort.Sort(universe)
+
+	for i := 0; i < 2000; i++ {
+		c := GenerateConstraint(r, o)
+		c.IncludePrerelease = r.Intn(4) == 0
+
+		v, err := GenerateSatisfying(r, c, o)
+		if err == nil && !c.Check(v) {
+			t.Errorf("Expected %q to satisfy %q", v, c)
+		}
+		if err != nil && err != ErrNoSatisfyingVersion {
+			t.Errorf("Unexpected error for %q: %s", c, err)
+		}
+		if err != nil && c.Count(universe) > 0 {
+			t.Errorf("Expected a version satisfying %q to be generated", c)
+		}
+
+		v, err = GenerateViolating(r, c, o)
+		if err == nil && c.Check(v) {
+			t.Errorf("Expected %q not to satisfy %q", v, c)
+		}
+		if err != nil && err != ErrNoViolatingVersion {
+			t.Errorf("Unexpected error for %q: %s", c, err)
+		}
+		if err != nil && c.Count(universe) != len(universe) {
+			t.Errorf("Expected a version violating %q to be generated", c)
+		}
+	}
+}
+
+func TestGenerateSatisfyingEdges(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+
+	c, _ := NewConstraint("=1.0.0-rc.1")
+	if v, err := GenerateSatisfying(r, c, nil); err != nil || v.String() != "1.0.0-rc.1" {
+		t.Errorf("Expected 1.0.0-rc.1, got %v (%v)", v, err)
+	}
+
+	c, _ = NewConstraint(">2, <1")
+	if _, err := GenerateSatisfying(r, c, nil); err != ErrNoSatisfyingVersion {
+		t.Errorf("Expected ErrNoSatisfyingVersion, got %v", err)
+	}
+
+	c, _ = NewConstraint("*")
+	if v, err := GenerateViolating(r, c, nil); err != nil || v.Prerelease() == "" {
+		t.Errorf("Expected a prerelease to violate *, got %v (%v)", v, err)
+	}
+
+	// 0.0.0-0 is the lowest version.
+	c, _ = NewConstraint(">=0.0.0-0")
+	c.IncludePrerelease = true
+	if _, err := GenerateViolating(r, c, nil); err != ErrNoViolatingVersion {
+		t.Errorf("Expected ErrNoViolatingVersion, got %v", err)
+	}
+}
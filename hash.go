@@ -0,0 +1,106 @@
+package semver
+
+// VersionKey is a comparable form of a version for use as a map key. Two
+// versions have the same key exactly when they are Equal, so build metadata
+// is ignored.
+type VersionKey struct {
+	major, minor, patch uint64
+	pre                 string
+}
+
+// Key returns the map key of the version. It is cheaper to compute and
+// compare than String.
+func (v *Version) Key() VersionKey {
+	return VersionKey{major: v.major, minor: v.minor, patch: v.patch, pre: v.pre}
+}
+
+// Hash returns a 64-bit hash of the version. Versions that are Equal have the
+// same hash, so build metadata is ignored. The hash is the same in every
+// process and can be stored.
+func (v *Version) Hash() uint64 {
+	h := newHash()
+	h = h.uint64(v.major).uint64(v.minor).uint64(v.patch).string(v.pre)
+	return uint64(h)
+}
+
+// Hash returns a 64-bit hash of the normalized form of the constraints,
+// including their options. Spellings of the same term hash the same, such as
+// =>v1.2 and >=1.2.x, and 1.2.3 and =1.2.3. Terms and groups written in a
+// different order hash differently. Like Version.Hash it is the same in every
+// process and can be stored.
+func (cs Constraints) Hash() uint64 {
+	h := newHash()
+
+	var opts uint64
+	if cs.ExclusionsMatchMetadata {
+		opts |= 1 << 0
+	}
+	if cs.EqualityMatchesMetadata {
+		opts |= 1 << 1
+	}
+	if cs.IncludePrerelease {
+		opts |= 1 << 2
+	}
+	if cs.SnapPartialBounds {
+		opts |= 1 << 3
+	}
+	h = h.uint64(opts).uint64(uint64(len(cs.constraints)))
+
+	for _, v := range cs.constraints {
+		h = h.uint64(uint64(len(v)))
+		for _, c := range v {
+			var dirty uint64
+			if c.dirty {
+				dirty |= 1 << 0
+			}
+			if c.minorDirty {
+				dirty |= 1 << 1
+			}
+			if c.patchDirty {
+				dirty |= 1 << 2
+			}
+
+			op := c.origfunc
+			if o, ok := canonicalOps[op]; ok {
+				op = o
+			}
+
+			h = h.string(op).uint64(dirty)
+			h = h.uint64(c.con.major).uint64(c.con.minor).uint64(c.con.patch)
+			h = h.string(c.con.pre).string(c.con.metadata)
+		}
+	}
+
+	return uint64(h)
+}
+
+// fnvHash is a 64-bit FNV-1a hash. It is written out rather than using
+// hash/fnv so hashing allocates nothing.
+type fnvHash uint64
+
+const fnvPrime = 1099511628211
+
+func newHash() fnvHash {
+	return 14695981039346656037
+}
+
+func (h fnvHash) byte(b byte) fnvHash {
+	return (h ^ fnvHash(b)) * fnvPrime
+}
+
+func (h fnvHash) uint64(x uint64) fnvHash {
+	for i := 0; i < 8; i++ {
+		h = h.byte(byte(x >> (8 * uint(i))))
+	}
+	return h
+}
+
+// string hashes the length of s before s so that adjacent strings cannot run
+// into each other.
+func (h fnvHash) string(s string) fnvHash {
+	h = h.uint64(uint64(len(s)))
+	for i := 0; i < len(s); i++ {
+		h = h.byte(s[i])
+	}
+	return h
+}
@@ -0,0 +1,97 @@
+package semver
+
+import (
+	"testing"
+)
+
+func TestVersionHash(t *testing.T) {
+	tests := []struct {
+		v1, v2 string
+		same   bool
+	}{
+		{"1.2.3", "1.2.3", true},
+		{"v1.2.3", "1.2.3", true},
+		{"1.2", "1.2.0", true},
+		{"1.2.3+build.1", "1.2.3+build.2", true},
+		{"1.2.3", "1.2.4", false},
+		{"1.2.3-beta", "1.2.3", false},
+		{"1.2.3-beta", "1.2.3-beta.1", false},
+		{"1.23.4", "12.3.4", false},
+	}
+
+	for _, tc := range tests {
+		v1, v2 := MustParse(tc.v1), MustParse(tc.v2)
+		if a := v1.Hash() == v2.Hash(); a != tc.same {
+			t.Errorf("Expected hashes of %s and %s to be the same: %t", tc.v1, tc.v2, tc.same)
+		}
+		if a := v1.Key() == v2.Key(); a != tc.same {
+			t.Errorf("Expected keys of %s and %s to be the same: %t", tc.v1, tc.v2, tc.same)
+		}
+		if a := v1.Equal(v2); a != tc.same {
+			t.Errorf("Expected %s and %s to be equal: %t", tc.v1, tc.v2, tc.same)
+		}
+	}
+
+	// The hash is stable, so it can be stored.
+	if h := MustParse("1.2.3").Hash(); h != 0x057a9a41125371a5 {
+		t.Errorf("Expected the hash of 1.2.3 to be stable, got %#x", h)
+	}
+}
+
+func TestConstraintsHash(t *testing.T) {
+	tests := []struct {
+		c1, c2 string
+		same   bool
+	}{
+		{">=1.2.3", ">=1.2.3", true},
+		{"=>v1.2", ">=1.2.x", true},
+		{"1.2.3", "=1.2.3", true},
+		{"~>1.2", "~1.2", true},
+		{">=1.2.3 <2", ">=1.2.3, <2", true},
+		{">=1.2.3", ">1.2.3", false},
+		{"1.2", "1.2.0", false},
+		{">=1.2.3 <2", "<2 >=1.2.3", false},
+		{"^1 || ^2", "^1 ^2", false},
+		{"!=1.2.3+a", "!=1.2.3+b", false},
+	}
+
+	for _, tc := range tests {
+		c1, err := NewConstraint(tc.c1)
+		if err != nil {
+			t.Fatalf("Error parsing constraint %q: %s", tc.c1, err)
+		}
+		c2, err := NewConstraint(tc.c2)
+		if err != nil {
+			t.Fatalf("Error parsing constraint %q: %s", tc.c2, err)
+		}
+
+		if a := c1.Hash() == c2.Hash(); a != tc.same {
+			t.Errorf("Expected hashes of %q and %q to be the same: %t", tc.c1, tc.c2, tc.same)
+		}
+	}
+
+	c, err := NewConstraint(">=1.2.3")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	h := c.Hash()
+	c.IncludePrerelease = true
+	if c.Hash() == h {
+		t.Error("Expected the options to change the hash")
+	}
+}
+
+func TestHashAllocs(t *testing.T) {
+	v := MustParse("1.2.3-beta.1")
+	c, err := NewConstraint(">=1.2.3, !=1.4.x || ^3")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	if n := testing.AllocsPerRun(100, func() { v.Hash() }); n != 0 {
+		t.Errorf("Expected Version.Hash to not allocate, got %v allocations", n)
+	}
+	if n := testing.AllocsPerRun(100, func() { c.Hash() }); n != 0 {
+		t.Errorf("Expected Constraints.Hash to not allocate, got %v allocations", n)
+	}
+}
@@ -0,0 +1,227 @@
+// Package httpsemver provides HTTP handlers exposing version validation,
+// comparison, constraint checking, and sorting as a JSON API, for teams
+// running an internal version policy service.
+//
+// Each endpoint accepts a POST with a JSON request body and responds with
+// JSON. Malformed requests, and versions or constraints that cannot be
+// parsed where one is required, are answered with 400 Bad Request and an
+// ErrorResponse.
+package httpsemver
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+
+	"github.com/Masterminds/semver/v3"
+)
+
+// maxBodySize bounds the request bodies read by the handlers.
+const maxBodySize = 1 << 20
+
+// ErrorResponse is the body of an unsuccessful response.
+type ErrorResponse struct {
+	Error string `json:"error"`
+}
+
+// ValidateRequest is the body of a request to the validate endpoint.
+type ValidateRequest struct {
+	Version string `json:"version"`
+
+	// Strict parses the version with StrictNewVersion instead of
+	// NewVersion.
+	Strict bool `json:"strict,omitempty"`
+}
+
+// ValidateResponse is the body of a response from the validate endpoint.
+// Version is the canonical form of a valid version and Error the reason an
+// invalid one was rejected.
+type ValidateResponse struct {
+	Valid   bool   `json:"valid"`
+	Version string `json:"version,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+// CompareRequest is the body of a request to the compare endpoint.
+type CompareRequest struct {
+	A string `json:"a"`
+	B string `json:"b"`
+}
+
+// CompareResponse is the body of a response from the compare endpoint.
+// Result is -1, 0, or 1 as A is lower than, equal to, or higher than B.
+type CompareResponse struct {
+	Result int `json:"result"`
+}
+
+// SatisfiesRequest is the body of a request to the satisfies endpoint.
+type SatisfiesRequest struct {
+	Constraint string `json:"constraint"`
+	Version    string `json:"version"`
+}
+
+// SatisfiesResponse is the body of a response from the satisfies endpoint.
+// Reasons explains why the version does not satisfy the constraint.
+type SatisfiesResponse struct {
+	Satisfies bool     `json:"satisfies"`
+	Reasons   []string `json:"reasons,omitempty"`
+}
+
+// SortRequest is the body of a request to the sort endpoint.
+type SortRequest struct {
+	Versions   []string `json:"versions"`
+	Descending bool     `json:"descending,omitempty"`
+}
+
+// SortResponse is the body of a response from the sort endpoint. The versions
+// are returned as they were given.
+type SortResponse struct {
+	Versions []string `json:"versions"`
+}
+
+// NewHandler returns a handler serving the validate, compare, satisfies, and
+// sort endpoints at /validate, /compare, /satisfies, and /sort.
+func NewHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle("/validate", ValidateHandler())
+	mux.Handle("/compare", CompareHandler())
+	mux.Handle("/satisfies", SatisfiesHandler())
+	mux.Handle("/sort", SortHandler())
+	return mux
+}
+
+// ValidateHandler returns a handler reporting whether a version is valid.
+func ValidateHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req ValidateRequest
+		if !decode(w, r, &req) {
+			return
+		}
+
+		parse := semver.NewVersion
+		if req.Strict {
+			parse = semver.StrictNewVersion
+		}
+
+		v, err := parse(req.Version)
+		if err != nil {
+			writeJSON(w, http.StatusOK, &ValidateResponse{Error: err.Error()})
+			return
+		}
+		writeJSON(w, http.StatusOK, &ValidateResponse{Valid: true, Version: v.String()})
+	})
+}
+
+// CompareHandler returns a handler comparing two versions.
+func CompareHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req CompareRequest
+		if !decode(w, r, &req) {
+			return
+		}
+
+		a, err := semver.NewVersion(req.A)
+		if err != nil {
+			badRequest(w, err)
+			return
+		}
+		b, err := semver.NewVersion(req.B)
+		if err != nil {
+			badRequest(w, err)
+			return
+		}
+
+		writeJSON(w, http.StatusOK, &CompareResponse{Result: a.Compare(b)})
+	})
+}
+
+// SatisfiesHandler returns a handler checking a version against a
+// constraint.
+func SatisfiesHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req SatisfiesRequest
+		if !decode(w, r, &req) {
+			return
+		}
+
+		c, err := semver.NewConstraint(req.Constraint)
+		if err != nil {
+			badRequest(w, err)
+			return
+		}
+		v, err := semver.NewVersion(req.Version)
+		if err != nil {
+			badRequest(w, err)
+			return
+		}
+
+		resp := &SatisfiesResponse{Satisfies: c.Check(v)}
+		if !resp.Satisfies {
+			_, errs := c.Validate(v)
+			for _, e := range errs {
+				resp.Reasons = append(resp.Reasons, e.Error())
+			}
+		}
+		writeJSON(w, http.StatusOK, resp)
+	})
+}
+
+// SortHandler returns a handler sorting versions by precedence. Versions with
+// equal precedence keep their order.
+func SortHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req SortRequest
+		if !decode(w, r, &req) {
+			return
+		}
+
+		vs := make(semver.Collection, len(req.Versions))
+		for i, s := range req.Versions {
+			v, err := semver.NewVersion(s)
+			if err != nil {
+				badRequest(w, err)
+				return
+			}
+			vs[i] = v
+		}
+
+		if req.Descending {
+			sort.Stable(sort.Reverse(vs))
+		} else {
+			sort.Stable(vs)
+		}
+
+		resp := &SortResponse{Versions: make([]string, len(vs))}
+		for i, v := range vs {
+			resp.Versions[i] = v.Original()
+		}
+		writeJSON(w, http.StatusOK, resp)
+	})
+}
+
+// decode reads the JSON request body into req. It writes an error response
+// and returns false when the request is not a POST or the body is invalid.
+func decode(w http.ResponseWriter, r *http.Request, req interface{}) bool {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		writeJSON(w, http.StatusMethodNotAllowed, &ErrorResponse{Error: "method not allowed"})
+		return false
+	}
+
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBodySize)).Decode(req); err != nil {
+		badRequest(w, err)
+		return false
+	}
+
+	return true
+}
+
+func badRequest(w http.ResponseWriter, err error) {
+	writeJSON(w, http.StatusBadRequest, &ErrorResponse{Error: err.Error()})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,150 @@
+package httpsemver
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func post(t *testing.T, h http.Handler, path, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	r := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	return w
+}
+
+func TestValidate(t *testing.T) {
+	h := NewHandler()
+
+	tests := []struct {
+		body     string
+		expected ValidateResponse
+	}{
+		{`{"version": "v1.2"}`, ValidateResponse{Valid: true, Version: "1.2.0"}},
+		{`{"version": "v1.2.3", "strict": true}`, ValidateResponse{Error: "Invalid characters in version"}},
+		{`{"version": "1.2.3-beta", "strict": true}`, ValidateResponse{Valid: true, Version: "1.2.3-beta"}},
+		{`{"version": "foo"}`, ValidateResponse{Error: "Invalid Semantic Version"}},
+	}
+
+	for _, tc := range tests {
+		w := post(t, h, "/validate", tc.body)
+		if w.Code != http.StatusOK {
+			t.Errorf("Expected status 200 for %s, got %d", tc.body, w.Code)
+			continue
+		}
+
+		var resp ValidateResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Errorf("Error decoding %s: %s", w.Body, err)
+			continue
+		}
+		if resp != tc.expected {
+			t.Errorf("Expected %+v for %s, got %+v", tc.expected, tc.body, resp)
+		}
+	}
+}
+
+func TestCompare(t *testing.T) {
+	h := NewHandler()
+
+	tests := []struct {
+		body   string
+		result int
+	}{
+		{`{"a": "1.2.3", "b": "1.2.4"}`, -1},
+		{`{"a": "1.2.3", "b": "1.2.3+build"}`, 0},
+		{`{"a": "2.0.0", "b": "2.0.0-rc.1"}`, 1},
+	}
+
+	for _, tc := range tests {
+		w := post(t, h, "/compare", tc.body)
+		var resp CompareResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil || w.Code != http.StatusOK {
+			t.Errorf("Unexpected response %d %s for %s", w.Code, w.Body, tc.body)
+			continue
+		}
+		if resp.Result != tc.result {
+			t.Errorf("Expected %d for %s, got %d", tc.result, tc.body, resp.Result)
+		}
+	}
+
+	if w := post(t, h, "/compare", `{"a": "foo", "b": "1.2.3"}`); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for an invalid version, got %d", w.Code)
+	}
+}
+
+func TestSatisfies(t *testing.T) {
+	h := NewHandler()
+
+	w := post(t, h, "/satisfies", `{"constraint": "^1.2", "version": "1.4.0"}`)
+	var resp SatisfiesResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil || !resp.Satisfies || len(resp.Reasons) != 0 {
+		t.Errorf("Expected 1.4.0 to satisfy ^1.2, got %s", w.Body)
+	}
+
+	w = post(t, h, "/satisfies", `{"constraint": "^1.2", "version": "2.0.0"}`)
+	resp = SatisfiesResponse{}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil || resp.Satisfies || len(resp.Reasons) != 1 {
+		t.Errorf("Expected 2.0.0 not to satisfy ^1.2 with a reason, got %s", w.Body)
+	}
+
+	w = post(t, h, "/satisfies", `{"constraint": "<=1.0.4-rc !=0.0.4 || 0.1.4", "version": "0.0.0-alpha"}`)
+	resp = SatisfiesResponse{}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil || !resp.Satisfies || len(resp.Reasons) != 0 {
+		t.Errorf("Expected 0.0.0-alpha to satisfy <=1.0.4-rc !=0.0.4 || 0.1.4, got %s", w.Body)
+	}
+
+	if w := post(t, h, "/satisfies", `{"constraint": "^^", "version": "1.0.0"}`); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for an invalid constraint, got %d", w.Code)
+	}
+}
+
+func TestSort(t *testing.T) {
+	h := NewHandler()
+
+	w := post(t, h, "/sort", `{"versions": ["1.10.0", "v1.2.0", "1.2.0-rc.1", "1.2.0+build"]}`)
+	var resp SortResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("Error decoding %s: %s", w.Body, err)
+	}
+	expected := []string{"1.2.0-rc.1", "v1.2.0", "1.2.0+build", "1.10.0"}
+	if !reflect.DeepEqual(resp.Versions, expected) {
+		t.Errorf("Expected %v, got %v", expected, resp.Versions)
+	}
+
+	w = post(t, h, "/sort", `{"versions": ["1.0.0", "3.0.0", "2.0.0"], "descending": true}`)
+	resp = SortResponse{}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("Error decoding %s: %s", w.Body, err)
+	}
+	expected = []string{"3.0.0", "2.0.0", "1.0.0"}
+	if !reflect.DeepEqual(resp.Versions, expected) {
+		t.Errorf("Expected %v, got %v", expected, resp.Versions)
+	}
+
+	if w := post(t, h, "/sort", `{"versions": ["1.0.0", "foo"]}`); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for an invalid version, got %d", w.Code)
+	}
+}
+
+func TestBadRequests(t *testing.T) {
+	h := NewHandler()
+
+	if w := post(t, h, "/validate", `{`); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for invalid JSON, got %d", w.Code)
+	}
+
+	r := httptest.NewRequest(http.MethodGet, "/validate", nil)
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	if w.Code != http.StatusMethodNotAllowed || w.Header().Get("Allow") != http.MethodPost {
+		t.Errorf("Expected status 405 with Allow: POST, got %d %q", w.Code, w.Header().Get("Allow"))
+	}
+	if w.Header().Get("Content-Type") != "application/json" {
+		t.Errorf("Expected a JSON response, got %q", w.Header().Get("Content-Type"))
+	}
+}
@@ -0,0 +1,103 @@
+package semver
+
+import (
+	"container/list"
+	"sync"
+)
+
+// Interner caches parsed versions so that parsing the same string again
+// returns the same *Version rather than parsing and allocating a new one.
+// This is worthwhile when the same versions are parsed many times, such as
+// when scanning the metadata of a registry. Comparing a shared version with
+// itself is also cheaper, as Compare first checks whether both are the same
+// pointer. Versions returned by an Interner are shared and must not be
+// modified, such as with Set or UnmarshalJSON.
+//
+// An Interner holds a limited number of strings and forgets the least
+// recently used one when it is full. It is safe for concurrent use.
+type Interner struct {
+	mu      sync.Mutex
+	max     int
+	entries map[internKey]*list.Element
+	lru     *list.List
+}
+
+type internKey struct {
+	s      string
+	strict bool
+}
+
+type internEntry struct {
+	key internKey
+	v   *Version
+	err error
+}
+
+// NewInterner returns an Interner holding up to max strings. A max of 0 or
+// less leaves it unbounded.
+func NewInterner(max int) *Interner {
+	return &Interner{
+		max:     max,
+		entries: make(map[internKey]*list.Element),
+		lru:     list.New(),
+	}
+}
+
+// NewVersion parses a version as NewVersion does, returning the version
+// parsed from the same string earlier when there is one. Errors are cached
+// too, so an invalid string is only parsed once.
+func (in *Interner) NewVersion(v string) (*Version, error) {
+	return in.parse(internKey{s: v}, NewVersion)
+}
+
+// StrictNewVersion parses a version as StrictNewVersion does, returning the
+// version parsed from the same string earlier when there is one.
+func (in *Interner) StrictNewVersion(v string) (*Version, error) {
+	return in.parse(internKey{s: v, strict: true}, StrictNewVersion)
+}
+
+// Len returns the number of strings held.
+func (in *Interner) Len() int {
+	in.mu.Lock()
+	defer in.mu.Unlock()
+	return in.lru.Len()
+}
+
+// Reset forgets every string.
+func (in *Interner) Reset() {
+	in.mu.Lock()
+	defer in.mu.Unlock()
+	in.entries = make(map[internKey]*list.Element)
+	in.lru.Init()
+}
+
+func (in *Interner) parse(k internKey, parse func(string) (*Version, error)) (*Version, error) {
+	in.mu.Lock()
+	if el, ok := in.entries[k]; ok {
+		in.lru.MoveToFront(el)
+		e := el.Value.(*internEntry)
+		in.mu.Unlock()
+		return e.v, e.err
+	}
+	in.mu.Unlock()
+
+	// Parse without holding the lock. Two goroutines parsing the same new
+	// string may both do so, in which case the first result is kept.
+	v, err := parse(k.s)
+
+	in.mu.Lock()
+	defer in.mu.Unlock()
+	if el, ok := in.entries[k]; ok {
+		e := el.Value.(*internEntry)
+		return e.v, e.err
+	}
+
+	in.entries[k] = in.lru.PushFront(&internEntry{key: k, v: v, err: err})
+	if in.max > 0 && in.lru.Len() > in.max {
+		el := in.lru.Back()
+		in.lru.Remove(el)
+		delete(in.entries, el.Value.(*internEntry).key)
+	}
+
+	return v, err
+}
@@ -0,0 +1,93 @@
+package semver
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+)
+
+func TestInterner(t *testing.T) {
+	in := NewInterner(0)
+
+	v1, err := in.NewVersion("v1.2")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	v2, err := in.NewVersion("v1.2")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if v1 != v2 {
+		t.Error("Expected the same version to be returned")
+	}
+	if v1.Original() != "v1.2" || v1.String() != "1.2.0" {
+		t.Errorf("Unexpected version %s from %s", v1, v1.Original())
+	}
+
+	if _, err := in.StrictNewVersion("v1.2"); err == nil {
+		t.Error("Expected StrictNewVersion to not use the NewVersion result")
+	}
+	if _, err := in.StrictNewVersion("v1.2"); err == nil {
+		t.Error("Expected the error to be cached")
+	}
+	if n := in.Len(); n != 2 {
+		t.Errorf("Expected 2 strings, got %d", n)
+	}
+
+	in.Reset()
+	if n := in.Len(); n != 0 {
+		t.Errorf("Expected no strings after Reset, got %d", n)
+	}
+	v3, err := in.NewVersion("v1.2")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if v3 == v1 || !v3.Equal(v1) {
+		t.Error("Expected a new, equal version after Reset")
+	}
+}
+
+func TestInternerLimit(t *testing.T) {
+	in := NewInterner(2)
+
+	a, _ := in.NewVersion("1.0.0")
+	in.NewVersion("2.0.0")
+	// Using 1.0.0 makes 2.0.0 the least recently used.
+	in.NewVersion("1.0.0")
+	in.NewVersion("3.0.0")
+
+	if n := in.Len(); n != 2 {
+		t.Errorf("Expected 2 strings, got %d", n)
+	}
+	if v, _ := in.NewVersion("1.0.0"); v != a {
+		t.Error("Expected 1.0.0 to be kept")
+	}
+	if in.Len() != 2 {
+		t.Errorf("Expected 2 strings, got %d", in.Len())
+	}
+}
+
+func TestInternerConcurrent(t *testing.T) {
+	in := NewInterner(50)
+
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 1000; i++ {
+				s := fmt.Sprintf("1.%d.%d", i%100, g%2)
+				v, err := in.NewVersion(s)
+				if err != nil || v.Original() != s {
+					t.Errorf("Unexpected result for %s: %v, %v", s, v, err)
+					return
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+
+	if n := in.Len(); n != 50 {
+		t.Errorf("Expected 50 strings, got %d", n)
+	}
+}
@@ -0,0 +1,176 @@
+package semver
+
+import (
+	"strings"
+)
+
+// Coercion is a set of changes ParseLenient made to turn a string into a
+// valid semantic version.
+type Coercion uint
+
+const (
+	// CoercedWhitespace is set when surrounding whitespace was removed.
+	CoercedWhitespace Coercion = 1 << iota
+
+	// CoercedPrefix is set when a v, V or = prefix was removed.
+	CoercedPrefix
+
+	// CoercedPartial is set when a missing minor or patch was set to 0.
+	CoercedPartial
+
+	// CoercedLeadingZeros is set when leading zeros were removed from a
+	// number, such as 01.2.3 or 1.2.3-rc.01.
+	CoercedLeadingZeros
+
+	// CoercedExtraSegments is set when numbers after the patch, such as the
+	// 4 in 1.2.3.4, were moved to the build metadata.
+	CoercedExtraSegments
+
+	// CoercedCharacters is set when non-ASCII lookalike characters, such as
+	// full-width digits, were replaced with their ASCII equivalents.
+	CoercedCharacters
+)
+
+var coercionNames = []string{
+	"whitespace",
+	"prefix",
+	"partial",
+	"leading zeros",
+	"extra segments",
+	"characters",
+}
+
+// Has reports whether all of the coercions in o are in c.
+func (c Coercion) Has(o Coercion) bool {
+	return c&o == o
+}
+
+// String returns the names of the coercions separated by commas, or "none".
+func (c Coercion) String() string {
+	var names []string
+	for i, n := range coercionNames {
+		if c.Has(1 << uint(i)) {
+			names = append(names, n)
+		}
+	}
+	if len(names) == 0 {
+		return "none"
+	}
+	return strings.Join(names, ", ")
+}
+
+// ParseLenient parses a version, coercing common deviations from the spec
+// found in real world tags into a valid semantic version. On top of what
+// NewVersion accepts it removes surrounding whitespace, a V or = prefix and
+// leading zeros in prerelease numbers, and moves numbers after the patch,
+// such as the 4 in 1.2.3.4, to the start of the build metadata so 1.2.3.4
+// becomes 1.2.3+4. The coercions that were made are returned with the
+// version, whose Original is the string that was passed in.
+func ParseLenient(v string) (*Version, Coercion, error) {
+	if MaxVersionLength > 0 && len(v) > MaxVersionLength {
+		return nil, 0, ErrVersionTooLong
+	}
+
+	var c Coercion
+
+	s := strings.TrimSpace(v)
+	if s != v {
+		c |= CoercedWhitespace
+	}
+
+	if !isASCII(s) {
+		s = normalizeASCII(s)
+		if !isASCII(s) {
+			return nil, c, ErrNonASCII
+		}
+		c |= CoercedCharacters
+	}
+
+	if t := strings.TrimPrefix(s, "="); t != s {
+		s = strings.TrimSpace(t)
+		c |= CoercedPrefix
+	}
+	if len(s) > 0 && (s[0] == 'v' || s[0] == 'V') {
+		s = s[1:]
+		c |= CoercedPrefix
+	}
+
+	var metadata, pre string
+	if i := strings.IndexByte(s, '+'); i != -1 {
+		s, metadata = s[:i], s[i+1:]
+		if metadata == "" {
+			return nil, c, ErrInvalidMetadata
+		}
+	}
+	if i := strings.IndexByte(s, '-'); i != -1 {
+		s, pre = s[:i], s[i+1:]
+		if pre == "" {
+			return nil, c, ErrInvalidPrerelease
+		}
+	}
+
+	parts := strings.Split(s, ".")
+	for i, p := range parts {
+		if p == "" || !containsOnly(p, num) {
+			return nil, c, ErrInvalidSemVer
+		}
+		if t := trimZeros(p); t != p {
+			parts[i] = t
+			c |= CoercedLeadingZeros
+		}
+	}
+
+	if len(parts) > 3 {
+		extra := strings.Join(parts[3:], ".")
+		if metadata != "" {
+			metadata = extra + "." + metadata
+		} else {
+			metadata = extra
+		}
+		parts = parts[:3]
+		c |= CoercedExtraSegments
+	}
+	for len(parts) < 3 {
+		parts = append(parts, "0")
+		c |= CoercedPartial
+	}
+
+	if pre != "" {
+		ids := strings.Split(pre, ".")
+		for i, id := range ids {
+			if id == "" || !containsOnly(id, num) {
+				continue
+			}
+			if t := trimZeros(id); t != id {
+				ids[i] = t
+				c |= CoercedLeadingZeros
+			}
+		}
+		pre = strings.Join(ids, ".")
+	}
+
+	s = strings.Join(parts, ".")
+	if pre != "" {
+		s += "-" + pre
+	}
+	if metadata != "" {
+		s += "+" + metadata
+	}
+
+	sv, err := StrictNewVersion(s)
+	if err != nil {
+		return nil, c, err
+	}
+	sv.original = v
+
+	return sv, c, nil
+}
+
+// trimZeros removes the leading zeros from a number, keeping a single 0.
+func trimZeros(s string) string {
+	t := strings.TrimLeft(s, "0")
+	if t == "" {
+		return "0"
+	}
+	return t
+}
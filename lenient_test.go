@@ -0,0 +1,67 @@
+package semver
+
+import (
+	"testing"
+)
+
+func TestParseLenient(t *testing.T) {
+	tests := []struct {
+		version   string
+		expected  string
+		coercions Coercion
+		err       bool
+	}{
+		{"1.2.3", "1.2.3", 0, false},
+		{"v1.2.3", "1.2.3", CoercedPrefix, false},
+		{"V1.2.3", "1.2.3", CoercedPrefix, false},
+		{"= v1.2.3", "1.2.3", CoercedPrefix, false},
+		{" 1.2.3\n", "1.2.3", CoercedWhitespace, false},
+		{"1", "1.0.0", CoercedPartial, false},
+		{"v1.2", "1.2.0", CoercedPrefix | CoercedPartial, false},
+		{"01.02.003", "1.2.3", CoercedLeadingZeros, false},
+		{"1.2.3-rc.01", "1.2.3-rc.1", CoercedLeadingZeros, false},
+		{"1.2.3-rc01", "1.2.3-rc01", 0, false},
+		{"1.2.3.4", "1.2.3+4", CoercedExtraSegments, false},
+		{"1.2.3.4.5-beta+b7", "1.2.3-beta+4.5.b7", CoercedExtraSegments, false},
+		{"1.2.3－1", "1.2.3-1", CoercedCharacters, false},
+		{" v1.02 ", "1.2.0", CoercedWhitespace | CoercedPrefix | CoercedLeadingZeros | CoercedPartial, false},
+		{"", "", 0, true},
+		{"v", "", 0, true},
+		{"1..2", "", 0, true},
+		{"1.2.x", "", 0, true},
+		{"1.2.3-", "", 0, true},
+		{"release-1.2.3", "", 0, true},
+	}
+
+	for _, tc := range tests {
+		v, c, err := ParseLenient(tc.version)
+		if tc.err {
+			if err == nil {
+				t.Errorf("Expected error parsing %q, got %s", tc.version, v)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Error parsing %q: %s", tc.version, err)
+			continue
+		}
+		if v.String() != tc.expected {
+			t.Errorf("Expected %q to parse as %q, got %q", tc.version, tc.expected, v)
+		}
+		if v.Original() != tc.version {
+			t.Errorf("Expected original %q, got %q", tc.version, v.Original())
+		}
+		if c != tc.coercions {
+			t.Errorf("Expected %q to have coercions %s, got %s", tc.version, tc.coercions, c)
+		}
+	}
+}
+
+func TestCoercionString(t *testing.T) {
+	if s := Coercion(0).String(); s != "none" {
+		t.Errorf("Expected none, got %q", s)
+	}
+	if s := (CoercedPrefix | CoercedPartial).String(); s != "prefix, partial" {
+		t.Errorf("Expected \"prefix, partial\", got %q", s)
+	}
+}
@@ -0,0 +1,114 @@
+package semver
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// LineOption configures ParseLines.
+type LineOption func(*lineOptions)
+
+type lineOptions struct {
+	strict   bool
+	maxLines int
+}
+
+// StrictLines makes ParseLines parse each line with StrictNewVersion instead
+// of NewVersion.
+func StrictLines() LineOption {
+	return func(o *lineOptions) {
+		o.strict = true
+	}
+}
+
+// MaxLines stops ParseLines after it has read n lines, bounding the number
+// of versions kept in memory. A limit of 0 or less reads every line.
+func MaxLines(n int) LineOption {
+	return func(o *lineOptions) {
+		o.maxLines = n
+	}
+}
+
+// ParseLines reads newline delimited versions from r, such as the output of
+// git tag, until the end of the input. Surrounding whitespace is ignored, as
+// are blank lines and lines starting with #. Lines that cannot be parsed are
+// reported in the returned errors, which include the line number, and
+// parsing continues with the next line. When MaxVersionLength is set, longer
+// lines are reported without being held in memory. An error reading from r is
+// returned as the last error.
+func ParseLines(r io.Reader, opts ...LineOption) (Collection, []error) {
+	var o lineOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	parse := NewVersion
+	if o.strict {
+		parse = StrictNewVersion
+	}
+
+	var vs Collection
+	var errs []error
+
+	br := bufio.NewReader(r)
+	for n := 1; o.maxLines <= 0 || n <= o.maxLines; n++ {
+		line, tooLong, err := readLine(br)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			errs = append(errs, err)
+			break
+		}
+
+		if tooLong {
+			errs = append(errs, fmt.Errorf("line %d: %s", n, ErrVersionTooLong))
+		} else if s := strings.TrimSpace(line); s != "" && !strings.HasPrefix(s, "#") {
+			v, perr := parse(s)
+			if perr != nil {
+				errs = append(errs, fmt.Errorf("line %d: %q: %s", n, s, perr))
+			} else {
+				vs = append(vs, v)
+			}
+		}
+	}
+
+	return vs, errs
+}
+
+// readLine reads a line without its line ending. When the line is longer
+// than MaxVersionLength, plus room for surrounding whitespace, the rest of it
+// is discarded and tooLong is set. io.EOF is only returned when there are no
+// more lines.
+func readLine(br *bufio.Reader) (line string, tooLong bool, err error) {
+	limit := MaxVersionLength
+	if limit > 0 {
+		// Allow for surrounding whitespace and a \r\n line ending.
+		limit += 64
+	}
+
+	var buf []byte
+	for {
+		chunk, isPrefix, rerr := br.ReadLine()
+		if rerr != nil {
+			if rerr == io.EOF && (len(buf) > 0 || tooLong) {
+				return string(buf), tooLong, nil
+			}
+			return string(buf), tooLong, rerr
+		}
+
+		if !tooLong {
+			buf = append(buf, chunk...)
+			if limit > 0 && len(buf) > limit {
+				buf = nil
+				tooLong = true
+			}
+		}
+
+		if !isPrefix {
+			return string(buf), tooLong, nil
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package semver
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func TestParseLines(t *testing.T) {
+	defer func(l int) { MaxVersionLength = l }(MaxVersionLength)
+	MaxVersionLength = 256
+
+	input := "v1.0.0\n" +
+		"  1.2.3-beta.1  \r\n" +
+		"\n" +
+		"# a comment\n" +
+		"not-a-version\n" +
+		strings.Repeat("1", 1000) + "\n" +
+		"1.2\n" +
+		"2.0.0"
+
+	vs, errs := ParseLines(strings.NewReader(input))
+
+	expected := []string{"1.0.0", "1.2.3-beta.1", "1.2.0", "2.0.0"}
+	if len(vs) != len(expected) {
+		t.Fatalf("Expected %d versions, got %d", len(expected), len(vs))
+	}
+	for i, e := range expected {
+		if vs[i].String() != e {
+			t.Errorf("Expected version %d to be %q, got %q", i, e, vs[i])
+		}
+	}
+
+	if len(errs) != 2 {
+		t.Fatalf("Expected 2 errors, got %v", errs)
+	}
+	if !strings.HasPrefix(errs[0].Error(), "line 5: ") {
+		t.Errorf("Expected the first error to be on line 5, got %q", errs[0])
+	}
+	if errs[1].Error() != "line 6: "+ErrVersionTooLong.Error() {
+		t.Errorf("Expected the second error to be a long line on line 6, got %q", errs[1])
+	}
+}
+
+func TestParseLinesOptions(t *testing.T) {
+	input := "1.0.0\nv1.1.0\n1.2\n1.3.0\n"
+
+	vs, errs := ParseLines(strings.NewReader(input), StrictLines())
+	if len(vs) != 2 || len(errs) != 2 {
+		t.Errorf("Expected 2 versions and 2 errors parsing strictly, got %v and %v", vs, errs)
+	}
+
+	vs, errs = ParseLines(strings.NewReader(input), MaxLines(2))
+	if len(vs) != 2 || len(errs) != 0 {
+		t.Errorf("Expected 2 versions reading 2 lines, got %v and %v", vs, errs)
+	}
+}
+
+func TestParseLinesReadError(t *testing.T) {
+	fail := errors.New("read failed")
+
+	vs, errs := ParseLines(&errAfterReader{r: strings.NewReader("1.0.0\n2.0.0\n"), err: fail})
+	if len(vs) != 2 {
+		t.Errorf("Expected 2 versions before the error, got %v", vs)
+	}
+	if len(errs) != 1 || errs[0] != fail {
+		t.Errorf("Expected the read error to be returned, got %v", errs)
+	}
+
+	// Small reads must not split lines.
+	vs, errs = ParseLines(iotest.OneByteReader(strings.NewReader("1.0.0\n2.0.0")))
+	if len(vs) != 2 || len(errs) != 0 {
+		t.Errorf("Expected 2 versions reading one byte at a time, got %v and %v", vs, errs)
+	}
+}
+
+// errAfterReader returns err once r is exhausted.
+type errAfterReader struct {
+	r   *strings.Reader
+	err error
+}
+
+func (e *errAfterReader) Read(p []byte) (int, error) {
+	if e.r.Len() == 0 {
+		return 0, e.err
+	}
+	return e.r.Read(p)
+}
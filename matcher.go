@@ -0,0 +1,120 @@
+package semver
+
+// Matcher is a compiled form of Constraints for checking many versions
+// against the same constraints. The work Check does on every call to apply
+// the options on Constraints is done once by Compile, and exclusions of exact
+// versions are looked up in a map rather than checked one at a time. A
+// Matcher admits exactly the versions the Constraints it was compiled from
+// do and is safe for concurrent use.
+type Matcher struct {
+	groups []matcherGroup
+}
+
+// matcherGroup is an AND group of constraints.
+type matcherGroup struct {
+	constraints []matcherConstraint
+
+	// excluded holds the versions excluded by != constraints on exact
+	// versions.
+	excluded map[VersionKey]struct{}
+}
+
+// matcherConstraint is a constraint with its check function resolved.
+type matcherConstraint struct {
+	fn cfunc
+	c  *constraint
+
+	// pre is used in place of c for prerelease versions when the
+	// constraints have IncludePrerelease set, as done by Constraints.check.
+	pre *constraint
+}
+
+// Compile returns a Matcher for the constraints. Changes to the options on cs
+// after Compile returns do not affect the Matcher.
+func (cs Constraints) Compile() *Matcher {
+	m := &Matcher{groups: make([]matcherGroup, len(cs.constraints))}
+
+	for k, o := range cs.constraints {
+		g := &m.groups[k]
+		for _, c := range o {
+			if c.origfunc == "!=" && !c.dirty &&
+				!(cs.ExclusionsMatchMetadata && c.con.metadata != "") {
+				if g.excluded == nil {
+					g.excluded = make(map[VersionKey]struct{})
+				}
+				g.excluded[c.con.Key()] = struct{}{}
+				continue
+			}
+
+			mc := matcherConstraint{fn: constraintOps[c.origfunc], c: c}
+			if cs.ExclusionsMatchMetadata && c.origfunc == "!=" && !c.dirty {
+				mc.fn = constraintNotEqualMetadata
+			}
+			if cs.EqualityMatchesMetadata && isEqualityOp(c.origfunc) && !c.dirty && c.con.metadata != "" {
+				mc.fn = constraintEqualMetadata
+			}
+
+			if cs.IncludePrerelease && c.con.pre == "" {
+				// Constraints.check copies the constraint for each
+				// prerelease it checks. Do it once here instead.
+				mc.pre = cs.prereleaseConstraint(c)
+			}
+
+			g.constraints = append(g.constraints, mc)
+		}
+	}
+
+	return m
+}
+
+// Check tests if a version satisfies the constraints.
+func (m *Matcher) Check(v *Version) bool {
+	for i := range m.groups {
+		if m.groups[i].check(v) {
+			return true
+		}
+	}
+	return false
+}
+
+// Filter returns the versions that satisfy the constraints, in the order they
+// appear in vs.
+func (m *Matcher) Filter(vs Collection) Collection {
+	var out Collection
+	for _, v := range vs {
+		if m.Check(v) {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+// CheckAny reports whether any of the versions satisfies the constraints. It
+// stops at the first one that does.
+func (m *Matcher) CheckAny(vs Collection) bool {
+	for _, v := range vs {
+		if m.Check(v) {
+			return true
+		}
+	}
+	return false
+}
+
+func (g *matcherGroup) check(v *Version) bool {
+	if g.excluded != nil {
+		if _, ok := g.excluded[v.Key()]; ok {
+			return false
+		}
+	}
+
+	for _, mc := range g.constraints {
+		c := mc.c
+		if mc.pre != nil && v.pre != "" {
+			c = mc.pre
+		}
+		if ok, _ := mc.fn(v, c); !ok {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,89 @@
+package semver
+
+import (
+	"testing"
+)
+
+func TestMatcher(t *testing.T) {
+	constraints := []string{
+		"*",
+		"1.2.3",
+		"!=1.2.3",
+		"!=1.2.3 !=1.2.4 !=2.0.0-rc.1",
+		"!=1.2.3+build.1",
+		"1.2.3+build.1 || 1.3.0",
+		"!=1.2.x",
+		">=1.2.0, <1.4.0, !=1.3.1",
+		"^1.2 || >=3.0.0-0",
+		"~1.2.3 || 2.x",
+		">1.2 <=2",
+		"<2",
+		">=1.2",
+		"1.0.0 - 2.0.0",
+		">=1.2.3-beta.1, !=1.2.3-beta.2",
+	}
+	versions := []string{
+		"0.9.0", "1.0.0", "1.2.0-beta", "1.2.0", "1.2.3-beta.1", "1.2.3-beta.2",
+		"1.2.3", "1.2.3+build.1", "1.2.3+build.2", "1.2.4", "1.3.0", "1.3.1",
+		"1.4.0", "2.0.0-rc.1", "2.0.0", "2.5.0", "3.0.0-alpha", "3.1.0",
+	}
+	options := []Constraints{
+		{},
+		{IncludePrerelease: true},
+		{IncludePrerelease: true, SnapPartialBounds: true},
+		{ExclusionsMatchMetadata: true},
+		{EqualityMatchesMetadata: true},
+	}
+
+	var vs Collection
+	for _, s := range versions {
+		vs = append(vs, MustParse(s))
+	}
+
+	for _, s := range constraints {
+		for _, o := range options {
+			c, err := NewConstraint(s)
+			if err != nil {
+				t.Fatalf("Error parsing constraint %q: %s", s, err)
+			}
+			c.ExclusionsMatchMetadata = o.ExclusionsMatchMetadata
+			c.EqualityMatchesMetadata = o.EqualityMatchesMetadata
+			c.IncludePrerelease = o.IncludePrerelease
+			c.SnapPartialBounds = o.SnapPartialBounds
+
+			m := c.Compile()
+			for _, v := range vs {
+				if a, e := m.Check(v), c.Check(v); a != e {
+					t.Errorf("Expected compiled %q with %+v check of %s to be %t", s, o, v, e)
+				}
+			}
+
+			filtered := m.Filter(vs)
+			expected := vs.Filter(c)
+			if len(filtered) != len(expected) {
+				t.Errorf("Expected compiled %q with %+v to filter to %v, got %v", s, o, expected, filtered)
+			}
+			if m.CheckAny(vs) != (len(expected) > 0) {
+				t.Errorf("Expected compiled %q with %+v CheckAny to be %t", s, o, len(expected) > 0)
+			}
+		}
+	}
+}
+
+func TestMatcherOptionsCopied(t *testing.T) {
+	c, err := NewConstraint(">=1.0.0")
+	if err != nil {
+		t.Fatalf("Error parsing constraint: %s", err)
+	}
+
+	m := c.Compile()
+	c.IncludePrerelease = true
+
+	v := MustParse("1.5.0-rc.1")
+	if m.Check(v) {
+		t.Errorf("Expected the matcher to ignore options set after Compile")
+	}
+	if m.CheckAny(Collection{v}) {
+		t.Errorf("Expected CheckAny to be false")
+	}
+}
@@ -0,0 +1,100 @@
+package semver
+
+import (
+	"sort"
+)
+
+// CompatibilityMatrix records which versions of a shared dependency each of
+// a number of products supports. It is built by NewCompatibilityMatrix and is
+// meant for platform teams publishing support matrices.
+type CompatibilityMatrix struct {
+	// Products are the names of the products in sorted order.
+	Products []string
+
+	// Versions are the versions of the dependency in ascending order.
+	Versions Collection
+
+	// Supports holds, for each product, whether it supports each version,
+	// indexed in the same order as Products and Versions.
+	Supports [][]bool
+}
+
+// NewCompatibilityMatrix checks each of the versions against the supported
+// version constraints of each product.
+func NewCompatibilityMatrix(products map[string]*Constraints, versions Collection) *CompatibilityMatrix {
+	m := &CompatibilityMatrix{
+		Products: make([]string, 0, len(products)),
+		Versions: make(Collection, len(versions)),
+	}
+
+	for name := range products {
+		m.Products = append(m.Products, name)
+	}
+	sort.Strings(m.Products)
+
+	copy(m.Versions, versions)
+	sort.Sort(m.Versions)
+
+	m.Supports = make([][]bool, len(m.Products))
+	for i, name := range m.Products {
+		m.Supports[i] = make([]bool, len(m.Versions))
+		for j, v := range m.Versions {
+			m.Supports[i][j] = products[name].Check(v)
+		}
+	}
+
+	return m
+}
+
+// Supported returns the versions supported by every one of the named
+// products, or by all products when none are named. Products not in the
+// matrix support no versions.
+func (m *CompatibilityMatrix) Supported(products ...string) Collection {
+	rows := make([]int, 0, len(products))
+	for _, name := range products {
+		i := sort.SearchStrings(m.Products, name)
+		if i == len(m.Products) || m.Products[i] != name {
+			return nil
+		}
+		rows = append(rows, i)
+	}
+	if len(products) == 0 {
+		for i := range m.Products {
+			rows = append(rows, i)
+		}
+	}
+
+	var out Collection
+	for j, v := range m.Versions {
+		all := true
+		for _, i := range rows {
+			if !m.Supports[i][j] {
+				all = false
+				break
+			}
+		}
+		if all {
+			out = append(out, v)
+		}
+	}
+
+	return out
+}
+
+// Window returns the oldest and newest versions supported by every product,
+// the mutually supported window. Both are nil when there are none. Versions
+// within the window may still be unsupported by some products, see
+// Supported for the exact set.
+func (m *CompatibilityMatrix) Window() (oldest, newest *Version) {
+	vs := m.Supported()
+	if len(vs) == 0 {
+		return nil, nil
+	}
+	return vs[0], vs[len(vs)-1]
+}
+
+// Compatible reports whether the two products support at least one version
+// in common.
+func (m *CompatibilityMatrix) Compatible(a, b string) bool {
+	return len(m.Supported(a, b)) > 0
+}
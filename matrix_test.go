@@ -0,0 +1,73 @@
+package semver
+
+import (
+	"testing"
+)
+
+func TestCompatibilityMatrix(t *testing.T) {
+	products := map[string]*Constraints{}
+	for name, c := range map[string]string{
+		"web":    ">=1.2, <3",
+		"api":    "^2",
+		"worker": "^1.4 || 2.0.x",
+		"legacy": "<1.3",
+	} {
+		cs, err := NewConstraint(c)
+		if err != nil {
+			t.Fatalf("Error parsing constraint %q: %s", c, err)
+		}
+		products[name] = cs
+	}
+
+	var versions Collection
+	for _, s := range []string{"2.1.0", "1.2.0", "1.4.0", "2.0.0", "3.0.0", "1.5.0", "2.0.1"} {
+		versions = append(versions, MustParse(s))
+	}
+
+	m := NewCompatibilityMatrix(products, versions)
+
+	if len(m.Products) != 4 || m.Products[0] != "api" || m.Products[3] != "worker" {
+		t.Errorf("Expected sorted products, got %v", m.Products)
+	}
+	if m.Versions[0].String() != "1.2.0" || m.Versions[len(m.Versions)-1].String() != "3.0.0" {
+		t.Errorf("Expected sorted versions, got %v", m.Versions)
+	}
+	if !m.Supports[3][1] || m.Supports[3][0] {
+		t.Errorf("Expected worker to support 1.4.0 but not 1.2.0")
+	}
+
+	check := func(vs Collection, expected ...string) {
+		t.Helper()
+		if len(vs) != len(expected) {
+			t.Errorf("Expected %v, got %v", expected, vs)
+			return
+		}
+		for i, e := range expected {
+			if vs[i].String() != e {
+				t.Errorf("Expected %v, got %v", expected, vs)
+				return
+			}
+		}
+	}
+
+	check(m.Supported("web", "api", "worker"), "2.0.0", "2.0.1")
+	check(m.Supported("web", "legacy"), "1.2.0")
+	check(m.Supported("api", "legacy"))
+	check(m.Supported("web", "missing"))
+	check(m.Supported())
+
+	if m.Compatible("api", "legacy") || !m.Compatible("web", "worker") {
+		t.Error("Expected web and worker to be compatible but not api and legacy")
+	}
+
+	if o, n := m.Window(); o != nil || n != nil {
+		t.Errorf("Expected no mutually supported window, got %v to %v", o, n)
+	}
+
+	delete(products, "legacy")
+	m = NewCompatibilityMatrix(products, versions)
+	o, n := m.Window()
+	if o == nil || o.String() != "2.0.0" || n.String() != "2.0.1" {
+		t.Errorf("Expected the window 2.0.0 to 2.0.1, got %v to %v", o, n)
+	}
+}
@@ -0,0 +1,249 @@
+package semver
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MajorSuffix returns the suffix Go requires at the end of the path of a
+// module with the given version. It is "" for major versions 0 and 1 and
+// "/vN" for a major version N of 2 or more. Versions with +incompatible
+// metadata predate modules and have no suffix.
+func MajorSuffix(v *Version) string {
+	if v.Major() < 2 || v.Metadata() == "incompatible" {
+		return ""
+	}
+	return "/v" + strconv.FormatUint(v.Major(), 10)
+}
+
+// CheckPathMatchesVersion returns an error if a Go module path is not valid
+// for a module with the given version. A path ending in /vN, for N of 2 or
+// more, is only valid for major version N. A path without a suffix is only
+// valid for major versions 0 and 1, and for versions with +incompatible
+// metadata. gopkg.in paths, which end in .vN for every major version, are
+// also supported.
+func CheckPathMatchesVersion(path string, v *Version) error {
+	_, major, ok := splitPathMajor(path)
+	if !ok {
+		return fmt.Errorf("Invalid major version suffix in module path %q", path)
+	}
+
+	if strings.HasPrefix(path, "gopkg.in/") {
+		if major != v.Major() {
+			return fmt.Errorf("Module path %q requires major version %d, got %s", path, major, v)
+		}
+		return nil
+	}
+
+	if major == 0 {
+		if v.Major() >= 2 && v.Metadata() != "incompatible" {
+			return fmt.Errorf("Module path %q requires the suffix /v%d for version %s", path, v.Major(), v)
+		}
+		return nil
+	}
+
+	if major != v.Major() || v.Metadata() == "incompatible" {
+		return fmt.Errorf("Module path %q requires major version %d, got %s", path, major, v)
+	}
+
+	return nil
+}
+
+// TrimMajorSuffix returns the module path without its major version suffix.
+// Paths without a valid suffix are returned unchanged.
+func TrimMajorSuffix(path string) string {
+	prefix, _, ok := splitPathMajor(path)
+	if !ok {
+		return path
+	}
+	return prefix
+}
+
+// SetMajorSuffix returns the module path with its major version suffix
+// replaced by the one required for the given version. For example, the path
+// example.com/mod/v2 with the version 3.0.0 becomes example.com/mod/v3.
+func SetMajorSuffix(path string, v *Version) string {
+	prefix := TrimMajorSuffix(path)
+
+	if strings.HasPrefix(path, "gopkg.in/") {
+		return prefix + ".v" + strconv.FormatUint(v.Major(), 10)
+	}
+
+	return prefix + MajorSuffix(v)
+}
+
+// splitPathMajor splits a module path into the path before the major version
+// suffix and the major version. The major version is 0 when there is no
+// suffix. ok is false when the suffix is malformed, such as /v1 or /v02, or
+// when a gopkg.in path has no suffix.
+func splitPathMajor(path string) (prefix string, major uint64, ok bool) {
+	sep := "/v"
+	if strings.HasPrefix(path, "gopkg.in/") {
+		sep = ".v"
+	}
+
+	i := len(path)
+	for i > 0 && path[i-1] >= '0' && path[i-1] <= '9' {
+		i--
+	}
+	digits := path[i:]
+
+	if digits == "" || !strings.HasSuffix(path[:i], sep) {
+		if sep == ".v" {
+			return path, 0, false
+		}
+		return path, 0, true
+	}
+
+	if len(digits) > 1 && digits[0] == '0' {
+		return path, 0, false
+	}
+
+	n, err := strconv.ParseUint(digits, 10, 64)
+	if err != nil || (sep == "/v" && n < 2) {
+		return path, 0, false
+	}
+
+	return path[:i-len(sep)], n, true
+}
+
+// ErrNotPseudoVersion is returned by the pseudo-version functions for a
+// version that is not a pseudo-version.
+var ErrNotPseudoVersion = errors.New("Not a pseudo-version")
+
+// pseudoVersionRegex matches the normalized form of the three kinds of
+// pseudo-version: vX.0.0-T-R when there is no earlier tag, vX.Y.Z-pre.0.T-R
+// after the prerelease vX.Y.Z-pre, and vX.Y.(Z+1)-0.T-R after the release
+// vX.Y.Z, where T is a UTC timestamp and R a revision identifier.
+var pseudoVersionRegex = regexp.MustCompile(`^[0-9]+\.(0\.0-|[0-9]+\.[0-9]+-([^+]*\.)?0\.)([0-9]{14})-([A-Za-z0-9]+)(\+.*)?$`)
+
+// pseudoTimeFormat is the layout of the timestamp in a pseudo-version.
+const pseudoTimeFormat = "20060102150405"
+
+// NewGoModVersion parses a version the way the go command accepts it in a
+// go.mod file. It must have a v prefix and all three parts, as in v1.2.3,
+// and the only build metadata allowed is +incompatible on major versions of
+// 2 or more. The v prefix is kept in Original.
+func NewGoModVersion(v string) (*Version, error) {
+	if !strings.HasPrefix(v, "v") {
+		return nil, fmt.Errorf("Go module version %q must start with v", v)
+	}
+
+	sv, err := StrictNewVersion(v[1:])
+	if err != nil {
+		return nil, err
+	}
+
+	switch sv.Metadata() {
+	case "":
+	case "incompatible":
+		if sv.Major() < 2 {
+			return nil, fmt.Errorf("Go module version %q is +incompatible with major version %d", v, sv.Major())
+		}
+	default:
+		return nil, fmt.Errorf("Go module version %q has build metadata other than +incompatible", v)
+	}
+
+	sv.original = v
+	return sv, nil
+}
+
+// IsPseudoVersion reports whether v is a Go pseudo-version, such as
+// v0.0.0-20210101000000-abcdef123456. Pseudo-versions identify untagged
+// revisions and their prerelease sorts them after the tag they are based on
+// and before the next release.
+func IsPseudoVersion(v *Version) bool {
+	return pseudoVersionRegex.MatchString(v.String())
+}
+
+// NewPseudoVersion returns the pseudo-version for revision rev committed at t.
+// base is the closest earlier tag or nil if there is none, in which case
+// major is the major version of the result. A revision longer than 12
+// characters, such as a full commit hash, is shortened to 12 as the go
+// command does.
+func NewPseudoVersion(major uint64, base *Version, t time.Time, rev string) (*Version, error) {
+	if len(rev) > 12 {
+		rev = rev[:12]
+	}
+	suffix := t.UTC().Format(pseudoTimeFormat) + "-" + rev
+
+	var v Version
+	var pre string
+	switch {
+	case base == nil:
+		v = Version{major: major}
+		pre = suffix
+	case base.Prerelease() != "":
+		v = *base
+		pre = base.Prerelease() + ".0." + suffix
+	default:
+		v = base.IncPatch()
+		pre = "0." + suffix
+	}
+
+	v, err := v.SetPrerelease(pre)
+	if err != nil {
+		return nil, err
+	}
+	v.metadata = ""
+	if base != nil && base.Metadata() == "incompatible" {
+		v.metadata = "incompatible"
+	}
+	// Pseudo-versions always have the v prefix.
+	v.original = "v" + v.String()
+
+	return &v, nil
+}
+
+// PseudoVersionTime returns the commit time of the revision a pseudo-version
+// identifies.
+func PseudoVersionTime(v *Version) (time.Time, error) {
+	m := pseudoVersionRegex.FindStringSubmatch(v.String())
+	if m == nil {
+		return time.Time{}, ErrNotPseudoVersion
+	}
+	return time.Parse(pseudoTimeFormat, m[3])
+}
+
+// PseudoVersionRev returns the revision identifier of a pseudo-version.
+func PseudoVersionRev(v *Version) (string, error) {
+	m := pseudoVersionRegex.FindStringSubmatch(v.String())
+	if m == nil {
+		return "", ErrNotPseudoVersion
+	}
+	return m[4], nil
+}
+
+// PseudoVersionBase returns the tag a pseudo-version is based on. It is nil
+// when the pseudo-version has no earlier tag, as in v0.0.0-T-R. The base of
+// an +incompatible pseudo-version is also +incompatible.
+func PseudoVersionBase(v *Version) (*Version, error) {
+	m := pseudoVersionRegex.FindStringSubmatch(v.String())
+	if m == nil {
+		return nil, ErrNotPseudoVersion
+	}
+
+	if m[1] == "0.0-" {
+		return nil, nil
+	}
+
+	base := *v
+	if m[2] != "" {
+		// vX.Y.Z-pre.0.T-R is based on vX.Y.Z-pre.
+		base.pre = strings.TrimSuffix(m[2], ".")
+	} else {
+		// vX.Y.(Z+1)-0.T-R is based on vX.Y.Z.
+		if v.Patch() == 0 {
+			return nil, fmt.Errorf("Pseudo-version %s has no base release", v)
+		}
+		base.pre = ""
+		base.patch--
+	}
+	base.original = "v" + base.String()
+
+	return &base, nil
+}
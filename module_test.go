@@ -0,0 +1,232 @@
+package semver
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMajorSuffix(t *testing.T) {
+	tests := []struct {
+		version string
+		suffix  string
+	}{
+		{"0.1.0", ""},
+		{"1.2.3", ""},
+		{"2.0.0", "/v2"},
+		{"3.1.0-beta", "/v3"},
+		{"4.0.0+incompatible", ""},
+	}
+
+	for _, tc := range tests {
+		if s := MajorSuffix(MustParse(tc.version)); s != tc.suffix {
+			t.Errorf("Expected suffix %q for %q, got %q", tc.suffix, tc.version, s)
+		}
+	}
+}
+
+func TestCheckPathMatchesVersion(t *testing.T) {
+	tests := []struct {
+		path    string
+		version string
+		err     bool
+	}{
+		{"example.com/mod", "0.1.0", false},
+		{"example.com/mod", "1.2.3", false},
+		{"example.com/mod", "2.0.0", true},
+		{"example.com/mod", "2.0.0+incompatible", false},
+		{"example.com/mod/v2", "2.0.0", false},
+		{"example.com/mod/v2", "2.5.1-rc.1", false},
+		{"example.com/mod/v2", "3.0.0", true},
+		{"example.com/mod/v2", "1.0.0", true},
+		{"example.com/mod/v2", "2.0.0+incompatible", true},
+		{"example.com/mod/v1", "1.0.0", true},
+		{"example.com/mod/v02", "2.0.0", true},
+		{"example.com/modv2", "2.0.0", true},
+		{"gopkg.in/yaml.v2", "2.4.0", false},
+		{"gopkg.in/yaml.v1", "1.0.0", false},
+		{"gopkg.in/yaml.v2", "3.0.0", true},
+		{"gopkg.in/yaml", "1.0.0", true},
+	}
+
+	for _, tc := range tests {
+		err := CheckPathMatchesVersion(tc.path, MustParse(tc.version))
+		if tc.err && err == nil {
+			t.Errorf("Expected error for path %q with version %q", tc.path, tc.version)
+		} else if !tc.err && err != nil {
+			t.Errorf("Unexpected error for path %q with version %q: %s", tc.path, tc.version, err)
+		}
+	}
+}
+
+func TestTrimMajorSuffix(t *testing.T) {
+	tests := []struct {
+		path     string
+		expected string
+	}{
+		{"example.com/mod", "example.com/mod"},
+		{"example.com/mod/v2", "example.com/mod"},
+		{"example.com/mod/v1", "example.com/mod/v1"},
+		{"example.com/modv2", "example.com/modv2"},
+		{"gopkg.in/yaml.v3", "gopkg.in/yaml"},
+	}
+
+	for _, tc := range tests {
+		if p := TrimMajorSuffix(tc.path); p != tc.expected {
+			t.Errorf("Expected %q to trim to %q, got %q", tc.path, tc.expected, p)
+		}
+	}
+}
+
+func TestSetMajorSuffix(t *testing.T) {
+	tests := []struct {
+		path     string
+		version  string
+		expected string
+	}{
+		{"example.com/mod", "2.0.0", "example.com/mod/v2"},
+		{"example.com/mod/v2", "3.0.0", "example.com/mod/v3"},
+		{"example.com/mod/v2", "1.4.0", "example.com/mod"},
+		{"example.com/mod", "1.4.0", "example.com/mod"},
+		{"gopkg.in/yaml.v2", "3.0.0", "gopkg.in/yaml.v3"},
+	}
+
+	for _, tc := range tests {
+		p := SetMajorSuffix(tc.path, MustParse(tc.version))
+		if p != tc.expected {
+			t.Errorf("Expected %q with %q to become %q, got %q", tc.path, tc.version, tc.expected, p)
+		}
+		if err := CheckPathMatchesVersion(p, MustParse(tc.version)); err != nil {
+			t.Errorf("Expected %q to match %q: %s", p, tc.version, err)
+		}
+	}
+}
+
+func TestNewGoModVersion(t *testing.T) {
+	tests := []struct {
+		version string
+		err     bool
+	}{
+		{"v1.2.3", false},
+		{"v1.2.3-rc.1", false},
+		{"v2.0.0+incompatible", false},
+		{"v0.0.0-20210101000000-abcdef123456", false},
+		{"1.2.3", true},
+		{"v1.2", true},
+		{"v1.2.3+build", true},
+		{"v1.0.0+incompatible", true},
+		{"V1.2.3", true},
+	}
+
+	for _, tc := range tests {
+		v, err := NewGoModVersion(tc.version)
+		if tc.err {
+			if err == nil {
+				t.Errorf("Expected error parsing %q", tc.version)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Error parsing %q: %s", tc.version, err)
+			continue
+		}
+		if v.Original() != tc.version {
+			t.Errorf("Expected original %q, got %q", tc.version, v.Original())
+		}
+	}
+}
+
+func TestPseudoVersion(t *testing.T) {
+	date := time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC)
+	rev := "abcdef1234567890abcdef1234567890abcdef12"
+
+	tests := []struct {
+		major    uint64
+		base     string
+		expected string
+	}{
+		{0, "", "v0.0.0-20210102030405-abcdef123456"},
+		{2, "", "v2.0.0-20210102030405-abcdef123456"},
+		{0, "v1.2.3", "v1.2.4-0.20210102030405-abcdef123456"},
+		{0, "v1.2.3+build", "v1.2.4-0.20210102030405-abcdef123456"},
+		{0, "v1.3.0-rc.1", "v1.3.0-rc.1.0.20210102030405-abcdef123456"},
+		{0, "v3.1.0+incompatible", "v3.1.1-0.20210102030405-abcdef123456+incompatible"},
+	}
+
+	for _, tc := range tests {
+		var base *Version
+		if tc.base != "" {
+			base = MustParse(tc.base)
+		}
+
+		v, err := NewPseudoVersion(tc.major, base, date.In(time.FixedZone("X", 3600)), rev)
+		if err != nil {
+			t.Errorf("Error creating pseudo-version from %q: %s", tc.base, err)
+			continue
+		}
+		if v.Original() != tc.expected {
+			t.Errorf("Expected %q, got %q", tc.expected, v.Original())
+		}
+
+		if !IsPseudoVersion(v) {
+			t.Errorf("Expected %s to be a pseudo-version", v)
+		}
+		if d, err := PseudoVersionTime(v); err != nil || !d.Equal(date) {
+			t.Errorf("Expected %s to have time %s, got %s %v", v, date, d, err)
+		}
+		if r, err := PseudoVersionRev(v); err != nil || r != rev[:12] {
+			t.Errorf("Expected %s to have revision %s, got %s %v", v, rev[:12], r, err)
+		}
+
+		b, err := PseudoVersionBase(v)
+		if err != nil {
+			t.Errorf("Error getting base of %s: %s", v, err)
+			continue
+		}
+		if base == nil {
+			if b != nil {
+				t.Errorf("Expected %s to have no base, got %s", v, b)
+			}
+			continue
+		}
+		if b == nil || b.Compare(base) != 0 {
+			t.Errorf("Expected %s to have base %s, got %v", v, base, b)
+			continue
+		}
+
+		// A pseudo-version sorts after its base and before the next
+		// release.
+		if !v.GreaterThan(base) {
+			t.Errorf("Expected %s to be greater than %s", v, base)
+		}
+		next := base.IncPatch()
+		if base.Prerelease() == "" && !v.LessThan(&next) {
+			t.Errorf("Expected %s to be less than %s", v, &next)
+		}
+	}
+}
+
+func TestIsPseudoVersion(t *testing.T) {
+	tests := []struct {
+		version  string
+		expected bool
+	}{
+		{"v0.0.0-20210101000000-abcdef123456", true},
+		{"v1.2.4-0.20210101000000-abcdef123456", true},
+		{"v1.2.3-pre.0.20210101000000-abcdef123456+incompatible", true},
+		{"v1.2.3", false},
+		{"v1.2.3-rc.1", false},
+		{"v1.2.4-20210101000000-abcdef123456", false},
+		{"v0.0.0-2021010100000-abcdef123456", false},
+	}
+
+	for _, tc := range tests {
+		v := MustParse(tc.version)
+		if a := IsPseudoVersion(v); a != tc.expected {
+			t.Errorf("Expected IsPseudoVersion(%q) to be %t", tc.version, tc.expected)
+		}
+	}
+
+	if _, err := PseudoVersionBase(MustParse("v1.2.3")); err != ErrNotPseudoVersion {
+		t.Errorf("Expected ErrNotPseudoVersion, got %v", err)
+	}
+}
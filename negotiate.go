@@ -0,0 +1,103 @@
+package semver
+
+import (
+	"errors"
+	"mime"
+	"regexp"
+	"strings"
+)
+
+var (
+	// ErrNoVersionToken is returned when a media type does not carry a
+	// version.
+	ErrNoVersionToken = errors.New("No version in media type")
+
+	// ErrNoAcceptableVersion is returned when none of the requested versions
+	// are supported.
+	ErrNoAcceptableVersion = errors.New("No acceptable version")
+)
+
+// mediaTypeVersionRegex matches a version at the end of a media subtype, such
+// as the v2 in vnd.foo.v2.
+var mediaTypeVersionRegex = regexp.MustCompile(`\.v([0-9]+(\.[0-9]+){0,2})$`)
+
+// ParseVersionHeader parses the value of a header carrying an API version,
+// such as X-API-Version: 1.4. Missing minor and patch numbers are treated as
+// 0 and a leading v is allowed.
+func ParseVersionHeader(h string) (*Version, error) {
+	return NewVersion(strings.TrimSpace(h))
+}
+
+// ParseMediaTypeVersion parses the version carried by a media type. The
+// version can be either a version parameter, as in application/json;
+// version=1.4, or a suffix of the subtype, as in application/vnd.foo.v2+json.
+// ErrNoVersionToken is returned when the media type has neither.
+func ParseMediaTypeVersion(mt string) (*Version, error) {
+	t, params, err := mime.ParseMediaType(mt)
+	if err != nil {
+		return nil, err
+	}
+
+	if p, ok := params["version"]; ok {
+		return NewVersion(p)
+	}
+
+	if i := strings.LastIndex(t, "+"); i != -1 {
+		t = t[:i]
+	}
+
+	m := mediaTypeVersionRegex.FindStringSubmatch(t)
+	if m == nil {
+		return nil, ErrNoVersionToken
+	}
+
+	return NewVersion(m[1])
+}
+
+// ParseAcceptVersions returns the versions carried by the media types in the
+// value of an Accept header, in the order they appear. Media types without a
+// version, such as */*, are skipped. Quality values are not taken into
+// account.
+func ParseAcceptVersions(accept string) ([]*Version, error) {
+	var vs []*Version
+
+	for _, mt := range strings.Split(accept, ",") {
+		mt = strings.TrimSpace(mt)
+		if mt == "" {
+			continue
+		}
+
+		v, err := ParseMediaTypeVersion(mt)
+		if err == ErrNoVersionToken {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		vs = append(vs, v)
+	}
+
+	return vs, nil
+}
+
+// NegotiateVersion selects the highest of the versions requested by a client
+// that the server supports. ErrNoAcceptableVersion is returned when none of
+// them are supported.
+func NegotiateVersion(requested []*Version, supported *Constraints) (*Version, error) {
+	var best *Version
+
+	for _, v := range requested {
+		if !supported.Check(v) {
+			continue
+		}
+		if best == nil || v.GreaterThan(best) {
+			best = v
+		}
+	}
+
+	if best == nil {
+		return nil, ErrNoAcceptableVersion
+	}
+
+	return best, nil
+}
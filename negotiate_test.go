@@ -0,0 +1,134 @@
+package semver
+
+import (
+	"testing"
+)
+
+func TestParseVersionHeader(t *testing.T) {
+	tests := []struct {
+		header   string
+		expected string
+		err      bool
+	}{
+		{"1.4", "1.4.0", false},
+		{" v2 ", "2.0.0", false},
+		{"1.2.3-beta", "1.2.3-beta", false},
+		{"", "", true},
+		{"latest", "", true},
+	}
+
+	for _, tc := range tests {
+		v, err := ParseVersionHeader(tc.header)
+		if tc.err {
+			if err == nil {
+				t.Errorf("Expected error for header %q", tc.header)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Unexpected error for header %q: %s", tc.header, err)
+			continue
+		}
+		if v.String() != tc.expected {
+			t.Errorf("Expected header %q to parse as %q, got %q", tc.header, tc.expected, v)
+		}
+	}
+}
+
+func TestParseMediaTypeVersion(t *testing.T) {
+	tests := []struct {
+		mediaType string
+		expected  string
+		err       error
+	}{
+		{"application/vnd.foo.v2+json", "2.0.0", nil},
+		{"application/vnd.foo.v2.1+json", "2.1.0", nil},
+		{"application/vnd.foo.v1.2.3", "1.2.3", nil},
+		{"application/json; version=1.4", "1.4.0", nil},
+		{"application/vnd.foo.v2+json; version=3", "3.0.0", nil},
+		{"application/vnd.foo+json", "", ErrNoVersionToken},
+		{"application/vnd.v2foo+json", "", ErrNoVersionToken},
+		{"*/*", "", ErrNoVersionToken},
+	}
+
+	for _, tc := range tests {
+		v, err := ParseMediaTypeVersion(tc.mediaType)
+		if err != tc.err {
+			t.Errorf("Expected error %v for %q, got %v", tc.err, tc.mediaType, err)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		if v.String() != tc.expected {
+			t.Errorf("Expected %q to carry version %q, got %q", tc.mediaType, tc.expected, v)
+		}
+	}
+
+	if _, err := ParseMediaTypeVersion("application/json; version="); err == nil {
+		t.Error("Expected error for an empty version parameter")
+	}
+	if _, err := ParseMediaTypeVersion("/"); err == nil {
+		t.Error("Expected error for an invalid media type")
+	}
+}
+
+func TestParseAcceptVersions(t *testing.T) {
+	vs, err := ParseAcceptVersions("application/vnd.foo.v2+json, application/vnd.foo.v1+json;q=0.5, */*")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	expected := []string{"2.0.0", "1.0.0"}
+	if len(vs) != len(expected) {
+		t.Fatalf("Expected %d versions, got %d", len(expected), len(vs))
+	}
+	for i, e := range expected {
+		if vs[i].String() != e {
+			t.Errorf("Expected version %d to be %q, got %q", i, e, vs[i])
+		}
+	}
+
+	if _, err := ParseAcceptVersions("application/json; version=foo"); err == nil {
+		t.Error("Expected error for an invalid version")
+	}
+}
+
+func TestNegotiateVersion(t *testing.T) {
+	tests := []struct {
+		requested []string
+		supported string
+		expected  string
+		err       error
+	}{
+		{[]string{"1.0.0", "2.0.0", "3.0.0"}, ">=1.2, <3", "2.0.0", nil},
+		{[]string{"1.4.0", "1.2.0"}, "^1.2", "1.4.0", nil},
+		{[]string{"3.0.0"}, "^1.2 || ^2", "", ErrNoAcceptableVersion},
+		{nil, "*", "", ErrNoAcceptableVersion},
+	}
+
+	for _, tc := range tests {
+		c, err := NewConstraint(tc.supported)
+		if err != nil {
+			t.Errorf("Error parsing constraint %q: %s", tc.supported, err)
+			continue
+		}
+
+		var vs []*Version
+		for _, s := range tc.requested {
+			vs = append(vs, MustParse(s))
+		}
+
+		v, err := NegotiateVersion(vs, c)
+		if err != tc.err {
+			t.Errorf("Expected error %v negotiating %v against %q, got %v", tc.err, tc.requested, tc.supported, err)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		if v.String() != tc.expected {
+			t.Errorf("Expected %v against %q to negotiate %q, got %q", tc.requested, tc.supported, tc.expected, v)
+		}
+	}
+}
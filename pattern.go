@@ -0,0 +1,163 @@
+package semver
+
+import (
+	"errors"
+	"strings"
+)
+
+var (
+	// ErrInvalidPattern is returned when a pattern cannot be parsed.
+	ErrInvalidPattern = errors.New("Invalid version pattern")
+
+	// ErrPatternNotConstraint is returned when a pattern matches a set of
+	// versions that cannot be expressed as a constraint.
+	ErrPatternNotConstraint = errors.New("Version pattern cannot be converted to a constraint")
+)
+
+// Pattern is a version where any of the parts may be a wildcard. It matches
+// versions the way tag matching rules in CI configurations typically do. For
+// example, 1.2.x matches 1.2.0 and 1.2.7 while 1.*.*-rc.* matches 1.4.0-rc.2.
+//
+// The x, X, and * characters can be used as a wildcard for the major, minor,
+// and patch numbers and for any prerelease identifier. A wildcard identifier
+// matches exactly one identifier. Missing minor and patch numbers are treated
+// as wildcards. A pattern without a prerelease only matches versions without
+// one. Build metadata is ignored when matching.
+type Pattern struct {
+	// major, minor, and patch are empty when they are a wildcard.
+	major, minor, patch string
+	pre                 []string
+	original            string
+}
+
+// NewPattern parses a version pattern such as 1.2.x or 1.*.*-rc.*.
+func NewPattern(p string) (*Pattern, error) {
+	s := strings.TrimPrefix(p, "v")
+	if s == "" {
+		return nil, ErrInvalidPattern
+	}
+
+	pt := &Pattern{original: p}
+
+	if strings.Contains(s, "+") {
+		return nil, ErrInvalidPattern
+	}
+
+	if i := strings.Index(s, "-"); i != -1 {
+		pre := s[i+1:]
+		s = s[:i]
+		if pre == "" {
+			return nil, ErrInvalidPattern
+		}
+		pt.pre = strings.Split(pre, ".")
+		for _, id := range pt.pre {
+			if isX(id) {
+				continue
+			}
+			if id == "" || !containsOnly(id, allowed) {
+				return nil, ErrInvalidPattern
+			}
+			if containsOnly(id, num) && len(id) > 1 && id[0] == '0' {
+				return nil, ErrInvalidPattern
+			}
+		}
+	}
+
+	parts := strings.Split(s, ".")
+	if len(parts) > 3 {
+		return nil, ErrInvalidPattern
+	}
+	for i, part := range parts {
+		if isX(part) {
+			continue
+		}
+		if part == "" || !containsOnly(part, num) || len(part) > 1 && part[0] == '0' {
+			return nil, ErrInvalidPattern
+		}
+		if _, err := parseSegment(part); err != nil {
+			return nil, err
+		}
+		switch i {
+		case 0:
+			pt.major = part
+		case 1:
+			pt.minor = part
+		case 2:
+			pt.patch = part
+		}
+	}
+
+	return pt, nil
+}
+
+// Match reports whether the version matches the pattern.
+func (p *Pattern) Match(v *Version) bool {
+	if !matchSegment(p.major, v.Major()) ||
+		!matchSegment(p.minor, v.Minor()) ||
+		!matchSegment(p.patch, v.Patch()) {
+		return false
+	}
+
+	if len(p.pre) == 0 {
+		return v.Prerelease() == ""
+	}
+
+	if v.Prerelease() == "" {
+		return false
+	}
+
+	ids := strings.Split(v.Prerelease(), ".")
+	if len(ids) != len(p.pre) {
+		return false
+	}
+	for i, id := range p.pre {
+		if !isX(id) && comparePrePart(id, ids[i]) != 0 {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Constraint converts the pattern into a constraint admitting the same
+// versions. Only patterns without a prerelease whose wildcards all come after
+// the numbers, such as 1.2.x, can be converted. ErrPatternNotConstraint is
+// returned for the others.
+func (p *Pattern) Constraint() (*Constraints, error) {
+	if len(p.pre) != 0 {
+		return nil, ErrPatternNotConstraint
+	}
+
+	switch {
+	case p.major == "":
+		if p.minor != "" || p.patch != "" {
+			return nil, ErrPatternNotConstraint
+		}
+		return NewConstraint("*")
+	case p.minor == "":
+		if p.patch != "" {
+			return nil, ErrPatternNotConstraint
+		}
+		return NewConstraint(p.major + ".x")
+	case p.patch == "":
+		return NewConstraint(p.major + "." + p.minor + ".x")
+	}
+
+	return NewConstraint("=" + p.major + "." + p.minor + "." + p.patch)
+}
+
+// String returns the pattern as it was parsed.
+func (p *Pattern) String() string {
+	return p.original
+}
+
+// matchSegment compares a pattern segment, where empty is a wildcard, to the
+// segment of a version.
+func matchSegment(p string, v uint64) bool {
+	if p == "" {
+		return true
+	}
+
+	n, _ := parseSegment(p)
+	return n == v
+}
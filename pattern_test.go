@@ -0,0 +1,142 @@
+package semver
+
+import (
+	"testing"
+)
+
+func TestNewPattern(t *testing.T) {
+	tests := []struct {
+		pattern string
+		err     bool
+	}{
+		{"1.2.3", false},
+		{"1.2.x", false},
+		{"1.2", false},
+		{"v1.x", false},
+		{"*", false},
+		{"1.*.*-rc.*", false},
+		{"1.2.3-beta.X", false},
+		{"x.2.3", false},
+		{"", true},
+		{"v", true},
+		{"1.2.3.4", true},
+		{"1..3", true},
+		{"1.2.y", true},
+		{"1.2.3-", true},
+		{"1.2.3-rc..1", true},
+		{"1.2.3-rc.01", true},
+		{"01.2.x", true},
+		{"1.02", true},
+		{"1.2.00", true},
+		{"0.0.x", false},
+		{"1.2.3+build", true},
+		{"18446744073709551616.x", true},
+	}
+
+	for _, tc := range tests {
+		_, err := NewPattern(tc.pattern)
+		if tc.err && err == nil {
+			t.Errorf("Expected error for pattern %q", tc.pattern)
+		} else if !tc.err && err != nil {
+			t.Errorf("Unexpected error for pattern %q: %s", tc.pattern, err)
+		}
+	}
+}
+
+func TestPatternMatch(t *testing.T) {
+	tests := []struct {
+		pattern string
+		version string
+		match   bool
+	}{
+		{"1.2.x", "1.2.0", true},
+		{"1.2.x", "1.2.7", true},
+		{"1.2.x", "1.3.0", false},
+		{"1.2.x", "1.2.7-beta", false},
+		{"1.2.x", "1.2.7+build", true},
+		{"1.2", "1.2.7", true},
+		{"1", "1.9.0", true},
+		{"*", "4.5.6", true},
+		{"*", "4.5.6-alpha", false},
+		{"1.2.3", "1.2.3", true},
+		{"1.2.3", "1.2.4", false},
+		{"x.2.3", "7.2.3", true},
+		{"x.2.3", "7.2.4", false},
+		{"1.*.*-rc.*", "1.4.0-rc.2", true},
+		{"1.*.*-rc.*", "1.4.0-rc", false},
+		{"1.*.*-rc.*", "1.4.0-rc.2.1", false},
+		{"1.*.*-rc.*", "1.4.0-beta.2", false},
+		{"1.*.*-rc.*", "1.4.0", false},
+		{"1.*.*-rc.*", "2.4.0-rc.1", false},
+		{"1.2.3-*", "1.2.3-alpha", true},
+		{"1.2.3-beta.2", "1.2.3-beta.2", true},
+		{"1.2.3-beta.2", "1.2.3-beta.3", false},
+		{"v1.x", "1.0.1", true},
+	}
+
+	for _, tc := range tests {
+		p, err := NewPattern(tc.pattern)
+		if err != nil {
+			t.Errorf("Error parsing pattern %q: %s", tc.pattern, err)
+			continue
+		}
+
+		v, err := NewVersion(tc.version)
+		if err != nil {
+			t.Errorf("Error parsing version %q: %s", tc.version, err)
+			continue
+		}
+
+		if a := p.Match(v); a != tc.match {
+			t.Errorf("Expected pattern %q to match %q: %t, got %t", tc.pattern, tc.version, tc.match, a)
+		}
+	}
+}
+
+func TestPatternConstraint(t *testing.T) {
+	tests := []struct {
+		pattern    string
+		constraint string
+		err        error
+	}{
+		{"1.2.x", "1.2.x", nil},
+		{"1.2", "1.2.x", nil},
+		{"1.x.x", "1.x", nil},
+		{"*", "*", nil},
+		{"1.2.3", "=1.2.3", nil},
+		{"x.2.3", "", ErrPatternNotConstraint},
+		{"1.x.3", "", ErrPatternNotConstraint},
+		{"1.2.x-rc.*", "", ErrPatternNotConstraint},
+	}
+
+	versions := []string{"0.9.0", "1.0.0", "1.2.0", "1.2.3", "1.2.9", "1.3.0", "2.0.0", "1.2.3-rc.1"}
+
+	for _, tc := range tests {
+		p, err := NewPattern(tc.pattern)
+		if err != nil {
+			t.Errorf("Error parsing pattern %q: %s", tc.pattern, err)
+			continue
+		}
+
+		c, err := p.Constraint()
+		if err != tc.err {
+			t.Errorf("Expected error %v converting pattern %q, got %v", tc.err, tc.pattern, err)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+
+		if c.String() != tc.constraint {
+			t.Errorf("Expected pattern %q to convert to %q, got %q", tc.pattern, tc.constraint, c.String())
+		}
+
+		// The constraint must admit the same versions the pattern matches.
+		for _, s := range versions {
+			v := MustParse(s)
+			if p.Match(v) != c.Check(v) {
+				t.Errorf("Pattern %q and constraint %q disagree on %q", tc.pattern, c, s)
+			}
+		}
+	}
+}
@@ -0,0 +1,182 @@
+package semver
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Pin is an entry in a PinFile.
+type Pin struct {
+	// Name is the name of the pinned dependency.
+	Name string
+
+	// Value is the pinned version or constraint as written.
+	Value string
+
+	// Version is the pinned version when Value is an exact version, such as
+	// 1.2.3, and nil when it is a range, such as ^1.2.
+	Version *Version
+
+	// Constraint is the constraint Value stands for. For an exact version it
+	// only admits that version.
+	Constraint *Constraints
+}
+
+// PinFile is a lightweight lockfile mapping dependency names to an exact
+// version or a range. The text format has one pin per line,
+//
+//	# Comments start with a #.
+//	foo = "1.2.3"
+//	bar = "^2.1"
+//
+// in the style of a TOML table of strings. The quotes are optional when
+// reading. Writing sorts the pins by name so the output is stable.
+type PinFile struct {
+	pins map[string]*Pin
+}
+
+// NewPinFile returns an empty PinFile.
+func NewPinFile() *PinFile {
+	return &PinFile{pins: make(map[string]*Pin)}
+}
+
+// ParsePinFile reads a PinFile from r. Errors include the line number. A name
+// appearing twice is an error.
+func ParsePinFile(r io.Reader) (*PinFile, error) {
+	p := NewPinFile()
+
+	s := bufio.NewScanner(r)
+	for n := 1; s.Scan(); n++ {
+		line := strings.TrimSpace(s.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		i := strings.IndexByte(line, '=')
+		if i == -1 {
+			return nil, fmt.Errorf("line %d: expected name = version", n)
+		}
+
+		name := strings.TrimSpace(line[:i])
+		if _, ok := p.pins[name]; ok {
+			return nil, fmt.Errorf("line %d: %s is pinned more than once", n, name)
+		}
+
+		value := strings.TrimSpace(line[i+1:])
+		if strings.HasPrefix(value, `"`) {
+			uq, err := strconv.Unquote(value)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: invalid quoted value %s", n, value)
+			}
+			value = uq
+		}
+
+		if err := p.Set(name, value); err != nil {
+			return nil, fmt.Errorf("line %d: %s", n, err)
+		}
+	}
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+
+	return p, nil
+}
+
+// Set pins the dependency to an exact version or a constraint, replacing any
+// existing pin.
+func (p *PinFile) Set(name, value string) error {
+	if name == "" || strings.ContainsAny(name, " \t=#\"") {
+		return fmt.Errorf("invalid pin name: %q", name)
+	}
+
+	pin := &Pin{Name: name, Value: value}
+
+	// Only complete versions are exact. 1.2 is taken as the range 1.2.x.
+	if _, err := StrictNewVersion(strings.TrimPrefix(value, "v")); err == nil {
+		v, err := NewVersion(value)
+		if err != nil {
+			return err
+		}
+		pin.Version = v
+
+		c, err := NewConstraint("=" + v.String())
+		if err != nil {
+			return err
+		}
+		pin.Constraint = c
+	} else {
+		c, err := NewConstraint(value)
+		if err != nil {
+			return err
+		}
+		pin.Constraint = c
+	}
+
+	p.pins[name] = pin
+	return nil
+}
+
+// Get returns the pin for the dependency.
+func (p *PinFile) Get(name string) (*Pin, bool) {
+	pin, ok := p.pins[name]
+	return pin, ok
+}
+
+// Delete removes the pin for the dependency.
+func (p *PinFile) Delete(name string) {
+	delete(p.pins, name)
+}
+
+// Names returns the names of the pinned dependencies in sorted order.
+func (p *PinFile) Names() []string {
+	names := make([]string, 0, len(p.pins))
+	for name := range p.pins {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Validate checks the pins against the constraints each dependency must
+// satisfy. It reports dependencies that are not pinned and exact pins that do
+// not satisfy their constraint. Range pins are not checked as whether they
+// are compatible depends on the version eventually chosen.
+func (p *PinFile) Validate(reqs map[string]*Constraints) []error {
+	names := make([]string, 0, len(reqs))
+	for name := range reqs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var errs []error
+	for _, name := range names {
+		pin, ok := p.pins[name]
+		if !ok {
+			errs = append(errs, fmt.Errorf("%s is not pinned", name))
+			continue
+		}
+		if pin.Version != nil && !reqs[name].Check(pin.Version) {
+			errs = append(errs, fmt.Errorf("%s is pinned to %s which does not satisfy %s", name, pin.Value, reqs[name]))
+		}
+	}
+
+	return errs
+}
+
+// WriteTo writes the pins to w, sorted by name, in the format read by
+// ParsePinFile. It implements the io.WriterTo interface.
+func (p *PinFile) WriteTo(w io.Writer) (int64, error) {
+	var total int64
+	for _, name := range p.Names() {
+		n, err := fmt.Fprintf(w, "%s = %s\n", name, strconv.Quote(p.pins[name].Value))
+		total += int64(n)
+		if err != nil {
+			return total, err
+		}
+	}
+	return total, nil
+}
@@ -0,0 +1,132 @@
+package semver
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestParsePinFile(t *testing.T) {
+	input := `
+# Pinned dependencies.
+foo = "1.2.3"
+bar = ^2.1
+baz="v3.0.0-rc.1"
+qux = 1.2
+`
+
+	p, err := ParsePinFile(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	tests := []struct {
+		name    string
+		value   string
+		version string
+	}{
+		{"foo", "1.2.3", "1.2.3"},
+		{"bar", "^2.1", ""},
+		{"baz", "v3.0.0-rc.1", "3.0.0-rc.1"},
+		{"qux", "1.2", ""},
+	}
+
+	for _, tc := range tests {
+		pin, ok := p.Get(tc.name)
+		if !ok {
+			t.Errorf("Expected %s to be pinned", tc.name)
+			continue
+		}
+		if pin.Name != tc.name || pin.Value != tc.value {
+			t.Errorf("Expected %s = %q, got %s = %q", tc.name, tc.value, pin.Name, pin.Value)
+		}
+		if tc.version == "" {
+			if pin.Version != nil {
+				t.Errorf("Expected %s to be a range, got version %s", tc.name, pin.Version)
+			}
+		} else if pin.Version == nil || pin.Version.String() != tc.version {
+			t.Errorf("Expected %s to be pinned to %s, got %v", tc.name, tc.version, pin.Version)
+		}
+	}
+
+	if !p.pins["qux"].Constraint.Check(MustParse("1.2.9")) {
+		t.Error("Expected qux to admit 1.2.9")
+	}
+	if p.pins["foo"].Constraint.Check(MustParse("1.2.4")) {
+		t.Error("Expected foo to only admit 1.2.3")
+	}
+
+	invalid := []string{
+		"foo",
+		"foo = ",
+		"foo = \"1.2.3",
+		"foo = >=bar",
+		" = 1.2.3",
+		"foo bar = 1.2.3",
+		"foo = 1.2.3\nfoo = 1.2.4",
+	}
+	for _, s := range invalid {
+		if _, err := ParsePinFile(strings.NewReader(s)); err == nil {
+			t.Errorf("Expected error parsing %q", s)
+		}
+	}
+}
+
+func TestPinFileWriteTo(t *testing.T) {
+	p := NewPinFile()
+	for _, kv := range [][2]string{{"zeta", "~1.2"}, {"alpha", "2.0.0"}, {"mid", ">=1, <2"}} {
+		if err := p.Set(kv[0], kv[1]); err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+	}
+
+	var buf bytes.Buffer
+	n, err := p.WriteTo(&buf)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	expected := "alpha = \"2.0.0\"\nmid = \">=1, <2\"\nzeta = \"~1.2\"\n"
+	if buf.String() != expected {
+		t.Errorf("Expected %q, got %q", expected, buf.String())
+	}
+	if n != int64(len(expected)) {
+		t.Errorf("Expected %d bytes written, got %d", len(expected), n)
+	}
+
+	r, err := ParsePinFile(&buf)
+	if err != nil {
+		t.Fatalf("Unexpected error reading back: %s", err)
+	}
+	if names := r.Names(); len(names) != 3 || names[0] != "alpha" || names[2] != "zeta" {
+		t.Errorf("Expected the pins to round trip, got %v", names)
+	}
+
+	p.Delete("mid")
+	if _, ok := p.Get("mid"); ok {
+		t.Error("Expected mid to be deleted")
+	}
+}
+
+func TestPinFileValidate(t *testing.T) {
+	p := NewPinFile()
+	_ = p.Set("foo", "1.2.3")
+	_ = p.Set("bar", "3.0.0")
+	_ = p.Set("baz", "^5")
+
+	reqs := map[string]*Constraints{}
+	for name, c := range map[string]string{"foo": "^1.2", "bar": "^2", "baz": "^4", "qux": "*"} {
+		reqs[name], _ = NewConstraint(c)
+	}
+
+	errs := p.Validate(reqs)
+	if len(errs) != 2 {
+		t.Fatalf("Expected 2 errors, got %v", errs)
+	}
+	if errs[0].Error() != "bar is pinned to 3.0.0 which does not satisfy ^2" {
+		t.Errorf("Unexpected error: %s", errs[0])
+	}
+	if errs[1].Error() != "qux is not pinned" {
+		t.Errorf("Unexpected error: %s", errs[1])
+	}
+}
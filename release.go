@@ -0,0 +1,176 @@
+package semver
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// ReleaseSpan summarizes the release dates of the versions a constraint
+// admits. It is returned by Constraints.ReleaseSpan.
+type ReleaseSpan struct {
+	// Admitted is the number of versions the constraint admits.
+	Admitted int
+
+	// Oldest and Newest are the lowest and highest admitted versions by
+	// precedence. They are nil when no versions are admitted.
+	Oldest, Newest *Version
+
+	// Earliest and Latest are the first and last release dates of the
+	// admitted versions.
+	Earliest, Latest time.Time
+
+	// Age is how long ago Newest was released.
+	Age time.Duration
+
+	// OutdatedFor is how long a release higher than Newest, which the
+	// constraint does not admit, has been available. Prereleases are not
+	// considered. It is 0 when there is no higher release.
+	OutdatedFor time.Duration
+}
+
+// ReleaseSpan reports the span of release dates of the versions in dates that
+// the constraints admit and how stale the newest admitted version is as of
+// now. The keys of dates are version strings, as accepted by NewVersion, and
+// the values their release dates. An error is returned if a key cannot be
+// parsed.
+func (cs Constraints) ReleaseSpan(dates map[string]time.Time, now time.Time) (*ReleaseSpan, error) {
+	type release struct {
+		v    *Version
+		date time.Time
+	}
+
+	releases := make([]release, 0, len(dates))
+	for s, d := range dates {
+		v, err := NewVersion(s)
+		if err != nil {
+			return nil, fmt.Errorf("Error parsing version %q: %s", s, err)
+		}
+		releases = append(releases, release{v: v, date: d})
+	}
+
+	sort.Slice(releases, func(i, j int) bool {
+		return releases[i].v.CompareTotal(releases[j].v) < 0
+	})
+
+	span := &ReleaseSpan{}
+	var newest time.Time
+	for _, r := range releases {
+		if !cs.Check(r.v) {
+			continue
+		}
+
+		span.Admitted++
+		if span.Oldest == nil {
+			span.Oldest = r.v
+		}
+		span.Newest = r.v
+		newest = r.date
+
+		if span.Earliest.IsZero() || r.date.Before(span.Earliest) {
+			span.Earliest = r.date
+		}
+		if r.date.After(span.Latest) {
+			span.Latest = r.date
+		}
+	}
+
+	if span.Newest == nil {
+		return span, nil
+	}
+
+	span.Age = now.Sub(newest)
+
+	// The first release of a higher version is when the newest admitted
+	// version became outdated.
+	var outdated time.Time
+	for _, r := range releases {
+		if r.v.Prerelease() != "" || !r.v.GreaterThan(span.Newest) {
+			continue
+		}
+		if outdated.IsZero() || r.date.Before(outdated) {
+			outdated = r.date
+		}
+	}
+	if !outdated.IsZero() {
+		span.OutdatedFor = now.Sub(outdated)
+	}
+
+	return span, nil
+}
+
+// StreamCadence summarizes how often a release stream, the releases sharing a
+// major and minor version, has been released. It is returned by Cadence.
+type StreamCadence struct {
+	// Major and Minor identify the stream.
+	Major, Minor uint64
+
+	// Releases is the number of releases in the stream with a known date.
+	Releases int
+
+	// Latest is the highest release in the stream by precedence.
+	Latest *Version
+
+	// MeanPatchInterval is the mean time between consecutive releases in
+	// the stream. It is 0 when the stream has a single release.
+	MeanPatchInterval time.Duration
+
+	// LastRelease is the date of the most recent release in the stream and
+	// Age is how long ago that was.
+	LastRelease time.Time
+	Age         time.Duration
+}
+
+// Cadence computes release timing statistics for each release stream in the
+// collection as of now. The dates are keyed by version string, looked up
+// using the original string first and then the normalized one. Prereleases
+// and versions without a date are skipped. The streams are returned sorted
+// by version.
+func Cadence(c Collection, dates map[string]time.Time, now time.Time) []*StreamCadence {
+	sorted := make(Collection, len(c))
+	copy(sorted, c)
+	sort.Sort(sorted)
+
+	var streams []*StreamCadence
+	var first time.Time
+	for _, v := range sorted {
+		if v.Prerelease() != "" {
+			continue
+		}
+
+		d, ok := dates[v.Original()]
+		if !ok {
+			d, ok = dates[v.String()]
+		}
+		if !ok {
+			continue
+		}
+
+		var s *StreamCadence
+		if n := len(streams); n > 0 && streams[n-1].Major == v.Major() && streams[n-1].Minor == v.Minor() {
+			s = streams[n-1]
+		} else {
+			s = &StreamCadence{Major: v.Major(), Minor: v.Minor()}
+			streams = append(streams, s)
+			first = d
+		}
+
+		s.Releases++
+		s.Latest = v
+		if d.Before(first) {
+			first = d
+		}
+		if d.After(s.LastRelease) {
+			s.LastRelease = d
+		}
+
+		// The mean of the intervals between consecutive releases is the
+		// span of their dates divided by the number of intervals.
+		if s.Releases > 1 {
+			s.MeanPatchInterval = s.LastRelease.Sub(first) / time.Duration(s.Releases-1)
+		}
+		s.Age = now.Sub(s.LastRelease)
+	}
+
+	return streams
+}
@@ -0,0 +1,129 @@
+package semver
+
+import (
+	"testing"
+	"time"
+)
+
+func TestConstraintsReleaseSpan(t *testing.T) {
+	day := func(d int) time.Time {
+		return time.Date(2020, time.January, d, 0, 0, 0, 0, time.UTC)
+	}
+	dates := map[string]time.Time{
+		"1.0.0":        day(1),
+		"1.1.0":        day(5),
+		"1.2.0":        day(3),
+		"2.0.0-beta.1": day(8),
+		"2.0.0":        day(10),
+		"2.1.0":        day(20),
+	}
+	now := day(31)
+
+	tests := []struct {
+		constraint  string
+		admitted    int
+		oldest      string
+		newest      string
+		earliest    time.Time
+		latest      time.Time
+		age         time.Duration
+		outdatedFor time.Duration
+	}{
+		{"^1.0.0", 3, "1.0.0", "1.2.0", day(1), day(5), 28 * 24 * time.Hour, 21 * 24 * time.Hour},
+		{"^2.0.0", 2, "2.0.0", "2.1.0", day(10), day(20), 11 * 24 * time.Hour, 0},
+		{">=1.1.0-0, <=2.0.0-beta.1", 3, "1.1.0", "2.0.0-beta.1", day(3), day(8), 23 * 24 * time.Hour, 21 * 24 * time.Hour},
+		{"^3.0.0", 0, "", "", time.Time{}, time.Time{}, 0, 0},
+	}
+
+	for _, tc := range tests {
+		c, err := NewConstraint(tc.constraint)
+		if err != nil {
+			t.Errorf("Error parsing constraint %q: %s", tc.constraint, err)
+			continue
+		}
+
+		s, err := c.ReleaseSpan(dates, now)
+		if err != nil {
+			t.Errorf("Unexpected error for %q: %s", tc.constraint, err)
+			continue
+		}
+
+		if s.Admitted != tc.admitted {
+			t.Errorf("Expected %q to admit %d versions, got %d", tc.constraint, tc.admitted, s.Admitted)
+		}
+		if tc.admitted == 0 {
+			if s.Oldest != nil || s.Newest != nil {
+				t.Errorf("Expected no versions for %q", tc.constraint)
+			}
+			continue
+		}
+		if s.Oldest.String() != tc.oldest || s.Newest.String() != tc.newest {
+			t.Errorf("Expected %q to span %s to %s, got %s to %s", tc.constraint, tc.oldest, tc.newest, s.Oldest, s.Newest)
+		}
+		if !s.Earliest.Equal(tc.earliest) || !s.Latest.Equal(tc.latest) {
+			t.Errorf("Expected %q to be released from %s to %s, got %s to %s", tc.constraint, tc.earliest, tc.latest, s.Earliest, s.Latest)
+		}
+		if s.Age != tc.age {
+			t.Errorf("Expected %q to have age %s, got %s", tc.constraint, tc.age, s.Age)
+		}
+		if s.OutdatedFor != tc.outdatedFor {
+			t.Errorf("Expected %q to be outdated for %s, got %s", tc.constraint, tc.outdatedFor, s.OutdatedFor)
+		}
+	}
+
+	if _, err := (Constraints{}).ReleaseSpan(map[string]time.Time{"foo": now}, now); err == nil {
+		t.Error("Expected an error for an invalid version")
+	}
+}
+
+func TestCadence(t *testing.T) {
+	day := func(d int) time.Time {
+		return time.Date(2020, time.January, d, 0, 0, 0, 0, time.UTC)
+	}
+	dates := map[string]time.Time{
+		"1.0.0":      day(1),
+		"1.0.1":      day(4),
+		"v1.0.2":     day(10),
+		"1.1.0":      day(12),
+		"1.1.1-rc.1": day(13),
+		"2.0.0":      day(20),
+	}
+
+	var c Collection
+	for _, s := range []string{"2.0.0", "1.1.0", "1.0.2", "v1.0.2", "1.0.0", "1.0.1", "1.1.1-rc.1", "3.0.0"} {
+		c = append(c, MustParse(s))
+	}
+
+	streams := Cadence(c, dates, day(30))
+
+	expected := []StreamCadence{
+		{Major: 1, Minor: 0, Releases: 3, MeanPatchInterval: 108 * time.Hour, LastRelease: day(10), Age: 20 * 24 * time.Hour},
+		{Major: 1, Minor: 1, Releases: 1, LastRelease: day(12), Age: 18 * 24 * time.Hour},
+		{Major: 2, Minor: 0, Releases: 1, LastRelease: day(20), Age: 10 * 24 * time.Hour},
+	}
+	latest := []string{"1.0.2", "1.1.0", "2.0.0"}
+
+	if len(streams) != len(expected) {
+		t.Fatalf("Expected %d streams, got %d", len(expected), len(streams))
+	}
+
+	for i, e := range expected {
+		s := streams[i]
+		if s.Major != e.Major || s.Minor != e.Minor {
+			t.Errorf("Expected stream %d.%d, got %d.%d", e.Major, e.Minor, s.Major, s.Minor)
+			continue
+		}
+		if s.Releases != e.Releases {
+			t.Errorf("Expected stream %d.%d to have %d releases, got %d", e.Major, e.Minor, e.Releases, s.Releases)
+		}
+		if s.Latest.String() != latest[i] {
+			t.Errorf("Expected stream %d.%d to have latest %s, got %s", e.Major, e.Minor, latest[i], s.Latest)
+		}
+		if s.MeanPatchInterval != e.MeanPatchInterval {
+			t.Errorf("Expected stream %d.%d to have mean interval %s, got %s", e.Major, e.Minor, e.MeanPatchInterval, s.MeanPatchInterval)
+		}
+		if !s.LastRelease.Equal(e.LastRelease) || s.Age != e.Age {
+			t.Errorf("Expected stream %d.%d last released %s (%s ago), got %s (%s ago)", e.Major, e.Minor, e.LastRelease, e.Age, s.LastRelease, s.Age)
+		}
+	}
+}
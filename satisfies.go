@@ -0,0 +1,72 @@
+package semver
+
+import (
+	"strings"
+)
+
+// UnsatisfiedError is returned by Version.Satisfies and Version.SatisfiesAll
+// when the version does not satisfy some of the constraints it is checked
+// against.
+type UnsatisfiedError struct {
+	Version *Version
+
+	// Constraints are the constraints the version does not satisfy, in the
+	// order they were given.
+	Constraints []*Constraints
+
+	// Reasons holds the errors returned by Constraints.Validate for each of
+	// Constraints.
+	Reasons [][]error
+}
+
+func (e *UnsatisfiedError) Error() string {
+	var sb strings.Builder
+	for i, c := range e.Constraints {
+		if i > 0 {
+			sb.WriteString("; ")
+		}
+		sb.WriteString(e.Version.String())
+		sb.WriteString(" does not satisfy ")
+		sb.WriteString(c.String())
+		for j, r := range e.Reasons[i] {
+			if j == 0 {
+				sb.WriteString(": ")
+			} else {
+				sb.WriteString(", ")
+			}
+			sb.WriteString(r.Error())
+		}
+	}
+	return sb.String()
+}
+
+// Satisfies returns nil if the version satisfies c, and an *UnsatisfiedError
+// with the reasons it does not otherwise.
+func (v *Version) Satisfies(c *Constraints) error {
+	return v.SatisfiesAll(c)
+}
+
+// SatisfiesAll returns nil if the version satisfies every one of cs. If it
+// does not, the *UnsatisfiedError returned lists each of cs it does not
+// satisfy rather than only the first.
+func (v *Version) SatisfiesAll(cs ...*Constraints) error {
+	var e *UnsatisfiedError
+	for _, c := range cs {
+		// Validate can disagree with Check, such as on a != term with a
+		// prerelease, so it is only used for the reasons.
+		if c.Check(v) {
+			continue
+		}
+		_, reasons := c.Validate(v)
+		if e == nil {
+			e = &UnsatisfiedError{Version: v}
+		}
+		e.Constraints = append(e.Constraints, c)
+		e.Reasons = append(e.Reasons, reasons)
+	}
+
+	if e == nil {
+		return nil
+	}
+	return e
+}
@@ -0,0 +1,80 @@
+package semver
+
+import (
+	"testing"
+)
+
+func TestVersionSatisfies(t *testing.T) {
+	tests := []struct {
+		version    string
+		constraint string
+		err        string
+	}{
+		{"1.2.3", "^1.2", ""},
+		{"1.2.3", "^2.0", "1.2.3 does not satisfy ^2.0: 1.2.3 is less than 2.0"},
+		{"1.2.3-beta", "^1.2", "1.2.3-beta does not satisfy ^1.2: 1.2.3-beta is a prerelease version and the constraint is only looking for release versions"},
+		{"3.0.0", "~1.2 || ~2.0", "3.0.0 does not satisfy ~1.2 || ~2.0: 3.0.0 does not have same major version as 1.2, 3.0.0 does not have same major version as 2.0"},
+		// Validate rejects this prerelease although Check admits it.
+		{"0.0.0-alpha", "<=1.0.4-rc !=0.0.4 || 0.1.4", ""},
+	}
+
+	for _, tc := range tests {
+		err := MustParse(tc.version).Satisfies(mustNewConstraint(t, tc.constraint))
+		if tc.err == "" {
+			if err != nil {
+				t.Errorf("Expected %s to satisfy %q, got %q", tc.version, tc.constraint, err)
+			}
+			continue
+		}
+		if err == nil {
+			t.Errorf("Expected %s not to satisfy %q", tc.version, tc.constraint)
+			continue
+		}
+		if err.Error() != tc.err {
+			t.Errorf("Expected error %q, got %q", tc.err, err)
+		}
+	}
+}
+
+func TestVersionSatisfiesAll(t *testing.T) {
+	v := MustParse("1.4.0")
+	a := mustNewConstraint(t, ">=1.2")
+	b := mustNewConstraint(t, "!=1.4.0")
+	c := mustNewConstraint(t, "<1.3")
+
+	if err := v.SatisfiesAll(a); err != nil {
+		t.Errorf("Expected nil, got %q", err)
+	}
+	if err := v.SatisfiesAll(); err != nil {
+		t.Errorf("Expected nil with no constraints, got %q", err)
+	}
+
+	err := v.SatisfiesAll(a, b, c)
+	e, ok := err.(*UnsatisfiedError)
+	if !ok {
+		t.Fatalf("Expected *UnsatisfiedError, got %T", err)
+	}
+	if e.Version != v {
+		t.Errorf("Expected error for %s, got %s", v, e.Version)
+	}
+	if len(e.Constraints) != 2 || e.Constraints[0] != b || e.Constraints[1] != c {
+		t.Errorf("Expected unsatisfied constraints [%s %s], got %v", b, c, e.Constraints)
+	}
+	if len(e.Reasons) != 2 || len(e.Reasons[0]) != 1 || len(e.Reasons[1]) != 1 {
+		t.Errorf("Expected one reason for each constraint, got %v", e.Reasons)
+	}
+
+	expected := "1.4.0 does not satisfy !=1.4.0: 1.4.0 is equal to 1.4.0; 1.4.0 does not satisfy <1.3: 1.4.0 is greater than or equal to 1.3"
+	if err.Error() != expected {
+		t.Errorf("Expected error %q, got %q", expected, err)
+	}
+}
+
+func mustNewConstraint(t *testing.T, c string) *Constraints {
+	t.Helper()
+	cs, err := NewConstraint(c)
+	if err != nil {
+		t.Fatalf("Error parsing constraint %q: %s", c, err)
+	}
+	return cs
+}
@@ -0,0 +1,104 @@
+package semver
+
+// SelectOption configures MaxSatisfying and MinSatisfying.
+type SelectOption func(*selectOptions)
+
+type selectOptions struct {
+	prerelease    int // 0 follows the constraints, 1 includes all, -1 excludes all
+	metadataTie   bool
+	laterMetadata bool
+}
+
+// IncludePrereleases makes prereleases eligible by precedence alone, as if
+// the constraints had IncludePrerelease set.
+func IncludePrereleases() SelectOption {
+	return func(o *selectOptions) {
+		o.prerelease = 1
+	}
+}
+
+// ExcludePrereleases makes prereleases ineligible, even those the
+// constraints admit such as 1.2.0-rc.1 for >=1.2.0-0.
+func ExcludePrereleases() SelectOption {
+	return func(o *selectOptions) {
+		o.prerelease = -1
+	}
+}
+
+// MetadataTiebreak orders versions with equal precedence by CompareTotal, so
+// MaxSatisfying picks the one CompareTotal puts last and MinSatisfying the
+// one it puts first. By default the first one in the collection is picked.
+func MetadataTiebreak() SelectOption {
+	return func(o *selectOptions) {
+		o.metadataTie = true
+	}
+}
+
+// PreferLaterMetadata picks the version with the lexically later build
+// metadata when versions have equal precedence, for both MaxSatisfying and
+// MinSatisfying. It takes priority over MetadataTiebreak.
+func PreferLaterMetadata() SelectOption {
+	return func(o *selectOptions) {
+		o.laterMetadata = true
+	}
+}
+
+// MaxSatisfying returns the highest version in vs that satisfies cs, with
+// the prerelease and build metadata policies given by opts. vs does not
+// need to be sorted. ErrNoSatisfyingVersion is returned when no version is
+// eligible.
+func MaxSatisfying(vs Collection, cs *Constraints, opts ...SelectOption) (*Version, error) {
+	return selectSatisfying(vs, cs, 1, opts)
+}
+
+// MinSatisfying returns the lowest version in vs that satisfies cs, with the
+// prerelease and build metadata policies given by opts. vs does not need to
+// be sorted. ErrNoSatisfyingVersion is returned when no version is eligible.
+func MinSatisfying(vs Collection, cs *Constraints, opts ...SelectOption) (*Version, error) {
+	return selectSatisfying(vs, cs, -1, opts)
+}
+
+// selectSatisfying returns the eligible version in vs that sorts last when
+// dir is 1 and first when it is -1.
+func selectSatisfying(vs Collection, cs *Constraints, dir int, opts []SelectOption) (*Version, error) {
+	var o selectOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	if o.prerelease == 1 && !cs.IncludePrerelease {
+		c := *cs
+		c.IncludePrerelease = true
+		cs = &c
+	}
+
+	var best *Version
+	for _, v := range vs {
+		if o.prerelease == -1 && v.pre != "" || !cs.Check(v) {
+			continue
+		}
+		if best == nil || o.better(v, best, dir) {
+			best = v
+		}
+	}
+
+	if best == nil {
+		return nil, ErrNoSatisfyingVersion
+	}
+	return best, nil
+}
+
+// better reports whether v should be picked over best.
+func (o *selectOptions) better(v, best *Version, dir int) bool {
+	if n := v.Compare(best); n != 0 {
+		return n == dir
+	}
+
+	switch {
+	case o.laterMetadata:
+		return v.metadata > best.metadata
+	case o.metadataTie:
+		return v.CompareTotal(best) == dir
+	}
+	return false
+}
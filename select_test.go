@@ -0,0 +1,61 @@
+package semver
+
+import (
+	"testing"
+)
+
+func TestMaxMinSatisfying(t *testing.T) {
+	vs := Collection{
+		MustParse("1.2.0+b"),
+		MustParse("1.2.0+c"),
+		MustParse("1.2.0+a"),
+		MustParse("1.3.0-rc.1"),
+		MustParse("1.1.0"),
+		MustParse("1.1.0-beta"),
+		MustParse("2.0.0"),
+	}
+
+	tests := []struct {
+		constraint string
+		opts       []SelectOption
+		max, min   string
+	}{
+		{"^1.1", nil, "1.2.0+b", "1.1.0"},
+		{"^1.1", []SelectOption{IncludePrereleases()}, "1.3.0-rc.1", "1.1.0"},
+		{">=1.0.0-0, <2.0.0-0", nil, "1.3.0-rc.1", "1.1.0-beta"},
+		{">=1.0.0-0, <2.0.0-0", []SelectOption{ExcludePrereleases()}, "1.2.0+b", "1.1.0"},
+		{"=1.2.0", []SelectOption{MetadataTiebreak()}, "1.2.0+c", "1.2.0+a"},
+		{"=1.2.0", []SelectOption{PreferLaterMetadata()}, "1.2.0+c", "1.2.0+c"},
+		{"=1.2.0", []SelectOption{MetadataTiebreak(), PreferLaterMetadata()}, "1.2.0+c", "1.2.0+c"},
+		{"^3", nil, "", ""},
+		{"^1.3.0-rc.1", []SelectOption{ExcludePrereleases()}, "", ""},
+	}
+
+	for _, tc := range tests {
+		cs, err := NewConstraint(tc.constraint)
+		if err != nil {
+			t.Errorf("Error parsing constraint %q: %s", tc.constraint, err)
+			continue
+		}
+
+		max, err := MaxSatisfying(vs, cs, tc.opts...)
+		min, merr := MinSatisfying(vs, cs, tc.opts...)
+		if tc.max == "" {
+			if err != ErrNoSatisfyingVersion || merr != ErrNoSatisfyingVersion {
+				t.Errorf("Expected ErrNoSatisfyingVersion for %q, got %v and %v", tc.constraint, err, merr)
+			}
+			continue
+		}
+		if err != nil || max.Original() != tc.max {
+			t.Errorf("Expected the max for %q to be %q, got %v (%v)", tc.constraint, tc.max, max, err)
+		}
+		if merr != nil || min.Original() != tc.min {
+			t.Errorf("Expected the min for %q to be %q, got %v (%v)", tc.constraint, tc.min, min, merr)
+		}
+	}
+
+	cs, _ := NewConstraint("^1.1")
+	if _, err := MaxSatisfying(vs, cs, IncludePrereleases()); err != nil || cs.IncludePrerelease {
+		t.Errorf("Expected IncludePrereleases not to change the constraints, got %v", err)
+	}
+}
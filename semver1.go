@@ -0,0 +1,87 @@
+package semver
+
+import (
+	"strings"
+)
+
+// ParseSemVer1 parses a version written to SemVer 1.0.0 and its drafts, as
+// still published by some legacy registries. There the prerelease may follow
+// the patch directly, as in 1.0.0beta1, and is a single string of ASCII
+// alphanumerics and hyphens. Build metadata did not exist and is rejected.
+//
+// The version is returned in its canonical SemVer 2.0.0 form, so 1.0.0beta1
+// becomes 1.0.0-beta1, and Original returns the string as written. A
+// prerelease that is a number with leading zeros, such as the 01 in
+// 1.0.0-01, has no 2.0.0 form and is rejected. Compare the versions with
+// CompareSemVer1 to use the SemVer 1.0.0 precedence rules.
+func ParseSemVer1(v string) (*Version, error) {
+	if len(v) == 0 {
+		return nil, ErrEmptyString
+	}
+	if MaxVersionLength > 0 && len(v) > MaxVersionLength {
+		return nil, ErrVersionTooLong
+	}
+
+	// The prerelease starts after the digits of the patch.
+	i := strings.IndexByte(v, '.')
+	if i == -1 {
+		return nil, ErrInvalidSemVer
+	}
+	k := strings.IndexByte(v[i+1:], '.')
+	if k == -1 {
+		return nil, ErrInvalidSemVer
+	}
+	j := i + k + 2
+	for j < len(v) && v[j] >= '0' && v[j] <= '9' {
+		j++
+	}
+
+	s, pre := v[:j], strings.TrimPrefix(v[j:], "-")
+	if j < len(v) {
+		if pre == "" || !containsOnly(pre, allowed) {
+			return nil, ErrInvalidPrerelease
+		}
+		s += "-" + pre
+	}
+
+	sv, err := StrictNewVersion(s)
+	if err != nil {
+		return nil, err
+	}
+	sv.original = v
+
+	return sv, nil
+}
+
+// CompareSemVer1 compares two versions using the precedence rules of SemVer
+// 1.0.0. It returns -1, 0, or 1 if a is lower than, equal to, or higher than
+// b. It differs from Compare only in that prereleases are compared as a
+// single string in ASCII order, so 1.0.0-beta10 is lower than 1.0.0-beta2 and
+// 1.0.0-10 lower than 1.0.0-9. Build metadata is ignored.
+//
+// Constraints always use the SemVer 2.0.0 rules of Compare. These agree with
+// CompareSemVer1 for versions parsed by ParseSemVer1 except when both
+// prereleases are numbers, such as 10 and 9.
+func CompareSemVer1(a, b *Version) int {
+	if d := compareSegment(a.major, b.major); d != 0 {
+		return d
+	}
+	if d := compareSegment(a.minor, b.minor); d != 0 {
+		return d
+	}
+	if d := compareSegment(a.patch, b.patch); d != 0 {
+		return d
+	}
+
+	switch {
+	case a.pre == b.pre:
+		return 0
+	case a.pre == "":
+		return 1
+	case b.pre == "":
+		return -1
+	case a.pre < b.pre:
+		return -1
+	}
+	return 1
+}
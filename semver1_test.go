@@ -0,0 +1,87 @@
+package semver
+
+import (
+	"testing"
+)
+
+func TestParseSemVer1(t *testing.T) {
+	tests := []struct {
+		version  string
+		expected string
+		err      bool
+	}{
+		{"1.0.0", "1.0.0", false},
+		{"1.0.0beta1", "1.0.0-beta1", false},
+		{"1.0.0-beta1", "1.0.0-beta1", false},
+		{"1.0.0-rc-1", "1.0.0-rc-1", false},
+		{"1.0.10alpha", "1.0.10-alpha", false},
+		{"1.0.0-", "", true},
+		{"1.0.0-rc.1", "", true},
+		{"1.0.0+build", "", true},
+		{"1.0.0beta+build", "", true},
+		{"1.0.0-01", "", true},
+		{"1.0.0-10", "1.0.0-10", false},
+		{"v1.0.0", "", true},
+		{"1.0", "", true},
+		{"1.0beta", "", true},
+		{"", "", true},
+	}
+
+	for _, tc := range tests {
+		v, err := ParseSemVer1(tc.version)
+		if tc.err {
+			if err == nil {
+				t.Errorf("Expected error parsing %q, got %s", tc.version, v)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Error parsing %q: %s", tc.version, err)
+			continue
+		}
+
+		if v.String() != tc.expected {
+			t.Errorf("Expected %q to be %s, got %s", tc.version, tc.expected, v)
+		}
+		if v.Original() != tc.version {
+			t.Errorf("Expected original %q, got %q", tc.version, v.Original())
+		}
+	}
+}
+
+func TestCompareSemVer1(t *testing.T) {
+	tests := []struct {
+		v1, v2   string
+		expected int
+		compare  int
+	}{
+		{"1.0.0", "1.0.0", 0, 0},
+		{"1.0.0", "1.0.1", -1, -1},
+		{"2.0.0", "1.9.9", 1, 1},
+		{"1.0.0beta", "1.0.0", -1, -1},
+		{"1.0.0", "1.0.0beta", 1, 1},
+		{"1.0.0alpha", "1.0.0beta", -1, -1},
+		{"1.0.0beta10", "1.0.0beta2", -1, -1},
+		{"1.0.0-10", "1.0.0-9", -1, 1},
+		{"1.0.0-9", "1.0.0-a", -1, -1},
+		{"1.0.0-RC1", "1.0.0-rc1", -1, -1},
+	}
+
+	for _, tc := range tests {
+		v1, err := ParseSemVer1(tc.v1)
+		if err != nil {
+			t.Fatalf("Error parsing %q: %s", tc.v1, err)
+		}
+		v2, err := ParseSemVer1(tc.v2)
+		if err != nil {
+			t.Fatalf("Error parsing %q: %s", tc.v2, err)
+		}
+
+		if a := CompareSemVer1(v1, v2); a != tc.expected {
+			t.Errorf("Expected CompareSemVer1(%s, %s) to be %d, got %d", tc.v1, tc.v2, tc.expected, a)
+		}
+		if a := v1.Compare(v2); a != tc.compare {
+			t.Errorf("Expected Compare(%s, %s) to be %d, got %d", tc.v1, tc.v2, tc.compare, a)
+		}
+	}
+}
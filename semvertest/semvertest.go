@@ -0,0 +1,232 @@
+// Package semvertest provides test helpers for code using semantic versions
+// and constraints.
+//
+// The Assert functions report failures through a TestingT, which *testing.T
+// and *testing.B satisfy, as do the test types of testify. The matchers
+// returned by Satisfy and BeOrdered implement the gomega matcher interface
+// without this package depending on gomega.
+package semvertest
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+)
+
+// TestingT is the subset of testing.TB used to report failures.
+type TestingT interface {
+	Errorf(format string, args ...interface{})
+}
+
+// helper is implemented by *testing.T so failures are reported at the
+// caller of the assertion.
+type helper interface {
+	Helper()
+}
+
+// AssertSatisfies reports a failure unless the version satisfies the
+// constraint. The failure includes the reasons the constraint rejected the
+// version. It returns whether the assertion passed.
+func AssertSatisfies(t TestingT, constraint, version string) bool {
+	if h, ok := t.(helper); ok {
+		h.Helper()
+	}
+
+	ok, msg := satisfies(constraint, version)
+	if !ok {
+		t.Errorf("%s", msg)
+	}
+	return ok
+}
+
+// AssertNotSatisfies reports a failure if the version satisfies the
+// constraint. It returns whether the assertion passed.
+func AssertNotSatisfies(t TestingT, constraint, version string) bool {
+	if h, ok := t.(helper); ok {
+		h.Helper()
+	}
+
+	c, v, err := parse(constraint, version)
+	if err != nil {
+		t.Errorf("%s", err)
+		return false
+	}
+
+	if c.Check(v) {
+		t.Errorf("Expected %q not to satisfy %q", version, constraint)
+		return false
+	}
+	return true
+}
+
+// AssertOrdered reports a failure unless the versions are in ascending order
+// of precedence. Each pair out of order is reported. It returns whether the
+// assertion passed.
+func AssertOrdered(t TestingT, versions ...string) bool {
+	if h, ok := t.(helper); ok {
+		h.Helper()
+	}
+
+	msg := ordered(versions)
+	if msg != "" {
+		t.Errorf("%s", msg)
+		return false
+	}
+	return true
+}
+
+// Matcher is a gomega compatible matcher.
+type Matcher struct {
+	match   func(actual interface{}) (bool, error)
+	failure func(actual interface{}) string
+	negated func(actual interface{}) string
+}
+
+// Match reports whether actual matches.
+func (m *Matcher) Match(actual interface{}) (bool, error) {
+	return m.match(actual)
+}
+
+// FailureMessage returns the message for when actual does not match.
+func (m *Matcher) FailureMessage(actual interface{}) string {
+	return m.failure(actual)
+}
+
+// NegatedFailureMessage returns the message for when actual matches but was
+// expected not to.
+func (m *Matcher) NegatedFailureMessage(actual interface{}) string {
+	return m.negated(actual)
+}
+
+// Satisfy returns a matcher that matches versions, given as a string or a
+// *semver.Version, satisfying the constraint.
+func Satisfy(constraint string) *Matcher {
+	return &Matcher{
+		match: func(actual interface{}) (bool, error) {
+			s, err := versionString(actual)
+			if err != nil {
+				return false, err
+			}
+			c, v, err := parse(constraint, s)
+			if err != nil {
+				return false, err
+			}
+			return c.Check(v), nil
+		},
+		failure: func(actual interface{}) string {
+			s, _ := versionString(actual)
+			_, msg := satisfies(constraint, s)
+			return msg
+		},
+		negated: func(actual interface{}) string {
+			s, _ := versionString(actual)
+			return fmt.Sprintf("Expected %q not to satisfy %q", s, constraint)
+		},
+	}
+}
+
+// BeOrdered returns a matcher that matches a []string or
+// []*semver.Version in ascending order of precedence.
+func BeOrdered() *Matcher {
+	return &Matcher{
+		match: func(actual interface{}) (bool, error) {
+			vs, err := versionStrings(actual)
+			if err != nil {
+				return false, err
+			}
+			return ordered(vs) == "", nil
+		},
+		failure: func(actual interface{}) string {
+			vs, _ := versionStrings(actual)
+			return ordered(vs)
+		},
+		negated: func(actual interface{}) string {
+			vs, _ := versionStrings(actual)
+			return fmt.Sprintf("Expected %q not to be in order", vs)
+		},
+	}
+}
+
+// satisfies checks the version against the constraint and returns the
+// failure message when it does not satisfy it.
+func satisfies(constraint, version string) (bool, string) {
+	c, v, err := parse(constraint, version)
+	if err != nil {
+		return false, err.Error()
+	}
+
+	ok, errs := c.Validate(v)
+	if ok {
+		return true, ""
+	}
+
+	reasons := make([]string, len(errs))
+	for i, e := range errs {
+		reasons[i] = e.Error()
+	}
+	return false, fmt.Sprintf("Expected %q to satisfy %q:\n\t%s", version, constraint, strings.Join(reasons, "\n\t"))
+}
+
+// ordered returns a message listing the pairs of versions out of order, or
+// "" if they are in order.
+func ordered(versions []string) string {
+	var msgs []string
+	var last *semver.Version
+	for i, s := range versions {
+		v, err := semver.NewVersion(s)
+		if err != nil {
+			msgs = append(msgs, fmt.Sprintf("Error parsing version %q: %s", s, err))
+			last = nil
+			continue
+		}
+		if last != nil && v.LessThan(last) {
+			msgs = append(msgs, fmt.Sprintf("Expected %q at index %d to come before %q at index %d", s, i, versions[i-1], i-1))
+		}
+		last = v
+	}
+	return strings.Join(msgs, "\n")
+}
+
+// parse parses the constraint and version.
+func parse(constraint, version string) (*semver.Constraints, *semver.Version, error) {
+	c, err := semver.NewConstraint(constraint)
+	if err != nil {
+		return nil, nil, fmt.Errorf("Error parsing constraint %q: %s", constraint, err)
+	}
+	v, err := semver.NewVersion(version)
+	if err != nil {
+		return nil, nil, fmt.Errorf("Error parsing version %q: %s", version, err)
+	}
+	return c, v, nil
+}
+
+// versionString converts the actual value given to a matcher to a string.
+func versionString(actual interface{}) (string, error) {
+	switch a := actual.(type) {
+	case string:
+		return a, nil
+	case *semver.Version:
+		return a.String(), nil
+	case semver.Version:
+		return a.String(), nil
+	}
+	return "", fmt.Errorf("Expected a version string or *semver.Version, got %T", actual)
+}
+
+// versionStrings converts the actual value given to a matcher to strings.
+func versionStrings(actual interface{}) ([]string, error) {
+	switch a := actual.(type) {
+	case []string:
+		return a, nil
+	case []*semver.Version:
+		vs := make([]string, len(a))
+		for i, v := range a {
+			vs[i] = v.String()
+		}
+		return vs, nil
+	case semver.Collection:
+		return versionStrings([]*semver.Version(a))
+	}
+	return nil, fmt.Errorf("Expected []string or []*semver.Version, got %T", actual)
+}
@@ -0,0 +1,133 @@
+package semvertest
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/Masterminds/semver/v3"
+)
+
+// recorder is a TestingT that records failures.
+type recorder struct {
+	failures []string
+}
+
+func (r *recorder) Errorf(format string, args ...interface{}) {
+	r.failures = append(r.failures, fmt.Sprintf(format, args...))
+}
+
+func TestAssertSatisfies(t *testing.T) {
+	r := &recorder{}
+	if !AssertSatisfies(r, "^1.2", "1.4.0") || len(r.failures) != 0 {
+		t.Errorf("Unexpected failures: %v", r.failures)
+	}
+
+	r = &recorder{}
+	if AssertSatisfies(r, "^1.2", "2.0.0") {
+		t.Error("Expected the assertion to fail")
+	}
+	if len(r.failures) != 1 || !strings.Contains(r.failures[0], "2.0.0 does not have same major version as 1.2") {
+		t.Errorf("Expected the failure to explain the rejection, got %v", r.failures)
+	}
+
+	r = &recorder{}
+	if AssertSatisfies(r, "^1.2", "foo") || len(r.failures) != 1 {
+		t.Errorf("Expected a failure for an invalid version, got %v", r.failures)
+	}
+}
+
+func TestAssertNotSatisfies(t *testing.T) {
+	r := &recorder{}
+	if !AssertNotSatisfies(r, "^1.2", "2.0.0") || len(r.failures) != 0 {
+		t.Errorf("Unexpected failures: %v", r.failures)
+	}
+
+	r = &recorder{}
+	if AssertNotSatisfies(r, "^1.2", "1.3.0") || len(r.failures) != 1 {
+		t.Errorf("Expected one failure, got %v", r.failures)
+	}
+
+	r = &recorder{}
+	if AssertNotSatisfies(r, "^^", "1.3.0") || len(r.failures) != 1 {
+		t.Errorf("Expected a failure for an invalid constraint, got %v", r.failures)
+	}
+}
+
+func TestAssertOrdered(t *testing.T) {
+	r := &recorder{}
+	if !AssertOrdered(r, "1.0.0-alpha", "1.0.0", "1.0.0+build", "1.2.0", "v2") || len(r.failures) != 0 {
+		t.Errorf("Unexpected failures: %v", r.failures)
+	}
+
+	r = &recorder{}
+	if AssertOrdered(r, "1.0.0", "0.9.0", "1.1.0", "1.0.1") {
+		t.Error("Expected the assertion to fail")
+	}
+	if len(r.failures) != 1 || strings.Count(r.failures[0], "\n") != 1 {
+		t.Errorf("Expected both pairs out of order to be reported, got %v", r.failures)
+	}
+}
+
+func TestSatisfy(t *testing.T) {
+	m := Satisfy(">=1.2.3")
+
+	for _, actual := range []interface{}{"1.2.3", semver.MustParse("1.5.0"), *semver.MustParse("2.0.0")} {
+		if ok, err := m.Match(actual); !ok || err != nil {
+			t.Errorf("Expected %v to match, got %t, %v", actual, ok, err)
+		}
+	}
+
+	if ok, _ := m.Match("1.0.0"); ok {
+		t.Error("Expected 1.0.0 not to match")
+	}
+	if !strings.Contains(m.FailureMessage("1.0.0"), "1.0.0 is less than 1.2.3") {
+		t.Errorf("Expected the failure message to explain the rejection, got %q", m.FailureMessage("1.0.0"))
+	}
+	if m.NegatedFailureMessage("1.5.0") == "" {
+		t.Error("Expected a negated failure message")
+	}
+
+	if _, err := m.Match(42); err == nil {
+		t.Error("Expected an error for an unsupported type")
+	}
+
+	// Versions without a parseable original string are matched by their
+	// canonical form.
+	fp, err := semver.FromParts(1, 5, 0, nil, nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	lenient, _, err := semver.ParseLenient("  =1.5.0.4 ")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	for _, actual := range []interface{}{fp, lenient} {
+		if ok, err := m.Match(actual); !ok || err != nil {
+			t.Errorf("Expected %v to match, got %t, %v", actual, ok, err)
+		}
+	}
+	if ok, err := Satisfy("<1").Match(semver.Version{}); !ok || err != nil {
+		t.Errorf("Expected the zero value to match <1, got %t, %v", ok, err)
+	}
+}
+
+func TestBeOrdered(t *testing.T) {
+	m := BeOrdered()
+
+	if ok, err := m.Match([]string{"1.0.0", "1.1.0"}); !ok || err != nil {
+		t.Errorf("Expected ordered strings to match, got %t, %v", ok, err)
+	}
+
+	vs := semver.Collection{semver.MustParse("2.0.0"), semver.MustParse("1.0.0")}
+	if ok, err := m.Match(vs); ok || err != nil {
+		t.Errorf("Expected unordered versions not to match, got %t, %v", ok, err)
+	}
+	if m.FailureMessage(vs) == "" {
+		t.Error("Expected a failure message")
+	}
+
+	if _, err := m.Match("1.0.0"); err == nil {
+		t.Error("Expected an error for an unsupported type")
+	}
+}
@@ -0,0 +1,60 @@
+//go:build go1.21
+// +build go1.21
+
+package semver
+
+import (
+	"log/slog"
+)
+
+// LogValue implements the slog.LogValuer interface so versions are logged as
+// a group of their parts rather than a flat string. The prerelease and
+// metadata are omitted when empty.
+func (v Version) LogValue() slog.Value {
+	attrs := []slog.Attr{
+		slog.String("version", v.String()),
+		slog.Uint64("major", v.major),
+		slog.Uint64("minor", v.minor),
+		slog.Uint64("patch", v.patch),
+	}
+	if v.pre != "" {
+		attrs = append(attrs, slog.String("prerelease", v.pre))
+	}
+	if v.metadata != "" {
+		attrs = append(attrs, slog.String("metadata", v.metadata))
+	}
+
+	return slog.GroupValue(attrs...)
+}
+
+// LogValue implements the slog.LogValuer interface so constraints are logged
+// as a group holding the constraint string and the terms of each of its ||
+// groups, as in the JSON form. The options are included when set.
+func (cs Constraints) LogValue() slog.Value {
+	groups := make([][]string, len(cs.constraints))
+	for k, v := range cs.constraints {
+		groups[k] = make([]string, len(v))
+		for kk, c := range v {
+			groups[k][kk] = c.string()
+		}
+	}
+
+	attrs := []slog.Attr{
+		slog.String("constraint", cs.String()),
+		slog.Any("anyOf", groups),
+	}
+	if cs.ExclusionsMatchMetadata {
+		attrs = append(attrs, slog.Bool("exclusionsMatchMetadata", true))
+	}
+	if cs.EqualityMatchesMetadata {
+		attrs = append(attrs, slog.Bool("equalityMatchesMetadata", true))
+	}
+	if cs.IncludePrerelease {
+		attrs = append(attrs, slog.Bool("includePrerelease", true))
+	}
+	if cs.SnapPartialBounds {
+		attrs = append(attrs, slog.Bool("snapPartialBounds", true))
+	}
+
+	return slog.GroupValue(attrs...)
+}
@@ -0,0 +1,49 @@
+//go:build go1.21
+// +build go1.21
+
+package semver
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestVersionLogValue(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey && len(groups) == 0 {
+				return slog.Attr{}
+			}
+			return a
+		},
+	}))
+
+	logger.Info("resolved", "v", MustParse("v1.2.3-beta.1+build"), "r", MustParse("2.0.0"))
+
+	expected := "level=INFO msg=resolved v.version=1.2.3-beta.1+build v.major=1 v.minor=2 v.patch=3 v.prerelease=beta.1 v.metadata=build " +
+		"r.version=2.0.0 r.major=2 r.minor=0 r.patch=0\n"
+	if buf.String() != expected {
+		t.Errorf("Expected %q, got %q", expected, buf.String())
+	}
+}
+
+func TestConstraintsLogValue(t *testing.T) {
+	c, err := NewConstraint(">=1.2.3, <2 || ^3")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	c.IncludePrerelease = true
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+	logger.Info("checking", "c", c)
+
+	out := buf.String()
+	expected := `"c":{"constraint":">=1.2.3 <2 || ^3","anyOf":[[">=1.2.3","<2"],["^3"]],"includePrerelease":true}`
+	if !strings.Contains(out, expected) {
+		t.Errorf("Expected %s in %s", expected, out)
+	}
+}
@@ -0,0 +1,198 @@
+package semver
+
+import (
+	"bytes"
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// ErrSortableOverflow is returned when a version cannot be encoded because one
+// of its numbers is too large for the encoding.
+var ErrSortableOverflow = errors.New("Version segment too large to encode")
+
+// The layout of the sortable uint64 encoding. From the most significant bit,
+// it holds 21 bits of major version, 21 bits of minor version, 21 bits of
+// patch version, and a bit set for releases and clear for prereleases.
+const (
+	sortableSegmentBits = 21
+	sortableSegmentMax  = 1<<sortableSegmentBits - 1
+)
+
+// ToSortableUint64 encodes the version as a uint64 whose numeric order
+// matches the precedence of versions. This allows versions to be indexed and
+// range scanned in databases and key value stores.
+//
+// The major, minor, and patch numbers are each stored in 21 bits so they must
+// be at most 2097151, otherwise ErrSortableOverflow is returned. Prerelease
+// identifiers are not stored, only whether there is a prerelease, so all the
+// prereleases of a version encode to the same value and sort before the
+// release. Metadata is not stored.
+func ToSortableUint64(v *Version) (uint64, error) {
+	if v.Major() > sortableSegmentMax || v.Minor() > sortableSegmentMax || v.Patch() > sortableSegmentMax {
+		return 0, ErrSortableOverflow
+	}
+
+	n := v.Major()<<(2*sortableSegmentBits+1) |
+		v.Minor()<<(sortableSegmentBits+1) |
+		v.Patch()<<1
+	if v.Prerelease() == "" {
+		n |= 1
+	}
+
+	return n, nil
+}
+
+// FromSortableUint64 decodes a version encoded by ToSortableUint64. As the
+// prerelease identifiers are not stored, a prerelease decodes with the
+// prerelease 0, the lowest prerelease of that version.
+func FromSortableUint64(n uint64) *Version {
+	v := &Version{
+		major: n >> (2*sortableSegmentBits + 1) & sortableSegmentMax,
+		minor: n >> (sortableSegmentBits + 1) & sortableSegmentMax,
+		patch: n >> 1 & sortableSegmentMax,
+	}
+	if n&1 == 0 {
+		v.pre = "0"
+	}
+	v.original = v.String()
+
+	return v
+}
+
+// ErrInvalidSortableString is returned when a string was not produced by
+// ToSortableString.
+var ErrInvalidSortableString = errors.New("Invalid sortable version string")
+
+// sortableNumWidth is the width numbers are padded to in the sortable string
+// encoding, enough to hold any uint64.
+const sortableNumWidth = 20
+
+// ToSortableString encodes the version as a string whose byte-wise order
+// matches the precedence of versions. This allows versions to be ordered by
+// systems that can only sort strings, such as object storage keys.
+//
+// The major, minor, and patch numbers are zero padded to 20 digits and
+// separated by dots. A release is followed by a ~, which sorts after the -
+// that introduces a prerelease. Each prerelease identifier is encoded as a 0
+// followed by the number zero padded to 20 digits, or a 1 followed by the
+// identifier and a +, so numeric identifiers sort before alphanumeric ones
+// and shorter identifiers before longer ones they are a prefix of. Numeric
+// prerelease identifiers longer than 20 digits cause ErrSortableOverflow to
+// be returned. Metadata is not stored.
+func ToSortableString(v *Version) (string, error) {
+	var buf bytes.Buffer
+
+	writeSortableNum(&buf, strconv.FormatUint(v.Major(), 10))
+	buf.WriteByte('.')
+	writeSortableNum(&buf, strconv.FormatUint(v.Minor(), 10))
+	buf.WriteByte('.')
+	writeSortableNum(&buf, strconv.FormatUint(v.Patch(), 10))
+
+	if v.Prerelease() == "" {
+		buf.WriteByte('~')
+		return buf.String(), nil
+	}
+
+	buf.WriteByte('-')
+	for _, id := range strings.Split(v.Prerelease(), ".") {
+		if containsOnly(id, num) {
+			id = strings.TrimLeft(id, "0")
+			if len(id) > sortableNumWidth {
+				return "", ErrSortableOverflow
+			}
+			buf.WriteByte('0')
+			writeSortableNum(&buf, id)
+		} else {
+			buf.WriteByte('1')
+			buf.WriteString(id)
+			buf.WriteByte('+')
+		}
+	}
+
+	return buf.String(), nil
+}
+
+// FromSortableString decodes a version encoded by ToSortableString.
+func FromSortableString(s string) (*Version, error) {
+	// The release part is three padded numbers, two dots, and a ~ or -.
+	if len(s) < 3*sortableNumWidth+3 {
+		return nil, ErrInvalidSortableString
+	}
+
+	v := &Version{}
+	segs := []*uint64{&v.major, &v.minor, &v.patch}
+	for i, seg := range segs {
+		start := i * (sortableNumWidth + 1)
+		n, err := readSortableNum(s[start : start+sortableNumWidth])
+		if err != nil {
+			return nil, err
+		}
+		*seg = n
+
+		if i < 2 && s[start+sortableNumWidth] != '.' {
+			return nil, ErrInvalidSortableString
+		}
+	}
+
+	rest := s[3*sortableNumWidth+2:]
+	switch {
+	case rest == "~":
+	case rest[0] == '-' && len(rest) > 1:
+		var ids []string
+		rest = rest[1:]
+		for len(rest) > 0 {
+			switch rest[0] {
+			case '0':
+				if len(rest) < sortableNumWidth+1 {
+					return nil, ErrInvalidSortableString
+				}
+				// Numeric prerelease identifiers can be larger than a
+				// uint64, so they are kept as digits.
+				id := rest[1 : sortableNumWidth+1]
+				if !containsOnly(id, num) {
+					return nil, ErrInvalidSortableString
+				}
+				if id = strings.TrimLeft(id, "0"); id == "" {
+					id = "0"
+				}
+				ids = append(ids, id)
+				rest = rest[sortableNumWidth+1:]
+			case '1':
+				i := strings.IndexByte(rest, '+')
+				if i < 2 || containsOnly(rest[1:i], num) || !containsOnly(rest[1:i], allowed) {
+					return nil, ErrInvalidSortableString
+				}
+				ids = append(ids, rest[1:i])
+				rest = rest[i+1:]
+			default:
+				return nil, ErrInvalidSortableString
+			}
+		}
+		v.pre = strings.Join(ids, ".")
+	default:
+		return nil, ErrInvalidSortableString
+	}
+
+	v.original = v.String()
+
+	return v, nil
+}
+
+// writeSortableNum writes the digits zero padded to sortableNumWidth.
+func writeSortableNum(buf *bytes.Buffer, digits string) {
+	buf.WriteString(strings.Repeat("0", sortableNumWidth-len(digits)))
+	buf.WriteString(digits)
+}
+
+// readSortableNum reads a number zero padded to sortableNumWidth.
+func readSortableNum(s string) (uint64, error) {
+	if !containsOnly(s, num) {
+		return 0, ErrInvalidSortableString
+	}
+	n, err := strconv.ParseUint(s, 10, 64)
+	if err != nil {
+		return 0, ErrInvalidSortableString
+	}
+	return n, nil
+}
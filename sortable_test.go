@@ -0,0 +1,138 @@
+package semver
+
+import (
+	"testing"
+)
+
+func TestSortableUint64(t *testing.T) {
+	tests := []struct {
+		version string
+		decoded string
+	}{
+		{"0.0.0-alpha", "0.0.0-0"},
+		{"0.0.0", "0.0.0"},
+		{"0.0.1", "0.0.1"},
+		{"0.1.0", "0.1.0"},
+		{"1.0.0-0", "1.0.0-0"},
+		{"1.0.0-beta.2", "1.0.0-0"},
+		{"1.0.0+build", "1.0.0"},
+		{"1.0.1", "1.0.1"},
+		{"1.2097151.0", "1.2097151.0"},
+		{"2.0.0", "2.0.0"},
+		{"2097151.2097151.2097151", "2097151.2097151.2097151"},
+	}
+
+	var last uint64
+	for i, tc := range tests {
+		v := MustParse(tc.version)
+		n, err := ToSortableUint64(v)
+		if err != nil {
+			t.Errorf("Unexpected error encoding %q: %s", tc.version, err)
+			continue
+		}
+
+		if i > 0 && n < last {
+			t.Errorf("Expected %q to encode above %q", tc.version, tests[i-1].version)
+		}
+		last = n
+
+		if d := FromSortableUint64(n); d.String() != tc.decoded {
+			t.Errorf("Expected %q to decode as %q, got %q", tc.version, tc.decoded, d)
+		}
+	}
+
+	for _, s := range []string{"2097152.0.0", "0.2097152.0", "0.0.2097152"} {
+		if _, err := ToSortableUint64(MustParse(s)); err != ErrSortableOverflow {
+			t.Errorf("Expected ErrSortableOverflow encoding %q, got %v", s, err)
+		}
+	}
+}
+
+func TestSortableString(t *testing.T) {
+	// In order of precedence.
+	versions := []string{
+		"0.0.0-0",
+		"0.0.0-alpha",
+		"0.0.0",
+		"0.0.9",
+		"0.0.10",
+		"0.1.0",
+		"1.0.0-0",
+		"1.0.0-2",
+		"1.0.0-10",
+		"1.0.0-10.1",
+		"1.0.0-18446744073709551616",
+		"1.0.0-99999999999999999999",
+		"1.0.0-A",
+		"1.0.0-a",
+		"1.0.0-a-",
+		"1.0.0-alpha",
+		"1.0.0-alpha.1",
+		"1.0.0-alpha.beta",
+		"1.0.0-beta",
+		"1.0.0-beta.2",
+		"1.0.0-beta.11",
+		"1.0.0-rc.1",
+		"1.0.0",
+		"1.0.1",
+		"1.10.0",
+		"10.0.0",
+		"18446744073709551615.0.0",
+	}
+
+	var last string
+	for i, s := range versions {
+		v := MustParse(s)
+		e, err := ToSortableString(v)
+		if err != nil {
+			t.Errorf("Unexpected error encoding %q: %s", s, err)
+			continue
+		}
+
+		if i > 0 && e <= last {
+			t.Errorf("Expected %q to encode above %q", s, versions[i-1])
+		}
+		last = e
+
+		d, err := FromSortableString(e)
+		if err != nil {
+			t.Errorf("Unexpected error decoding %q: %s", e, err)
+			continue
+		}
+		if d.String() != s {
+			t.Errorf("Expected %q to decode as %q, got %q", e, s, d)
+		}
+	}
+
+	e, err := ToSortableString(MustParse("1.0.0-beta.1+build"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if d, _ := FromSortableString(e); d.String() != "1.0.0-beta.1" {
+		t.Errorf("Expected metadata to be dropped, got %q", d)
+	}
+
+	if _, err := ToSortableString(MustParse("1.0.0-123456789012345678901")); err != ErrSortableOverflow {
+		t.Errorf("Expected ErrSortableOverflow, got %v", err)
+	}
+
+	valid, _ := ToSortableString(MustParse("1.2.3"))
+	invalid := []string{
+		"",
+		"1.2.3",
+		valid[:len(valid)-1],
+		valid[:len(valid)-1] + "-",
+		valid[:len(valid)-1] + "x",
+		valid + "~",
+		valid[:len(valid)-1] + "-1abc",
+		valid[:len(valid)-1] + "-1+",
+		valid[:len(valid)-1] + "-0123",
+		valid[:len(valid)-1] + "-2abc+",
+		"a" + valid[1:],
+	}
+	for _, s := range invalid {
+		if _, err := FromSortableString(s); err != ErrInvalidSortableString {
+			t.Errorf("Expected ErrInvalidSortableString decoding %q, got %v", s, err)
+		}
+	}
+}
@@ -0,0 +1,297 @@
+package semver
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Split returns constraints admitting the same versions as cs in which no
+// group has a != term, for solvers that only understand intervals. Each
+// exclusion inside a range splits it in two, so >=1.0.0 <2.0.0 !=1.5.0
+// becomes >=1.0.0 <1.5.0 || >1.5.0 <2.0.0, and comparisons made redundant by
+// the split are dropped. Groups without a != term are left as they are and
+// the options on cs are kept. Fold reverses the split.
+//
+// An error is returned when the split would change the versions admitted.
+// This is the case for a != on a release, such as !=1.5.0, in a group that
+// admits prereleases, as <1.5.0 and >1.5.0 would reject them, for a != on an
+// exact version with metadata when ExclusionsMatchMetadata is set, and for
+// a != with a wildcard major version.
+func (cs Constraints) Split() (*Constraints, error) {
+	out := cs
+	out.constraints = nil
+
+	for _, group := range cs.constraints {
+		pieces, err := cs.splitGroup(group)
+		if err != nil {
+			return nil, err
+		}
+		out.constraints = append(out.constraints, pieces...)
+	}
+
+	if len(out.constraints) == 0 {
+		// Every group was empty. Nothing is below the lowest version.
+		lowest := &Version{pre: "0", original: "0.0.0-0"}
+		out.constraints = [][]*constraint{{exactConstraint("<", lowest)}}
+	}
+
+	return &out, nil
+}
+
+// splitGroup returns the groups without != terms that together admit the
+// versions an AND group does. It returns no groups if the group admits no
+// versions.
+func (cs Constraints) splitGroup(group []*constraint) ([][]*constraint, error) {
+	var base, excl []*constraint
+	for _, c := range group {
+		if c.origfunc == "!=" {
+			excl = append(excl, c)
+		} else {
+			base = append(base, c)
+		}
+	}
+	if len(excl) == 0 {
+		return [][]*constraint{group}, nil
+	}
+
+	pieces := [][]*constraint{base}
+	for _, e := range excl {
+		sides, err := cs.exclusionSides(e)
+		if err != nil {
+			return nil, err
+		}
+
+		var next [][]*constraint
+		for _, p := range pieces {
+			for _, s := range sides {
+				q := append(append([]*constraint(nil), p...), s)
+				if !windowEmpty(q) {
+					next = append(next, q)
+				}
+			}
+		}
+		pieces = next
+	}
+
+	rejects := cs.rejectsPrereleases(group)
+	for i, p := range pieces {
+		p = cs.dropRedundant(p)
+		if cs.rejectsPrereleases(p) != rejects {
+			return nil, fmt.Errorf("splitting %s would change the prereleases admitted", constraintsString(group))
+		}
+		sort.SliceStable(p, func(i, j int) bool { return splitRank(p[i]) < splitRank(p[j]) })
+		pieces[i] = p
+	}
+
+	return pieces, nil
+}
+
+// exclusionSides returns the comparisons admitting the versions below and
+// above those a != term excludes.
+func (cs Constraints) exclusionSides(e *constraint) ([]*constraint, error) {
+	if e.isAny() {
+		return nil, fmt.Errorf("%s cannot be split", e.string())
+	}
+	if !e.dirty && cs.ExclusionsMatchMetadata && e.con.metadata != "" {
+		return nil, fmt.Errorf("%s cannot be split when exclusions match metadata", e.string())
+	}
+
+	b := e.bound()
+	if !e.dirty {
+		return []*constraint{exactConstraint("<", b.Min), exactConstraint(">", b.Max)}, nil
+	}
+
+	// !=1.2.x excludes the prereleases of 1.2.0 but not those of 1.3.0 when
+	// prereleases are checked by precedence, so both bounds become the
+	// lowest prerelease.
+	lo, hi := *b.Min, *b.Max
+	if cs.IncludePrerelease {
+		lo.pre, hi.pre = "0", "0"
+	}
+	return []*constraint{exactConstraint("<", &lo), exactConstraint(">=", &hi)}, nil
+}
+
+// Fold returns constraints admitting the same versions as cs in which pairs
+// of groups that are a range split by one excluded version are joined back
+// into a group with a != term, so >=1.0.0 <1.5.0 || >1.5.0 <2.0.0 becomes
+// >=1.0.0 <2.0.0 !=1.5.0. Only groups of plain comparisons on exact versions,
+// such as those returned by Split, are folded. The options on cs are kept.
+func (cs Constraints) Fold() *Constraints {
+	out := cs
+	out.constraints = append([][]*constraint(nil), cs.constraints...)
+
+	for folded := true; folded; {
+		folded = false
+		for i := 0; i < len(out.constraints) && !folded; i++ {
+			for j := i + 1; j < len(out.constraints) && !folded; j++ {
+				g, ok := cs.foldGroups(out.constraints[i], out.constraints[j])
+				if !ok {
+					g, ok = cs.foldGroups(out.constraints[j], out.constraints[i])
+				}
+				if ok {
+					out.constraints[i] = g
+					out.constraints = append(out.constraints[:j], out.constraints[j+1:]...)
+					folded = true
+				}
+			}
+		}
+	}
+
+	return &out
+}
+
+// foldGroups joins lo, a range ending at <v, and hi, a range starting at >v,
+// into a single group with !=v.
+func (cs Constraints) foldGroups(lo, hi []*constraint) ([]*constraint, bool) {
+	loRest, lt := foldSplit(lo, "<")
+	hiRest, gt := foldSplit(hi, ">")
+	if lt == nil || gt == nil || lt.con.Compare(gt.con) != 0 || lt.con.metadata != gt.con.metadata {
+		return nil, false
+	}
+	v := lt.con
+	if cs.ExclusionsMatchMetadata && v.metadata != "" {
+		return nil, false
+	}
+
+	// Everything else in lo must admit all versions above v and everything
+	// else in hi all versions below it.
+	for _, c := range loRest {
+		if !(isLowerComparison(c) || c.origfunc == "!=") || c.con.Compare(v) >= 0 {
+			return nil, false
+		}
+	}
+	for _, c := range hiRest {
+		if !(isUpperComparison(c) || c.origfunc == "!=") || c.con.Compare(v) <= 0 {
+			return nil, false
+		}
+	}
+
+	g := append(append([]*constraint(nil), loRest...), hiRest...)
+	g = append(g, exactConstraint("!=", v))
+
+	rejects := cs.rejectsPrereleases(g)
+	if cs.rejectsPrereleases(lo) != rejects || cs.rejectsPrereleases(hi) != rejects {
+		return nil, false
+	}
+
+	sort.SliceStable(g, func(i, j int) bool { return splitRank(g[i]) < splitRank(g[j]) })
+	return g, true
+}
+
+// foldSplit returns the only term of the group on an exact version with the
+// operator op, and the other terms, which must all be on exact versions.
+// The term is nil if there is not exactly one.
+func foldSplit(group []*constraint, op string) ([]*constraint, *constraint) {
+	var rest []*constraint
+	var t *constraint
+	for _, c := range group {
+		if c.dirty {
+			return nil, nil
+		}
+		if c.origfunc == op {
+			if t != nil {
+				return nil, nil
+			}
+			t = c
+			continue
+		}
+		rest = append(rest, c)
+	}
+	return rest, t
+}
+
+// rejectsPrereleases reports whether an AND group rejects every prerelease,
+// as it does when any term on a release other than an exact != does.
+func (cs Constraints) rejectsPrereleases(group []*constraint) bool {
+	if cs.IncludePrerelease {
+		return false
+	}
+	for _, c := range group {
+		if c.con.pre == "" && !(c.origfunc == "!=" && !c.dirty) {
+			return true
+		}
+	}
+	return false
+}
+
+// dropRedundant removes the > and >= terms with a lower bound than another
+// one in the group, and likewise the < and <= terms, unless the group would
+// then stop rejecting prereleases.
+func (cs Constraints) dropRedundant(group []*constraint) []*constraint {
+	out := append([]*constraint(nil), group...)
+	rejects := cs.rejectsPrereleases(out)
+	for i := 0; i < len(out); i++ {
+		for j := range out {
+			if i == j || !tighter(out[j], out[i]) {
+				continue
+			}
+			others := append(append([]*constraint(nil), out[:i]...), out[i+1:]...)
+			if cs.rejectsPrereleases(others) != rejects {
+				continue
+			}
+			out = others
+			i--
+			break
+		}
+	}
+	return out
+}
+
+// tighter reports whether a and b are comparisons on exact versions in the
+// same direction and a admits no more versions than b.
+func tighter(a, b *constraint) bool {
+	ab, bb := a.bound(), b.bound()
+	switch {
+	case isLowerComparison(a) && isLowerComparison(b):
+		n := ab.Min.Compare(bb.Min)
+		return n > 0 || n == 0 && (!ab.IncludeMin || bb.IncludeMin)
+	case isUpperComparison(a) && isUpperComparison(b):
+		n := ab.Max.Compare(bb.Max)
+		return n < 0 || n == 0 && (!ab.IncludeMax || bb.IncludeMax)
+	}
+	return false
+}
+
+func isLowerComparison(c *constraint) bool {
+	switch c.origfunc {
+	case ">", ">=", "=>":
+		return !c.dirty
+	}
+	return false
+}
+
+func isUpperComparison(c *constraint) bool {
+	switch c.origfunc {
+	case "<", "<=", "=<":
+		return !c.dirty
+	}
+	return false
+}
+
+// splitRank orders the terms of groups written by Split and Fold with lower
+// bounds first, then upper bounds and then exclusions.
+func splitRank(c *constraint) int {
+	switch {
+	case isLowerComparison(c):
+		return 0
+	case isUpperComparison(c):
+		return 2
+	case c.origfunc == "!=":
+		return 3
+	}
+	return 1
+}
+
+// windowEmpty reports whether an AND group without != terms admits no
+// versions.
+func windowEmpty(group []*constraint) bool {
+	var r versionRange
+	for _, c := range group {
+		r = r.intersect(c.window())
+	}
+	return r.empty()
+}
+
+func constraintsString(group []*constraint) string {
+	return Constraints{constraints: [][]*constraint{group}}.String()
+}
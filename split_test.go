@@ -0,0 +1,146 @@
+package semver
+
+import (
+	"math/rand"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestConstraintsSplit(t *testing.T) {
+	tests := []struct {
+		constraint string
+		split      string
+		err        bool
+	}{
+		{">=1.0.0, <2.0.0, !=1.5.0", ">=1.0.0 <1.5.0 || >1.5.0 <2.0.0", false},
+		{">=1.0.0, <2.0.0, !=1.5.0, !=1.7.0", ">=1.0.0 <1.5.0 || >1.5.0 <1.7.0 || >1.7.0 <2.0.0", false},
+		{"^1.2, !=1.4.x", "^1.2 <1.4.0 || >=1.5.0 ^1.2", false},
+		{">=1.0.0, !=0.5.0", ">=1.0.0", false},
+		{"~1.2.3, !=1.2.3", ">1.2.3 ~1.2.3", false},
+		{"=1.2.3, !=1.2.3", "<0.0.0-0", false},
+		{"^1 || ^2, !=2.1.0", "^1 || ^2 <2.1.0 || >2.1.0 ^2", false},
+		{">=1.0.0-rc.1, !=1.0.0-rc.3", ">=1.0.0-rc.1 <1.0.0-rc.3 || >1.0.0-rc.3", false},
+		{"!=1.2.3", "", true},
+		{">=1.0.0-rc.1, !=1.0.0", "", true},
+		{"!=*", "", true},
+	}
+
+	for _, tc := range tests {
+		c, err := NewConstraint(tc.constraint)
+		if err != nil {
+			t.Errorf("Error parsing constraint %q: %s", tc.constraint, err)
+			continue
+		}
+
+		s, err := c.Split()
+		if tc.err {
+			if err == nil {
+				t.Errorf("Expected error splitting %q, got %q", tc.constraint, s)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Error splitting %q: %s", tc.constraint, err)
+			continue
+		}
+		if s.String() != tc.split {
+			t.Errorf("Expected %q to split into %q, got %q", tc.constraint, tc.split, s)
+		}
+	}
+}
+
+func TestConstraintsFold(t *testing.T) {
+	tests := []struct {
+		constraint string
+		folded     string
+	}{
+		{">=1.0.0 <1.5.0 || >1.5.0 <2.0.0", ">=1.0.0 <2.0.0 !=1.5.0"},
+		{">1.5.0 <2.0.0 || >=1.0.0 <1.5.0", ">=1.0.0 <2.0.0 !=1.5.0"},
+		{">=1.0.0 <1.5.0 || >1.5.0 <1.7.0 || >1.7.0 <2.0.0", ">=1.0.0 <2.0.0 !=1.5.0 !=1.7.0"},
+		{">=1.0.0 <1.5.0 || >1.5.0", ">=1.0.0 !=1.5.0"},
+
+		// Not a single excluded version.
+		{">=1.0.0 <1.5.0 || >1.5.1 <2.0.0", ">=1.0.0 <1.5.0 || >1.5.1 <2.0.0"},
+		{">=1.0.0 <1.5.0 || >=1.5.0 <2.0.0", ">=1.0.0 <1.5.0 || >=1.5.0 <2.0.0"},
+
+		// The lower range does not admit everything above 1.5.0.
+		{">=1.0.0 <1.5.0 <1.2.0 || >1.5.0 <2.0.0", ">=1.0.0 <1.5.0 <1.2.0 || >1.5.0 <2.0.0"},
+
+		// !=1.5.0 admits prereleases that <1.5.0 and >1.5.0 reject.
+		{"<1.5.0 || >1.5.0", "<1.5.0 || >1.5.0"},
+
+		{"^1.2 <1.4.0 || >1.4.0 ^1.2", "^1.2 <1.4.0 || >1.4.0 ^1.2"},
+	}
+
+	for _, tc := range tests {
+		c, err := NewConstraint(tc.constraint)
+		if err != nil {
+			t.Errorf("Error parsing constraint %q: %s", tc.constraint, err)
+			continue
+		}
+
+		if f := c.Fold(); f.String() != tc.folded {
+			t.Errorf("Expected %q to fold into %q, got %q", tc.constraint, tc.folded, f)
+		}
+	}
+}
+
+func TestConstraintsSplitFoldProperties(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	o := &GenerateOptions{Prerelease: 0.3}
+
+	var universe Collection
+	for major := uint64(0); major <= 7; major++ {
+		for minor := uint64(0); minor <= 7; minor++ {
+			for patch := uint64(0); patch <= 7; patch++ {
+				for _, pre := range []string{"", "0", "beta"} {
+					universe = append(universe, &Version{major: major, minor: minor, patch: patch, pre: pre})
+				}
+			}
+		}
+	}
+	sort.Sort(universe)
+
+	same := func(a, b *Constraints) bool {
+		for _, v := range universe {
+			if a.Check(v) != b.Check(v) {
+				return false
+			}
+		}
+		return true
+	}
+
+	var split int
+	for i := 0; i < 1000; i++ {
+		c := GenerateConstraint(r, o)
+		c.IncludePrerelease = r.Intn(3) == 0
+
+		f := c.Fold()
+		if !same(c, f) {
+			t.Errorf("Expected %q and its fold %q to admit the same versions", c, f)
+		}
+
+		s, err := c.Split()
+		if err != nil {
+			continue
+		}
+		split++
+		if strings.Contains(s.String(), "!=") {
+			t.Errorf("Expected no exclusions when splitting %q, got %q", c, s)
+		}
+		if s.IncludePrerelease != c.IncludePrerelease {
+			t.Errorf("Expected splitting %q to keep the options", c)
+		}
+		if !same(c, s) {
+			t.Errorf("Expected %q and its split %q to admit the same versions", c, s)
+		}
+		if sf := s.Fold(); !same(c, sf) {
+			t.Errorf("Expected %q and the fold %q of its split to admit the same versions", c, sf)
+		}
+	}
+
+	if split == 0 {
+		t.Error("Expected some constraints to be split")
+	}
+}
@@ -0,0 +1,84 @@
+package semver
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrMissingStamp is returned when a version stamp is empty, which usually
+// means the binary was built without setting it using -ldflags.
+var ErrMissingStamp = errors.New("Version stamp missing, set it with -ldflags \"-X <package>.<variable>=<version>\"")
+
+// Stamp holds a version injected at build time using -ldflags. For example,
+// given
+//
+//	var version string
+//
+//	var stamp = semver.NewStamp(version)
+//
+// in package main, building with
+//
+//	go build -ldflags "-X main.version=1.2.3"
+//
+// stamps the binary with 1.2.3. The string is parsed once when the stamp is
+// created. Validate should be called at startup so a missing or malformed
+// stamp is reported before the version is used.
+type Stamp struct {
+	raw string
+	v   *Version
+	err error
+}
+
+// NewStamp parses a version stamp. Errors are not returned but kept for
+// Validate so NewStamp can be used to initialize a package variable.
+func NewStamp(s string) *Stamp {
+	st := &Stamp{raw: s}
+
+	if s == "" {
+		st.err = ErrMissingStamp
+		return st
+	}
+
+	v, err := NewVersion(s)
+	if err != nil {
+		st.err = fmt.Errorf("Invalid version stamp %q: %s", s, err)
+		return st
+	}
+	st.v = v
+
+	return st
+}
+
+// Validate returns ErrMissingStamp if the stamp is empty or an error
+// describing why it could not be parsed.
+func (s *Stamp) Validate() error {
+	return s.err
+}
+
+// Version returns the stamped version. It returns nil if the stamp is not
+// valid.
+func (s *Stamp) Version() *Version {
+	return s.v
+}
+
+// MustVersion returns the stamped version and panics if the stamp is not
+// valid.
+func (s *Stamp) MustVersion() *Version {
+	if s.err != nil {
+		panic(s.err)
+	}
+	return s.v
+}
+
+// Raw returns the stamp as it was injected.
+func (s *Stamp) Raw() string {
+	return s.raw
+}
+
+// String returns the stamped version or the raw stamp if it is not valid.
+func (s *Stamp) String() string {
+	if s.v == nil {
+		return s.raw
+	}
+	return s.v.String()
+}
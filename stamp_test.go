@@ -0,0 +1,47 @@
+package semver
+
+import (
+	"testing"
+)
+
+func TestStamp(t *testing.T) {
+	s := NewStamp("v1.2.3-beta.1+abc")
+	if err := s.Validate(); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if s.Version().String() != "1.2.3-beta.1+abc" {
+		t.Errorf("Expected version 1.2.3-beta.1+abc, got %s", s.Version())
+	}
+	if s.MustVersion().Major() != 1 {
+		t.Errorf("Expected major 1, got %d", s.MustVersion().Major())
+	}
+	if s.Raw() != "v1.2.3-beta.1+abc" {
+		t.Errorf("Expected raw v1.2.3-beta.1+abc, got %s", s.Raw())
+	}
+	if s.String() != "1.2.3-beta.1+abc" {
+		t.Errorf("Expected string 1.2.3-beta.1+abc, got %s", s)
+	}
+
+	s = NewStamp("")
+	if err := s.Validate(); err != ErrMissingStamp {
+		t.Errorf("Expected ErrMissingStamp, got %v", err)
+	}
+	if s.Version() != nil {
+		t.Error("Expected no version for a missing stamp")
+	}
+
+	s = NewStamp("not-a-version")
+	if err := s.Validate(); err == nil {
+		t.Error("Expected an error for a malformed stamp")
+	}
+	if s.String() != "not-a-version" {
+		t.Errorf("Expected string not-a-version, got %s", s)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected MustVersion to panic for a malformed stamp")
+		}
+	}()
+	s.MustVersion()
+}
@@ -0,0 +1,89 @@
+package semver
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SuccessionPolicy is a set of rules on which version may be released after
+// another. It is used by ValidateSuccession. The zero value only requires
+// versions to increase.
+type SuccessionPolicy struct {
+	// NoSkippedMajors, NoSkippedMinors and NoSkippedPatches reject a version
+	// that skips a number, such as 1.2.3 followed by 1.4.0, or that does not
+	// reset the lower numbers when a higher one increases, such as 1.2.3
+	// followed by 2.1.0.
+	NoSkippedMajors  bool
+	NoSkippedMinors  bool
+	NoSkippedPatches bool
+
+	// RequirePrerelease requires a release to follow a prerelease of the
+	// same version, so 1.3.0 may only follow 1.3.0-rc.1 or similar.
+	RequirePrerelease bool
+
+	// MajorRequiresCandidate requires a new major release, such as 2.0.0,
+	// to follow a release candidate of it, a prerelease whose first
+	// identifier is CandidateIdentifier.
+	MajorRequiresCandidate bool
+
+	// CandidateIdentifier is the prerelease identifier of release
+	// candidates. It defaults to "rc".
+	CandidateIdentifier string
+}
+
+// ValidateSuccession reports whether next may be released after prev under
+// policy. The error describes the first rule that next breaks. next must
+// always have a higher precedence than prev.
+func ValidateSuccession(prev, next *Version, policy SuccessionPolicy) error {
+	if !next.GreaterThan(prev) {
+		return fmt.Errorf("%s is not greater than %s", next, prev)
+	}
+
+	switch {
+	case next.Major() > prev.Major():
+		if policy.NoSkippedMajors && next.Major() > prev.Major()+1 {
+			return fmt.Errorf("%s skips major version %d after %s", next, prev.Major()+1, prev)
+		}
+		if policy.NoSkippedMinors && next.Minor() != 0 {
+			return fmt.Errorf("%s skips minor version %d.0 after %s", next, next.Major(), prev)
+		}
+		if policy.NoSkippedPatches && next.Patch() != 0 {
+			return fmt.Errorf("%s skips patch version %d.%d.0 after %s", next, next.Major(), next.Minor(), prev)
+		}
+	case next.Minor() > prev.Minor():
+		if policy.NoSkippedMinors && next.Minor() > prev.Minor()+1 {
+			return fmt.Errorf("%s skips minor version %d.%d after %s", next, next.Major(), prev.Minor()+1, prev)
+		}
+		if policy.NoSkippedPatches && next.Patch() != 0 {
+			return fmt.Errorf("%s skips patch version %d.%d.0 after %s", next, next.Major(), next.Minor(), prev)
+		}
+	case next.Patch() > prev.Patch():
+		if policy.NoSkippedPatches && next.Patch() > prev.Patch()+1 {
+			return fmt.Errorf("%s skips patch version %d.%d.%d after %s", next, next.Major(), next.Minor(), prev.Patch()+1, prev)
+		}
+	}
+
+	if next.Prerelease() != "" {
+		return nil
+	}
+
+	// The rest of the rules need prev to be a prerelease of next.
+	same := prev.Prerelease() != "" && prev.Major() == next.Major() &&
+		prev.Minor() == next.Minor() && prev.Patch() == next.Patch()
+
+	if policy.RequirePrerelease && !same {
+		return fmt.Errorf("%s does not follow a prerelease of it, got %s", next, prev)
+	}
+
+	if policy.MajorRequiresCandidate && next.Minor() == 0 && next.Patch() == 0 {
+		id := policy.CandidateIdentifier
+		if id == "" {
+			id = "rc"
+		}
+		if !same || strings.SplitN(prev.Prerelease(), ".", 2)[0] != id {
+			return fmt.Errorf("%s does not follow a release candidate of it, got %s", next, prev)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,60 @@
+package semver
+
+import (
+	"testing"
+)
+
+func TestValidateSuccession(t *testing.T) {
+	strict := SuccessionPolicy{
+		NoSkippedMajors:  true,
+		NoSkippedMinors:  true,
+		NoSkippedPatches: true,
+	}
+	candidates := SuccessionPolicy{MajorRequiresCandidate: true}
+
+	tests := []struct {
+		prev, next string
+		policy     SuccessionPolicy
+		err        string
+	}{
+		{"1.2.3", "1.2.4", SuccessionPolicy{}, ""},
+		{"1.2.3", "1.2.3", SuccessionPolicy{}, "1.2.3 is not greater than 1.2.3"},
+		{"1.2.3", "1.2.0", SuccessionPolicy{}, "1.2.0 is not greater than 1.2.3"},
+		{"1.2.3", "1.2.3-rc.1", SuccessionPolicy{}, "1.2.3-rc.1 is not greater than 1.2.3"},
+		{"1.2.3", "1.5.0", SuccessionPolicy{}, ""},
+		{"1.2.3", "1.2.4", strict, ""},
+		{"1.2.3", "1.3.0", strict, ""},
+		{"1.2.3", "2.0.0", strict, ""},
+		{"1.2.3", "1.2.5", strict, "1.2.5 skips patch version 1.2.4 after 1.2.3"},
+		{"1.2.3", "1.4.0", strict, "1.4.0 skips minor version 1.3 after 1.2.3"},
+		{"1.2.3", "1.3.1", strict, "1.3.1 skips patch version 1.3.0 after 1.2.3"},
+		{"1.2.3", "3.0.0", strict, "3.0.0 skips major version 2 after 1.2.3"},
+		{"1.2.3", "2.1.0", strict, "2.1.0 skips minor version 2.0 after 1.2.3"},
+		{"1.2.3", "2.0.1", strict, "2.0.1 skips patch version 2.0.0 after 1.2.3"},
+		{"1.2.3", "1.4.0-rc.1", strict, "1.4.0-rc.1 skips minor version 1.3 after 1.2.3"},
+		{"1.3.0-rc.1", "1.3.0", strict, ""},
+		{"1.2.3", "1.3.0-rc.1", SuccessionPolicy{RequirePrerelease: true}, ""},
+		{"1.3.0-rc.1", "1.3.0", SuccessionPolicy{RequirePrerelease: true}, ""},
+		{"1.2.3", "1.3.0", SuccessionPolicy{RequirePrerelease: true}, "1.3.0 does not follow a prerelease of it, got 1.2.3"},
+		{"1.2.0-rc.1", "1.3.0", SuccessionPolicy{RequirePrerelease: true}, "1.3.0 does not follow a prerelease of it, got 1.2.0-rc.1"},
+		{"1.2.3", "1.3.0", candidates, ""},
+		{"1.2.3", "2.0.0-rc.1", candidates, ""},
+		{"2.0.0-rc.1", "2.0.0", candidates, ""},
+		{"1.2.3", "2.0.0", candidates, "2.0.0 does not follow a release candidate of it, got 1.2.3"},
+		{"2.0.0-beta.1", "2.0.0", candidates, "2.0.0 does not follow a release candidate of it, got 2.0.0-beta.1"},
+		{"2.0.0-beta.1", "2.0.0", SuccessionPolicy{MajorRequiresCandidate: true, CandidateIdentifier: "beta"}, ""},
+	}
+
+	for _, tc := range tests {
+		err := ValidateSuccession(MustParse(tc.prev), MustParse(tc.next), tc.policy)
+		if tc.err == "" {
+			if err != nil {
+				t.Errorf("Expected %s to follow %s, got %s", tc.next, tc.prev, err)
+			}
+			continue
+		}
+		if err == nil || err.Error() != tc.err {
+			t.Errorf("Expected error %q for %s after %s, got %v", tc.err, tc.next, tc.prev, err)
+		}
+	}
+}
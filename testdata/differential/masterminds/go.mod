@@ -0,0 +1,5 @@
+module github.com/Masterminds/semver/v3/testdata/differential/masterminds
+
+go 1.21
+
+require github.com/Masterminds/semver/v3 v3.5.0
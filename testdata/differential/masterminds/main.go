@@ -0,0 +1,63 @@
+// Command masterminds adds the results Masterminds/semver gives to the corpus
+// written by node.js. It reads the corpus on standard input and writes it,
+// one case per line, on standard output. The version of Masterminds/semver
+// recorded is the one required in go.mod.
+package main
+
+import (
+	"encoding/json"
+	"log"
+	"os"
+
+	"github.com/Masterminds/semver/v3"
+)
+
+type differentialCase struct {
+	Op          string      `json:"op"`
+	Version     string      `json:"version,omitempty"`
+	A           string      `json:"a,omitempty"`
+	B           string      `json:"b,omitempty"`
+	Constraint  string      `json:"range,omitempty"`
+	Node        interface{} `json:"node"`
+	Masterminds interface{} `json:"masterminds"`
+}
+
+func main() {
+	var cases []differentialCase
+	if err := json.NewDecoder(os.Stdin).Decode(&cases); err != nil {
+		log.Fatalf("Error reading corpus: %s", err)
+	}
+
+	os.Stdout.WriteString("[\n")
+	for i := range cases {
+		tc := &cases[i]
+		switch tc.Op {
+		case "valid":
+			_, err := semver.StrictNewVersion(tc.Version)
+			tc.Masterminds = err == nil
+		case "compare":
+			a, errA := semver.NewVersion(tc.A)
+			b, errB := semver.NewVersion(tc.B)
+			if errA == nil && errB == nil {
+				tc.Masterminds = a.Compare(b)
+			}
+		case "satisfies":
+			v, errV := semver.NewVersion(tc.Version)
+			c, errC := semver.NewConstraint(tc.Constraint)
+			if errV == nil && errC == nil {
+				tc.Masterminds = c.Check(v)
+			}
+		}
+
+		line, err := json.Marshal(tc)
+		if err != nil {
+			log.Fatalf("Error writing corpus: %s", err)
+		}
+		os.Stdout.Write(line)
+		if i < len(cases)-1 {
+			os.Stdout.WriteString(",")
+		}
+		os.Stdout.WriteString("\n")
+	}
+	os.Stdout.WriteString("]\n")
+}
@@ -0,0 +1,136 @@
+// Generates the inputs of testdata/differential.json and records the results
+// node-semver gives for them. The output is read by masterminds/main.go,
+// which adds the Masterminds results. Run "make differential" from the
+// repository root to regenerate the corpus.
+//
+// The SEMVER environment variable is the path to the node-semver package to
+// use. It defaults to "semver", resolved the usual way.
+'use strict'
+
+const semver = require(process.env.SEMVER || 'semver')
+
+// mulberry32 is a small seeded generator so the corpus is reproducible.
+let seed = 724
+function rand () {
+  seed = (seed + 0x6d2b79f5) | 0
+  let t = seed
+  t = Math.imul(t ^ (t >>> 15), t | 1)
+  t ^= t + Math.imul(t ^ (t >>> 7), t | 61)
+  return ((t ^ (t >>> 14)) >>> 0) / 4294967296
+}
+const intn = n => Math.floor(rand() * n)
+const pick = a => a[intn(a.length)]
+
+const words = ['alpha', 'beta', 'rc', 'pre', 'dev', 'build', 'x-y', '0a', 'A', 'a-']
+
+function num () {
+  switch (intn(10)) {
+    case 0: return '0' + intn(10)
+    case 1: return String(10 + intn(90))
+    case 2: return pick(['18446744073709551615', '9007199254740991', '99999999999999999999'])
+    default: return String(intn(4))
+  }
+}
+
+function ids () {
+  const out = []
+  for (let i = intn(3) + 1; i > 0; i--) {
+    switch (intn(12)) {
+      case 0: out.push(''); break
+      case 1: out.push('é'); break
+      default: out.push(rand() < 0.5 ? pick(words) : num())
+    }
+  }
+  return out.join('.')
+}
+
+// version returns a version string that is usually valid SemVer but is
+// sometimes partial, prefixed or otherwise malformed.
+function version (valid) {
+  let s = [0, 1, 2].map(() => valid ? String(intn(4)) : num()).join('.')
+  if (rand() < 0.4) s += '-' + (valid ? pick(words) + (rand() < 0.5 ? '.' + intn(12) : '') : ids())
+  if (rand() < 0.2) s += '+' + (valid ? pick(words) : ids())
+  if (!valid) {
+    switch (intn(8)) {
+      case 0: s = 'v' + s; break
+      case 1: s = s.split('.').slice(0, 1 + intn(2)).join('.'); break
+      case 2: s += '.' + intn(4); break
+      case 3: s = ' ' + s; break
+    }
+  }
+  return s
+}
+
+const ops = ['', '=', '>', '<', '>=', '<=', '~', '^']
+const wild = ['x', 'X', '*']
+
+function partial () {
+  const parts = [String(intn(4)), String(intn(4)), String(intn(4))]
+  const n = intn(6)
+  if (n === 0) return parts.slice(0, 1 + intn(2)).join('.')
+  if (n === 1) {
+    const i = intn(3)
+    for (let j = i; j < 3; j++) parts[j] = pick(wild)
+    return parts.join('.')
+  }
+  let s = parts.join('.')
+  if (rand() < 0.3) s += '-' + pick(words) + (rand() < 0.5 ? '.' + intn(4) : '')
+  return s
+}
+
+function range () {
+  const groups = []
+  for (let i = intn(3) + 1; i > 0; i--) {
+    // node-semver only accepts a hyphen range as a whole group.
+    if (intn(6) === 0) {
+      groups.push(partial() + ' - ' + partial())
+      continue
+    }
+    const terms = []
+    for (let j = intn(3) + 1; j > 0; j--) {
+      terms.push(pick(ops) + partial())
+    }
+    groups.push(terms.join(' '))
+  }
+  return groups.join(' || ')
+}
+
+const cases = []
+
+// The precedence examples from the SemVer specification, in order.
+const spec = ['1.0.0-alpha', '1.0.0-alpha.1', '1.0.0-alpha.beta', '1.0.0-beta',
+  '1.0.0-beta.2', '1.0.0-beta.11', '1.0.0-rc.1', '1.0.0', '2.0.0', '2.1.0', '2.1.1']
+for (let i = 0; i < spec.length; i++) {
+  for (let j = 0; j < spec.length; j++) {
+    cases.push({ op: 'compare', a: spec[i], b: spec[j], node: semver.compare(spec[i], spec[j]) })
+  }
+}
+
+for (let i = 0; i < 1500; i++) {
+  const v = version(rand() < 0.3)
+  cases.push({ op: 'valid', version: v, node: semver.valid(v) !== null })
+}
+
+for (let i = 0; i < 1500; i++) {
+  const a = version(true)
+  const b = rand() < 0.3 ? a.replace(/\+.*$/, '') : version(true)
+  cases.push({ op: 'compare', a, b, node: semver.compare(a, b) })
+}
+
+for (let i = 0; i < 3000; i++) {
+  const r = range()
+  let v = version(true)
+  if (rand() < 0.3) {
+    // The lowest version in the range exercises its boundary.
+    const min = semver.minVersion(r)
+    if (min !== null) v = min.version
+  }
+  cases.push({
+    op: 'satisfies',
+    version: v,
+    range: r,
+    node: semver.validRange(r) === null ? null : semver.satisfies(v, r)
+  })
+}
+
+process.stdout.write(JSON.stringify(cases))
@@ -0,0 +1,52 @@
+package semver
+
+// MaxTracker finds the highest version satisfying some constraints in a
+// stream of tags, such as pages of a registry API or the output of git
+// ls-remote, without holding the tags in memory. Only the best version seen
+// so far is kept. A MaxTracker is not safe for concurrent use.
+type MaxTracker struct {
+	m    *Matcher
+	best *Version
+}
+
+// NewMaxTracker returns a MaxTracker for versions satisfying cs. If cs is nil
+// every version is considered. Changes to the options on cs after
+// NewMaxTracker returns do not affect the MaxTracker.
+func NewMaxTracker(cs *Constraints) *MaxTracker {
+	t := &MaxTracker{}
+	if cs != nil {
+		t.m = cs.Compile()
+	}
+	return t
+}
+
+// Consider parses tag with ParseLenient and keeps the version if it satisfies
+// the constraints and is higher than the best one so far. When versions have
+// equal precedence the first one is kept. The error from ParseLenient is
+// returned for a tag that is not a version, and the tag is skipped.
+func (t *MaxTracker) Consider(tag string) error {
+	v, _, err := ParseLenient(tag)
+	if err != nil {
+		return err
+	}
+	t.ConsiderVersion(v)
+	return nil
+}
+
+// ConsiderVersion keeps v if it satisfies the constraints and is higher than
+// the best version so far.
+func (t *MaxTracker) ConsiderVersion(v *Version) {
+	if t.best != nil && !v.GreaterThan(t.best) {
+		return
+	}
+	if t.m != nil && !t.m.Check(v) {
+		return
+	}
+	t.best = v
+}
+
+// Best returns the highest version considered that satisfies the
+// constraints, or nil if there is none.
+func (t *MaxTracker) Best() *Version {
+	return t.best
+}
@@ -0,0 +1,67 @@
+package semver
+
+import (
+	"testing"
+)
+
+func TestMaxTracker(t *testing.T) {
+	cs, err := NewConstraint("^1.2")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tr := NewMaxTracker(cs)
+	if b := tr.Best(); b != nil {
+		t.Errorf("Expected no best version, got %q", b)
+	}
+
+	tags := []string{"v1.2.0", "1.3.0-rc.1", "release-candidate", "V1.4", "2.0.0", "1.4.0", "v1.3.9"}
+	var errs int
+	for _, tag := range tags {
+		if err := tr.Consider(tag); err != nil {
+			errs++
+		}
+	}
+
+	if errs != 1 {
+		t.Errorf("Expected 1 error, got %d", errs)
+	}
+	if b := tr.Best(); b == nil || b.Original() != "V1.4" {
+		t.Errorf("Expected best version V1.4, got %v", b)
+	}
+
+	// nil constraints consider every version.
+	tr = NewMaxTracker(nil)
+	for _, tag := range tags {
+		_ = tr.Consider(tag)
+	}
+	if b := tr.Best(); b == nil || b.String() != "2.0.0" {
+		t.Errorf("Expected best version 2.0.0, got %v", b)
+	}
+}
+
+func TestMaxTrackerMatchesLatest(t *testing.T) {
+	vs := Collection{
+		MustParse("1.0.0"),
+		MustParse("1.2.3+a"),
+		MustParse("1.2.3+b"),
+		MustParse("1.2.4-beta"),
+		MustParse("0.9.0"),
+	}
+	for _, c := range []string{">=1.0.0", "<1.2.3", "~1.2.3-0", "^3"} {
+		cs, err := NewConstraint(c)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		tr := NewMaxTracker(cs)
+		for _, v := range vs {
+			tr.ConsiderVersion(v)
+		}
+
+		latest, _ := vs.Latest(cs)
+		if tr.Best() != latest {
+			t.Errorf("Expected best version for %q to be %v, got %v", c, latest, tr.Best())
+		}
+	}
+}
@@ -6,9 +6,11 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"math"
 	"regexp"
 	"strconv"
 	"strings"
+	"unicode/utf8"
 )
 
 // The compiled version of the regex created at init() is cached here so it
@@ -36,8 +38,54 @@ var (
 
 	// ErrInvalidPrerelease is returned when the pre-release is an invalid format
 	ErrInvalidPrerelease = errors.New("Invalid Prerelease string")
+
+	// ErrSegmentOverflow is returned when a major, minor, or patch segment is
+	// too large to be represented as a uint64.
+	ErrSegmentOverflow = errors.New("Version segment overflows uint64")
+
+	// ErrNonASCII is returned when a version contains characters outside of
+	// ASCII. These are often lookalikes, such as full-width digits or Unicode
+	// hyphens, copied from documents.
+	ErrNonASCII = errors.New("Non-ASCII characters in version")
+
+	// ErrVersionTooLong is returned when a version string is longer than
+	// MaxVersionLength.
+	ErrVersionTooLong = errors.New("Version string too long")
+
+	// ErrTooManyIdentifiers is returned when a prerelease has more dot
+	// separated identifiers than MaxPrereleaseIdentifiers.
+	ErrTooManyIdentifiers = errors.New("Too many prerelease identifiers")
+
+	// ErrNoPrerelease is returned by IncPrerelease when the version is not a
+	// prerelease.
+	ErrNoPrerelease = errors.New("Version has no prerelease")
 )
 
+// Limits on the input accepted by the parsers. Version and constraint strings
+// often come from untrusted sources so these bound the work done on each one.
+// A limit of 0 or less disables it, and all of them are disabled by default.
+// Programs parsing untrusted input can set them, such as MaxVersionLength to
+// 256 and MaxPrereleaseIdentifiers to 64. The limits are read on every parse
+// and should be set before parsing begins.
+var (
+	// MaxVersionLength is the maximum length in bytes of a version string.
+	MaxVersionLength = 0
+
+	// MaxPrereleaseIdentifiers is the maximum number of dot separated
+	// identifiers in a prerelease.
+	MaxPrereleaseIdentifiers = 0
+)
+
+// MetadataComparator, when set, is consulted by CompareTotal to order two
+// versions with the same precedence but different metadata. It is passed the
+// metadata of both versions, either of which may be empty, and returns a
+// negative number, 0, or a positive number in the manner of Compare. This
+// lets packaging tools order extensions carried in the metadata, such as a
+// distribution revision like 1ubuntu2. When it returns 0, or is nil, the
+// default metadata ordering is used. It should be set before comparisons
+// begin.
+var MetadataComparator func(a, b string) int
+
 // semVerRegex is the regular expression used to parse a semantic version.
 const semVerRegex string = `v?([0-9]+)(\.[0-9]+)?(\.[0-9]+)?` +
 	`(-([0-9A-Za-z\-]+(\.[0-9A-Za-z\-]+)*))?` +
@@ -71,33 +119,50 @@ func StrictNewVersion(v string) (*Version, error) {
 		return nil, ErrEmptyString
 	}
 
-	// Split the parts into [0]major, [1]minor, and [2]patch,prerelease,build
-	parts := strings.SplitN(v, ".", 3)
-	if len(parts) != 3 {
+	if MaxVersionLength > 0 && len(v) > MaxVersionLength {
+		return nil, ErrVersionTooLong
+	}
+
+	if !isASCII(v) {
+		return nil, ErrNonASCII
+	}
+
+	// Split the parts into [0]major, [1]minor, and [2]patch,prerelease,build.
+	// The parts are sliced out of the string rather than split into a slice
+	// so that parsing allocates nothing but the Version.
+	i := strings.IndexByte(v, '.')
+	if i == -1 {
+		return nil, ErrInvalidSemVer
+	}
+	j := strings.IndexByte(v[i+1:], '.')
+	if j == -1 {
 		return nil, ErrInvalidSemVer
 	}
+	j += i + 1
+	parts := [3]string{v[:i], v[i+1 : j], v[j+1:]}
 
 	sv := &Version{
 		original: v,
 	}
 
-	// check for prerelease or build metadata
-	var extra []string
-	if strings.ContainsAny(parts[2], "-+") {
-		// Start with the build metadata first as it needs to be on the right
-		extra = strings.SplitN(parts[2], "+", 2)
-		if len(extra) > 1 {
-			// build metadata found
-			sv.metadata = extra[1]
-			parts[2] = extra[0]
+	// check for prerelease or build metadata. Start with the build metadata
+	// first as it needs to be on the right
+	if k := strings.IndexByte(parts[2], '+'); k != -1 {
+		// build metadata found
+		sv.metadata = parts[2][k+1:]
+		if sv.metadata == "" {
+			return nil, ErrInvalidMetadata
 		}
+		parts[2] = parts[2][:k]
+	}
 
-		extra = strings.SplitN(parts[2], "-", 2)
-		if len(extra) > 1 {
-			// prerelease found
-			sv.pre = extra[1]
-			parts[2] = extra[0]
+	if k := strings.IndexByte(parts[2], '-'); k != -1 {
+		// prerelease found
+		sv.pre = parts[2][k+1:]
+		if sv.pre == "" {
+			return nil, ErrInvalidPrerelease
 		}
+		parts[2] = parts[2][:k]
 	}
 
 	// Validate the number segments are valid. This includes only having positive
@@ -114,17 +179,17 @@ func StrictNewVersion(v string) (*Version, error) {
 
 	// Extract the major, minor, and patch elements onto the returned Version
 	var err error
-	sv.major, err = strconv.ParseUint(parts[0], 10, 64)
+	sv.major, err = parseSegment(parts[0])
 	if err != nil {
 		return nil, err
 	}
 
-	sv.minor, err = strconv.ParseUint(parts[1], 10, 64)
+	sv.minor, err = parseSegment(parts[1])
 	if err != nil {
 		return nil, err
 	}
 
-	sv.patch, err = strconv.ParseUint(parts[2], 10, 64)
+	sv.patch, err = parseSegment(parts[2])
 	if err != nil {
 		return nil, err
 	}
@@ -153,7 +218,27 @@ func StrictNewVersion(v string) (*Version, error) {
 // an error if unable to parse the version. If the version is SemVer-ish it
 // attempts to convert it to SemVer. If you want  to validate it was a strict
 // semantic version at parse time see StrictNewVersion().
+//
+// Non-ASCII input is rejected with ErrNonASCII. Use ParseLenient to convert
+// common lookalikes, such as full-width digits and Unicode hyphens, to their
+// ASCII equivalents before parsing.
 func NewVersion(v string) (*Version, error) {
+	if MaxVersionLength > 0 && len(v) > MaxVersionLength {
+		return nil, ErrVersionTooLong
+	}
+
+	// Most versions are valid semantic versions, other than a leading v, and
+	// the strict parser handles those without the allocations of the regular
+	// expression. Anything it rejects is parsed the slower way.
+	if sv, err := StrictNewVersion(strings.TrimPrefix(v, "v")); err == nil {
+		sv.original = v
+		return sv, nil
+	}
+
+	if !isASCII(v) {
+		return nil, ErrNonASCII
+	}
+
 	m := versionRegex.FindStringSubmatch(v)
 	if m == nil {
 		return nil, ErrInvalidSemVer
@@ -166,24 +251,24 @@ func NewVersion(v string) (*Version, error) {
 	}
 
 	var err error
-	sv.major, err = strconv.ParseUint(m[1], 10, 64)
+	sv.major, err = parseSegment(m[1])
 	if err != nil {
-		return nil, fmt.Errorf("Error parsing version segment: %s", err)
+		return nil, err
 	}
 
 	if m[2] != "" {
-		sv.minor, err = strconv.ParseUint(strings.TrimPrefix(m[2], "."), 10, 64)
+		sv.minor, err = parseSegment(strings.TrimPrefix(m[2], "."))
 		if err != nil {
-			return nil, fmt.Errorf("Error parsing version segment: %s", err)
+			return nil, err
 		}
 	} else {
 		sv.minor = 0
 	}
 
 	if m[3] != "" {
-		sv.patch, err = strconv.ParseUint(strings.TrimPrefix(m[3], "."), 10, 64)
+		sv.patch, err = parseSegment(strings.TrimPrefix(m[3], "."))
 		if err != nil {
-			return nil, fmt.Errorf("Error parsing version segment: %s", err)
+			return nil, err
 		}
 	} else {
 		sv.patch = 0
@@ -207,6 +292,47 @@ func NewVersion(v string) (*Version, error) {
 	return sv, nil
 }
 
+// FromParts creates a Version from its parts. The pre-release and metadata
+// are given as their identifiers, without the dots between them, and are
+// validated as StrictNewVersion would. Either may be empty.
+func FromParts(major, minor, patch uint64, pre, metadata []string) (*Version, error) {
+	sv := &Version{
+		major:    major,
+		minor:    minor,
+		patch:    patch,
+		pre:      strings.Join(pre, "."),
+		metadata: strings.Join(metadata, "."),
+	}
+
+	// The identifiers are checked rather than the joined strings so that a
+	// single empty identifier, or one containing a dot, is rejected.
+	if len(pre) > 0 {
+		if err := validatePrerelease(sv.pre); err != nil {
+			return nil, err
+		}
+		if strings.Count(sv.pre, ".") != len(pre)-1 {
+			return nil, ErrInvalidPrerelease
+		}
+	}
+	if len(metadata) > 0 {
+		if err := validateMetadata(sv.metadata); err != nil {
+			return nil, err
+		}
+		if strings.Count(sv.metadata, ".") != len(metadata)-1 {
+			return nil, ErrInvalidMetadata
+		}
+	}
+
+	sv.original = sv.String()
+	return sv, nil
+}
+
+// ParseBytes parses a version from a byte slice in the same way as
+// NewVersion. The bytes are copied so b may be reused once it returns.
+func ParseBytes(b []byte) (*Version, error) {
+	return NewVersion(string(b))
+}
+
 // MustParse parses a given version and panics on error.
 func MustParse(v string) *Version {
 	sv, err := NewVersion(v)
@@ -265,6 +391,35 @@ func (v Version) Metadata() string {
 	return v.metadata
 }
 
+// PrereleaseIdentifiers returns the dot separated identifiers of the
+// pre-release version, such as ["rc", "1"] for 1.2.3-rc.1. It is nil when
+// there is no pre-release.
+func (v Version) PrereleaseIdentifiers() []string {
+	if v.pre == "" {
+		return nil
+	}
+	return strings.Split(v.pre, ".")
+}
+
+// PrereleaseTag returns the first identifier of the pre-release, such as
+// "beta" for 1.2.3-beta.2, which names the release channel the version is
+// on. It is "" when there is no pre-release.
+func (v Version) PrereleaseTag() string {
+	if i := strings.IndexByte(v.pre, '.'); i != -1 {
+		return v.pre[:i]
+	}
+	return v.pre
+}
+
+// MetadataIdentifiers returns the dot separated identifiers of the metadata.
+// It is nil when there is no metadata.
+func (v Version) MetadataIdentifiers() []string {
+	if v.metadata == "" {
+		return nil
+	}
+	return strings.Split(v.metadata, ".")
+}
+
 // originalVPrefix returns the original 'v' prefix if any.
 func (v Version) originalVPrefix() string {
 
@@ -330,6 +485,33 @@ func (v Version) IncMajor() Version {
 	return vNext
 }
 
+// IncPrerelease produces the next prerelease of the same version.
+// Increments the last prerelease identifier when it is numeric, so rc.1
+// becomes rc.2, and otherwise appends a .1 identifier, so rc becomes rc.1.
+// Unsets metadata.
+// Returns ErrNoPrerelease when the version is not a prerelease.
+func (v Version) IncPrerelease() (Version, error) {
+	if v.pre == "" {
+		return v, ErrNoPrerelease
+	}
+
+	pre := v.pre
+	i := strings.LastIndexByte(pre, '.') + 1
+	if last := pre[i:]; containsOnly(last, num) {
+		n, err := strconv.ParseUint(last, 10, 64)
+		if err != nil || n == math.MaxUint64 {
+			return v, ErrSegmentOverflow
+		}
+		pre = pre[:i] + strconv.FormatUint(n+1, 10)
+	} else {
+		pre += ".1"
+	}
+
+	vNext := v
+	vNext.metadata = ""
+	return vNext.SetPrerelease(pre)
+}
+
 // SetPrerelease defines the prerelease value.
 // Value must not include the required 'hyphen' prefix.
 func (v Version) SetPrerelease(prerelease string) (Version, error) {
@@ -375,6 +557,13 @@ func (v *Version) Equal(o *Version) bool {
 	return v.Compare(o) == 0
 }
 
+// Identical tests if two versions are the same including their build
+// metadata, so 1.0.0+linux is not identical to 1.0.0+darwin. A leading v in
+// the original strings is not taken into account.
+func (v *Version) Identical(o *Version) bool {
+	return v.Equal(o) && v.metadata == o.metadata
+}
+
 // Compare compares this version to another one. It returns -1, 0, or 1 if
 // the version smaller, equal, or larger than the other version.
 //
@@ -383,6 +572,11 @@ func (v *Version) Equal(o *Version) bool {
 // prereleases. If you want to work with ranges using typical range syntaxes that
 // skip prereleases if the range is not looking for them use constraints.
 func (v *Version) Compare(o *Version) int {
+	// Shared versions, such as those returned by an Interner, are equal.
+	if v == o {
+		return 0
+	}
+
 	// Compare the major, minor, and patch version for differences. If a
 	// difference is found return the comparison.
 	if d := compareSegment(v.Major(), o.Major()); d != 0 {
@@ -412,6 +606,52 @@ func (v *Version) Compare(o *Version) int {
 	return comparePrerelease(ps, po)
 }
 
+// CompareTotal compares this version to another one in the same way as Compare
+// but does not ignore build metadata. When two versions have the same
+// precedence a version without metadata is lower than one with metadata, and
+// metadata identifiers are then compared in the same manner as prerelease
+// identifiers with a final byte-wise comparison. Versions only compare as 0
+// when their canonical strings are identical. This makes sorts that use it
+// reproducible regardless of the order the versions were provided in. The
+// metadata ordering can be extended with MetadataComparator.
+func (v *Version) CompareTotal(o *Version) int {
+	if v == o {
+		return 0
+	}
+	if d := v.Compare(o); d != 0 {
+		return d
+	}
+
+	ms := v.metadata
+	mo := o.Metadata()
+
+	if ms == mo {
+		return 0
+	}
+	if MetadataComparator != nil {
+		if d := MetadataComparator(ms, mo); d != 0 {
+			return d
+		}
+	}
+	if ms == "" {
+		return -1
+	}
+	if mo == "" {
+		return 1
+	}
+
+	if d := comparePrerelease(ms, mo); d != 0 {
+		return d
+	}
+
+	// Metadata such as 01 and 1 compare equal numerically so fall back to the
+	// bytes to keep the order total.
+	if ms < mo {
+		return -1
+	}
+	return 1
+}
+
 // UnmarshalJSON implements JSON.Unmarshaler interface.
 func (v *Version) UnmarshalJSON(b []byte) error {
 	var s string
@@ -436,10 +676,66 @@ func (v Version) MarshalJSON() ([]byte, error) {
 	return json.Marshal(v.String())
 }
 
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+func (v *Version) UnmarshalText(text []byte) error {
+	temp, err := NewVersion(string(text))
+	if err != nil {
+		return err
+	}
+	v.major = temp.major
+	v.minor = temp.minor
+	v.patch = temp.patch
+	v.pre = temp.pre
+	v.metadata = temp.metadata
+	v.original = temp.original
+	return nil
+}
+
+// MarshalText implements the encoding.TextMarshaler interface.
+func (v Version) MarshalText() ([]byte, error) {
+	return []byte(v.String()), nil
+}
+
+// UnmarshalYAML implements the yaml.Unmarshaler interface of go-yaml, which
+// calls it with a function that decodes the YAML value into its argument.
+// The error for a version that cannot be parsed includes the string.
+func (v *Version) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var s string
+	if err := unmarshal(&s); err != nil {
+		return err
+	}
+	if err := v.UnmarshalText([]byte(s)); err != nil {
+		return fmt.Errorf("invalid version %q: %s", s, err)
+	}
+	return nil
+}
+
+// MarshalYAML implements the yaml.Marshaler interface of go-yaml. The
+// version is written as a string.
+func (v Version) MarshalYAML() (interface{}, error) {
+	return v.String(), nil
+}
+
+// Set implements the flag.Value interface so a version can be used as a
+// command line flag. The version is parsed with NewVersion.
+func (v *Version) Set(s string) error {
+	return v.UnmarshalText([]byte(s))
+}
+
+// Type returns the name of the flag type for the pflag.Value interface.
+func (v *Version) Type() string {
+	return "version"
+}
+
 // Scan implements the SQL.Scanner interface.
 func (v *Version) Scan(value interface{}) error {
 	var s string
-	s, _ = value.(string)
+	switch t := value.(type) {
+	case string:
+		s = t
+	case []byte:
+		s = string(t)
+	}
 	temp, err := NewVersion(s)
 	if err != nil {
 		return err
@@ -458,6 +754,21 @@ func (v Version) Value() (driver.Value, error) {
 	return v.String(), nil
 }
 
+// parseSegment converts a major, minor, or patch segment that has already been
+// checked to contain only digits. The only way this can fail is when the value
+// does not fit in a uint64, which is reported rather than silently wrapped.
+func parseSegment(s string) (uint64, error) {
+	n, err := strconv.ParseUint(s, 10, 64)
+	if err != nil {
+		if ne, ok := err.(*strconv.NumError); ok && ne.Err == strconv.ErrRange {
+			return 0, ErrSegmentOverflow
+		}
+		return 0, fmt.Errorf("Error parsing version segment: %s", err)
+	}
+
+	return n, nil
+}
+
 func compareSegment(v, o uint64) int {
 	if v < o {
 		return -1
@@ -471,34 +782,14 @@ func compareSegment(v, o uint64) int {
 
 func comparePrerelease(v, o string) int {
 
-	// split the prelease versions by their part. The separator, per the spec,
-	// is a .
-	sparts := strings.Split(v, ".")
-	oparts := strings.Split(o, ".")
-
-	// Find the longer length of the parts to know how many loop iterations to
-	// go through.
-	slen := len(sparts)
-	olen := len(oparts)
-
-	l := slen
-	if olen > slen {
-		l = olen
-	}
-
-	// Iterate over each part of the prereleases to compare the differences.
-	for i := 0; i < l; i++ {
-		// Since the lentgh of the parts can be different we need to create
-		// a placeholder. This is to avoid out of bounds issues.
-		stemp := ""
-		if i < slen {
-			stemp = sparts[i]
-		}
-
-		otemp := ""
-		if i < olen {
-			otemp = oparts[i]
-		}
+	// Walk the parts of both prerelease versions together. The separator, per
+	// the spec, is a . and the parts are sliced out of the strings rather than
+	// split into slices so that comparing does not allocate. When one has
+	// fewer parts an empty placeholder is compared with the other's part.
+	for v != "" || o != "" {
+		var stemp, otemp string
+		stemp, v = nextIdentifier(v)
+		otemp, o = nextIdentifier(o)
 
 		d := comparePrePart(stemp, otemp)
 		if d != 0 {
@@ -512,6 +803,15 @@ func comparePrerelease(v, o string) int {
 	return 0
 }
 
+// nextIdentifier returns the first dot separated identifier in s and the rest
+// of s after the dot. Both are empty when s is.
+func nextIdentifier(s string) (id, rest string) {
+	if i := strings.IndexByte(s, '.'); i != -1 {
+		return s[:i], s[i+1:]
+	}
+	return s, ""
+}
+
 func comparePrePart(s, o string) int {
 	// Fastpath if they are equal
 	if s == o {
@@ -538,31 +838,80 @@ func comparePrePart(s, o string) int {
 	// cases like this we need to detect numbers and compare them. According
 	// to the semver spec, numbers are always positive. If there is a - at the
 	// start like -99 this is to be evaluated as an alphanum. numbers always
-	// have precedence over alphanum. Parsing as Uints because negative numbers
-	// are ignored.
+	// have precedence over alphanum. Numeric identifiers are compared by their
+	// digits rather than parsed so identifiers too large for a uint64 are
+	// still ordered numerically.
 
-	oi, n1 := strconv.ParseUint(o, 10, 64)
-	si, n2 := strconv.ParseUint(s, 10, 64)
+	sn := containsOnly(s, num)
+	on := containsOnly(o, num)
 
 	// The case where both are strings compare the strings
-	if n1 != nil && n2 != nil {
+	if !sn && !on {
 		if s > o {
 			return 1
 		}
 		return -1
-	} else if n1 != nil {
+	} else if !on {
 		// o is a string and s is a number
 		return -1
-	} else if n2 != nil {
+	} else if !sn {
 		// s is a string and o is a number
 		return 1
 	}
 	// Both are numbers
-	if si > oi {
+	return compareNumeric(s, o)
+}
+
+// compareNumeric compares two strings of ASCII digits by numeric value.
+// Leading zeros are ignored so a longer string is only larger when it has
+// more significant digits.
+func compareNumeric(s, o string) int {
+	s = strings.TrimLeft(s, "0")
+	o = strings.TrimLeft(o, "0")
+
+	if len(s) != len(o) {
+		if len(s) > len(o) {
+			return 1
+		}
+		return -1
+	}
+	if s > o {
 		return 1
 	}
-	return -1
+	if s < o {
+		return -1
+	}
+	return 0
+}
 
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
+
+// normalizeASCII replaces characters that are commonly mistaken for the ASCII
+// characters used in versions with those characters. Zero width characters
+// and byte order marks are removed. Anything else is left as is.
+func normalizeASCII(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= '\uFF01' && r <= '\uFF5E':
+			// Full-width forms of the printable ASCII characters
+			return r - '\uFF01' + '!'
+		case r == '\u2010', r == '\u2011', r == '\u2012', r == '\u2013',
+			r == '\u2212', r == '\uFE63':
+			// Hyphens, dashes, and minus signs
+			return '-'
+		case r == '\u200B', r == '\u200C', r == '\u200D', r == '\u2060',
+			r == '\uFEFF':
+			return -1
+		}
+		return r
+	}, s)
 }
 
 // Like strings.ContainsAny but does an only instead of any.
@@ -577,18 +926,32 @@ func containsOnly(s string, comp string) bool {
 // Numeric identifiers MUST NOT include leading zeroes.". These segments can
 // be dot separated.
 func validatePrerelease(p string) error {
-	eparts := strings.Split(p, ".")
-	for _, p := range eparts {
-		if containsOnly(p, num) {
-			if len(p) > 1 && p[0] == '0' {
+	if MaxPrereleaseIdentifiers > 0 && strings.Count(p, ".") >= MaxPrereleaseIdentifiers {
+		return ErrTooManyIdentifiers
+	}
+	for {
+		i := strings.IndexByte(p, '.')
+		part := p
+		if i != -1 {
+			part = p[:i]
+		}
+
+		if part == "" {
+			return ErrInvalidPrerelease
+		}
+		if containsOnly(part, num) {
+			if len(part) > 1 && part[0] == '0' {
 				return ErrSegmentStartsZero
 			}
-		} else if !containsOnly(p, allowed) {
+		} else if !containsOnly(part, allowed) {
 			return ErrInvalidPrerelease
 		}
-	}
 
-	return nil
+		if i == -1 {
+			return nil
+		}
+		p = p[i+1:]
+	}
 }
 
 // From the spec, "Build metadata MAY be denoted by
@@ -596,11 +959,20 @@ func validatePrerelease(p string) error {
 // following the patch or pre-release version. Identifiers MUST comprise only
 // ASCII alphanumerics and hyphen [0-9A-Za-z-]. Identifiers MUST NOT be empty."
 func validateMetadata(m string) error {
-	eparts := strings.Split(m, ".")
-	for _, p := range eparts {
-		if !containsOnly(p, allowed) {
+	for {
+		i := strings.IndexByte(m, '.')
+		part := m
+		if i != -1 {
+			part = m[:i]
+		}
+
+		if part == "" || !containsOnly(part, allowed) {
 			return ErrInvalidMetadata
 		}
+
+		if i == -1 {
+			return nil
+		}
+		m = m[i+1:]
 	}
-	return nil
 }
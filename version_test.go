@@ -3,7 +3,11 @@ package semver
 import (
 	"database/sql"
 	"encoding/json"
+	"flag"
 	"fmt"
+	"io/ioutil"
+	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -41,6 +45,12 @@ func TestStrictNewVersion(t *testing.T) {
 		{"1.2.2147483648", false},
 		{"1.2147483648.3", false},
 		{"2147483648.3.0", false},
+		{"1.2.3-", true},
+		{"1.2.3+", true},
+		{"1.2.3-+meta", true},
+		{"1.2.3-alpha..1", true},
+		{"1.2.3-alpha.", true},
+		{"1.2.3+meta..1", true},
 	}
 
 	for _, tc := range tests {
@@ -99,6 +109,122 @@ func TestNewVersion(t *testing.T) {
 	}
 }
 
+func TestSegmentOverflow(t *testing.T) {
+	tests := []string{
+		"18446744073709551616.0.0",
+		"1.18446744073709551616.0",
+		"1.0.18446744073709551616",
+		"99999999999999999999.0.0",
+	}
+
+	for _, tc := range tests {
+		if _, err := StrictNewVersion(tc); err != ErrSegmentOverflow {
+			t.Errorf("Expected ErrSegmentOverflow from StrictNewVersion for %s, got %v", tc, err)
+		}
+		if _, err := NewVersion(tc); err != ErrSegmentOverflow {
+			t.Errorf("Expected ErrSegmentOverflow from NewVersion for %s, got %v", tc, err)
+		}
+	}
+
+	v, err := StrictNewVersion("18446744073709551615.0.0")
+	if err != nil {
+		t.Fatalf("Unexpected error parsing the largest major version: %s", err)
+	}
+	if v.Major() != 18446744073709551615 {
+		t.Errorf("Expected major version 18446744073709551615, got %d", v.Major())
+	}
+}
+
+func TestVersionLimits(t *testing.T) {
+	defer func(l, i int) {
+		MaxVersionLength = l
+		MaxPrereleaseIdentifiers = i
+	}(MaxVersionLength, MaxPrereleaseIdentifiers)
+	MaxVersionLength = 256
+	MaxPrereleaseIdentifiers = 64
+
+	long := "1.2.3-" + strings.Repeat("a", MaxVersionLength)
+	if _, err := NewVersion(long); err != ErrVersionTooLong {
+		t.Errorf("Expected ErrVersionTooLong from NewVersion, got %v", err)
+	}
+	if _, err := StrictNewVersion(long); err != ErrVersionTooLong {
+		t.Errorf("Expected ErrVersionTooLong from StrictNewVersion, got %v", err)
+	}
+
+	many := "1.2.3-" + strings.TrimSuffix(strings.Repeat("a.", MaxPrereleaseIdentifiers+1), ".")
+	if _, err := NewVersion(many); err != ErrTooManyIdentifiers {
+		t.Errorf("Expected ErrTooManyIdentifiers from NewVersion, got %v", err)
+	}
+	if _, err := StrictNewVersion(many); err != ErrTooManyIdentifiers {
+		t.Errorf("Expected ErrTooManyIdentifiers from StrictNewVersion, got %v", err)
+	}
+
+	enough := "1.2.3-" + strings.TrimSuffix(strings.Repeat("a.", MaxPrereleaseIdentifiers), ".")
+	if _, err := StrictNewVersion(enough); err != nil {
+		t.Errorf("Unexpected error for a prerelease at the identifier limit: %s", err)
+	}
+
+	MaxVersionLength = 0
+	MaxPrereleaseIdentifiers = 0
+
+	if _, err := StrictNewVersion(long); err != nil {
+		t.Errorf("Unexpected error with the length limit disabled: %s", err)
+	}
+	if _, err := StrictNewVersion(many); err != nil {
+		t.Errorf("Unexpected error with the identifier limit disabled: %s", err)
+	}
+}
+
+func TestNonASCII(t *testing.T) {
+	tests := []struct {
+		version  string
+		expected string
+	}{
+		{"\uFF11.\uFF12.\uFF13", "1.2.3"},
+		{"1\uFF0E2\uFF0E3", "1.2.3"},
+		{"1.2.3\u2010beta.1", "1.2.3-beta.1"},
+		{"1.2.3\u2013rc\uFF0B\uFF42uild", "1.2.3-rc+build"},
+		{"1.2.3\u2212alpha", "1.2.3-alpha"},
+		{"\uFEFF1.2.3", "1.2.3"},
+		{"1.2\u200B.3", "1.2.3"},
+		{"\uFF56\uFF11.\uFF10", "1.0.0"},
+	}
+
+	for _, tc := range tests {
+		if _, err := StrictNewVersion(tc.version); err != ErrNonASCII {
+			t.Errorf("Expected ErrNonASCII from StrictNewVersion for %q, got %v", tc.version, err)
+		}
+
+		if _, err := NewVersion(tc.version); err != ErrNonASCII {
+			t.Errorf("Expected ErrNonASCII from NewVersion for %q, got %v", tc.version, err)
+		}
+
+		v, c, err := ParseLenient(tc.version)
+		if err != nil {
+			t.Errorf("Error parsing version %q: %s", tc.version, err)
+			continue
+		}
+		if c&CoercedCharacters == 0 {
+			t.Errorf("Expected CoercedCharacters for %q, got %s", tc.version, c)
+		}
+		if v.String() != tc.expected {
+			t.Errorf("Expected %q to be normalized to %q, got %q", tc.version, tc.expected, v.String())
+		}
+		if v.Original() != tc.version {
+			t.Errorf("Expected original %q, got %q", tc.version, v.Original())
+		}
+	}
+
+	for _, tc := range []string{"1.2.3-\u03B2eta", "\u0661.2.3", "1.2.3-foo\u2603"} {
+		if _, err := NewVersion(tc); err != ErrNonASCII {
+			t.Errorf("Expected ErrNonASCII from NewVersion for %q, got %v", tc, err)
+		}
+		if _, _, err := ParseLenient(tc); err != ErrNonASCII {
+			t.Errorf("Expected ErrNonASCII from ParseLenient for %q, got %v", tc, err)
+		}
+	}
+}
+
 func TestOriginal(t *testing.T) {
 	tests := []string{
 		"1.2.3",
@@ -153,6 +279,49 @@ func TestParts(t *testing.T) {
 	if v.Metadata() != "build.123" {
 		t.Error("Metadata() returning wrong value")
 	}
+	if !reflect.DeepEqual(v.PrereleaseIdentifiers(), []string{"beta", "1"}) {
+		t.Error("PrereleaseIdentifiers() returning wrong value")
+	}
+	if !reflect.DeepEqual(v.MetadataIdentifiers(), []string{"build", "123"}) {
+		t.Error("MetadataIdentifiers() returning wrong value")
+	}
+
+	v = MustParse("1.2.3")
+	if v.PrereleaseIdentifiers() != nil || v.MetadataIdentifiers() != nil {
+		t.Error("Expected no identifiers for 1.2.3")
+	}
+}
+
+func TestFromParts(t *testing.T) {
+	tests := []struct {
+		major, minor, patch uint64
+		pre, metadata       []string
+		expected            string
+		err                 error
+	}{
+		{1, 2, 3, nil, nil, "1.2.3", nil},
+		{1, 2, 3, []string{"rc", "1"}, []string{"build", "007"}, "1.2.3-rc.1+build.007", nil},
+		{0, 0, 0, []string{}, []string{}, "0.0.0", nil},
+		{1, 2, 3, []string{""}, nil, "", ErrInvalidPrerelease},
+		{1, 2, 3, []string{"rc", "01"}, nil, "", ErrSegmentStartsZero},
+		{1, 2, 3, []string{"rc.1"}, nil, "", ErrInvalidPrerelease},
+		{1, 2, 3, nil, []string{"a.b"}, "", ErrInvalidMetadata},
+		{1, 2, 3, nil, []string{"a_b"}, "", ErrInvalidMetadata},
+	}
+
+	for _, tc := range tests {
+		v, err := FromParts(tc.major, tc.minor, tc.patch, tc.pre, tc.metadata)
+		if err != tc.err {
+			t.Errorf("Expected error %v for %v %v, got %v", tc.err, tc.pre, tc.metadata, err)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		if v.String() != tc.expected || v.Original() != tc.expected {
+			t.Errorf("Expected %q, got %q (original %q)", tc.expected, v, v.Original())
+		}
+	}
 }
 
 func TestCoerceString(t *testing.T) {
@@ -216,6 +385,10 @@ func TestCompare(t *testing.T) {
 		{"1.0.0-beta.4", "1.0.0-beta.-2", -1},
 		{"1.0.0-beta.-2", "1.0.0-beta.-3", -1},
 		{"1.0.0-beta.-3", "1.0.0-beta.5", 1},
+		{"1.0.0-100000000000000000000", "1.0.0-99999999999999999999", 1},
+		{"1.0.0-99999999999999999999", "1.0.0-99999999999999999999", 0},
+		{"1.0.0-99999999999999999999", "1.0.0-alpha", -1},
+		{"1.0.0-99999999999999999999", "1.0.0-5", 1},
 	}
 
 	for _, tc := range tests {
@@ -240,6 +413,91 @@ func TestCompare(t *testing.T) {
 	}
 }
 
+func TestCompareTotal(t *testing.T) {
+	tests := []struct {
+		v1       string
+		v2       string
+		expected int
+	}{
+		{"1.2.3", "1.5.1", -1},
+		{"1.2.3+foo", "1.5.1", -1},
+		{"4.2-beta+zzz", "4.2+aaa", -1},
+		{"1.2.3", "1.2.3", 0},
+		{"1.2.3+foo", "1.2.3+foo", 0},
+		{"1.2.3", "1.2.3+foo", -1},
+		{"1.2.3+foo", "1.2.3", 1},
+		{"1.2.3+bar", "1.2.3+baz", -1},
+		{"1.2.3+build.9", "1.2.3+build.10", -1},
+		{"1.2.3+build.10", "1.2.3+build", 1},
+		{"1.2.3+01", "1.2.3+1", -1},
+		{"1.2.3+1", "1.2.3+01", 1},
+		{"1.2.3-beta+b", "1.2.3-beta+a", 1},
+	}
+
+	for _, tc := range tests {
+		v1, err := NewVersion(tc.v1)
+		if err != nil {
+			t.Errorf("Error parsing version: %s", err)
+		}
+
+		v2, err := NewVersion(tc.v2)
+		if err != nil {
+			t.Errorf("Error parsing version: %s", err)
+		}
+
+		a := v1.CompareTotal(v2)
+		e := tc.expected
+		if a != e {
+			t.Errorf(
+				"Total comparison of '%s' and '%s' failed. Expected '%d', got '%d'",
+				tc.v1, tc.v2, e, a,
+			)
+		}
+	}
+}
+
+func TestMetadataComparator(t *testing.T) {
+	// Order revisions such as 1ubuntu2 by the number after ubuntu, which the
+	// default ordering compares lexically.
+	MetadataComparator = func(a, b string) int {
+		ia := strings.Index(a, "ubuntu")
+		ib := strings.Index(b, "ubuntu")
+		if ia == -1 || ib == -1 || a[:ia] != b[:ib] {
+			return 0
+		}
+		return compareNumeric(a[ia+6:], b[ib+6:])
+	}
+	defer func() { MetadataComparator = nil }()
+
+	tests := []struct {
+		v1       string
+		v2       string
+		expected int
+	}{
+		{"1.2.3+1ubuntu2", "1.2.3+1ubuntu10", -1},
+		{"1.2.3+1ubuntu10", "1.2.3+1ubuntu2", 1},
+		{"1.2.3+1ubuntu2", "1.2.3+1ubuntu2", 0},
+		{"1.2.4+1ubuntu2", "1.2.3+1ubuntu10", 1},
+		{"1.2.3+2ubuntu1", "1.2.3+1ubuntu10", 1},
+		{"1.2.3", "1.2.3+1ubuntu1", -1},
+	}
+
+	for _, tc := range tests {
+		v1 := MustParse(tc.v1)
+		v2 := MustParse(tc.v2)
+
+		if a := v1.CompareTotal(v2); a != tc.expected {
+			t.Errorf("Comparison of '%s' and '%s' failed. Expected '%d', got '%d'", tc.v1, tc.v2, tc.expected, a)
+		}
+
+		// Precedence is unaffected by the comparator.
+		same := strings.SplitN(tc.v1, "+", 2)[0] == strings.SplitN(tc.v2, "+", 2)[0]
+		if v1.Equal(v2) != same {
+			t.Errorf("Expected the precedence of '%s' and '%s' to ignore metadata", tc.v1, tc.v2)
+		}
+	}
+}
+
 func TestLessThan(t *testing.T) {
 	tests := []struct {
 		v1       string
@@ -345,6 +603,38 @@ func TestEqual(t *testing.T) {
 	}
 }
 
+func TestIdentical(t *testing.T) {
+	tests := []struct {
+		v1       string
+		v2       string
+		expected bool
+	}{
+		{"1.2.3", "1.2.3", true},
+		{"v1.2.3", "1.2.3", true},
+		{"1.2.3", "1.2.4", false},
+		{"1.0.0+linux", "1.0.0+linux", true},
+		{"1.0.0+linux", "1.0.0+darwin", false},
+		{"1.0.0+linux", "1.0.0", false},
+		{"3.2-beta+foo", "3.2-beta+foo", true},
+		{"3.2-beta+foo", "3.2-alpha+foo", false},
+	}
+
+	for _, tc := range tests {
+		v1 := MustParse(tc.v1)
+		v2 := MustParse(tc.v2)
+
+		if a := v1.Identical(v2); a != tc.expected {
+			t.Errorf(
+				"Identical of '%s' and '%s' failed. Expected '%t', got '%t'",
+				tc.v1, tc.v2, tc.expected, a,
+			)
+		}
+		if v1.Identical(v2) && !v1.Equal(v2) {
+			t.Errorf("Expected identical '%s' and '%s' to be equal", tc.v1, tc.v2)
+		}
+	}
+}
+
 func TestInc(t *testing.T) {
 	tests := []struct {
 		v1               string
@@ -402,6 +692,42 @@ func TestInc(t *testing.T) {
 	}
 }
 
+func TestIncPrerelease(t *testing.T) {
+	tests := []struct {
+		v1               string
+		expected         string
+		expectedOriginal string
+		expectedErr      error
+	}{
+		{"1.2.3-rc.1", "1.2.3-rc.2", "1.2.3-rc.2", nil},
+		{"v1.2.3-rc.9+meta", "1.2.3-rc.10", "v1.2.3-rc.10", nil},
+		{"1.2.3-rc", "1.2.3-rc.1", "1.2.3-rc.1", nil},
+		{"1.2.3-0", "1.2.3-1", "1.2.3-1", nil},
+		{"1.2.3-1.beta", "1.2.3-1.beta.1", "1.2.3-1.beta.1", nil},
+		{"1.2.3-rc.18446744073709551615", "1.2.3-rc.18446744073709551615", "1.2.3-rc.18446744073709551615", ErrSegmentOverflow},
+		{"1.2.3", "1.2.3", "1.2.3", ErrNoPrerelease},
+	}
+
+	for _, tc := range tests {
+		v1, err := NewVersion(tc.v1)
+		if err != nil {
+			t.Errorf("Error parsing version: %s", err)
+			continue
+		}
+
+		v2, err := v1.IncPrerelease()
+		if err != tc.expectedErr {
+			t.Errorf("Expected to get err=%v for %q, but got err=%v", tc.expectedErr, tc.v1, err)
+		}
+		if a := v2.String(); a != tc.expected {
+			t.Errorf("Expected %q to increment to %q, but got %q", tc.v1, tc.expected, a)
+		}
+		if err == nil && v2.Original() != tc.expectedOriginal {
+			t.Errorf("Expected original %q, but got %q", tc.expectedOriginal, v2.Original())
+		}
+	}
+}
+
 func TestSetPrerelease(t *testing.T) {
 	tests := []struct {
 		v1                 string
@@ -542,6 +868,100 @@ func TestJsonUnmarshal(t *testing.T) {
 	}
 }
 
+func TestTextMarshal(t *testing.T) {
+	sVer := "1.2.3-beta.1+build.01"
+	x, err := StrictNewVersion(sVer)
+	if err != nil {
+		t.Errorf("Error creating version: %s", err)
+	}
+	out, err := x.MarshalText()
+	if err != nil {
+		t.Errorf("Error marshaling version: %s", err)
+	}
+	if string(out) != sVer {
+		t.Errorf("Error marshaling unexpected marshaled content: got=%q want=%q", string(out), sVer)
+	}
+
+	ver := &Version{}
+	if err := ver.UnmarshalText([]byte("v" + sVer)); err != nil {
+		t.Errorf("Error unmarshaling version: %s", err)
+	}
+	if ver.String() != sVer || ver.Original() != "v"+sVer {
+		t.Errorf("Error unmarshaling unexpected object content: got=%q want=%q", ver.String(), sVer)
+	}
+
+	if err := ver.UnmarshalText([]byte("foo")); err == nil {
+		t.Error("Expected error unmarshaling an invalid version")
+	}
+}
+
+// yamlString returns a function that decodes the YAML string s into a
+// *string the way go-yaml calls UnmarshalYAML.
+func yamlString(s string) func(interface{}) error {
+	return func(out interface{}) error {
+		p, ok := out.(*string)
+		if !ok {
+			return fmt.Errorf("cannot decode a string into %T", out)
+		}
+		*p = s
+		return nil
+	}
+}
+
+func TestYAMLMarshal(t *testing.T) {
+	sVer := "1.2.3-beta.1+build.01"
+	ver := &Version{}
+	if err := ver.UnmarshalYAML(yamlString("v" + sVer)); err != nil {
+		t.Errorf("Error unmarshaling version: %s", err)
+	}
+	if ver.String() != sVer || ver.Original() != "v"+sVer {
+		t.Errorf("Error unmarshaling unexpected object content: got=%q want=%q", ver.String(), sVer)
+	}
+
+	out, err := ver.MarshalYAML()
+	if err != nil {
+		t.Errorf("Error marshaling version: %s", err)
+	}
+	if out != sVer {
+		t.Errorf("Error marshaling unexpected marshaled content: got=%v want=%q", out, sVer)
+	}
+
+	err = ver.UnmarshalYAML(yamlString("foo"))
+	if err == nil || err.Error() != `invalid version "foo": Invalid Semantic Version` {
+		t.Errorf("Expected error naming the invalid version, got %v", err)
+	}
+}
+
+func TestEncodingRoundTrip(t *testing.T) {
+	type config struct {
+		Version  Version            `json:"version"`
+		Optional *Version           `json:"optional"`
+		Pinned   map[string]Version `json:"pinned"`
+	}
+
+	in := config{
+		Version:  *MustParse("1.2.3-rc.1+build.5"),
+		Optional: MustParse("2.0.0-0.3.7+exp.sha.5114f85"),
+		Pinned:   map[string]Version{"foo": *MustParse("1.0.0+20130313144700")},
+	}
+
+	b, err := json.Marshal(in)
+	if err != nil {
+		t.Fatalf("Error marshaling: %s", err)
+	}
+
+	var out config
+	if err := json.Unmarshal(b, &out); err != nil {
+		t.Fatalf("Error unmarshaling %s: %s", b, err)
+	}
+
+	if out.Version.String() != in.Version.String() ||
+		out.Optional.String() != in.Optional.String() ||
+		out.Pinned["foo"].String() != in.Pinned["foo"].String() {
+		t.Errorf("Expected %s to round trip", b)
+	}
+}
+
 func TestSQLScanner(t *testing.T) {
 	sVer := "1.1.1"
 	x, err := StrictNewVersion(sVer)
@@ -561,6 +981,60 @@ func TestSQLScanner(t *testing.T) {
 	}
 }
 
+func TestSQLScan(t *testing.T) {
+	tests := []struct {
+		value    interface{}
+		expected string
+		err      bool
+	}{
+		{"1.2.3", "1.2.3", false},
+		{[]byte("v1.2.3-beta+b1"), "1.2.3-beta+b1", false},
+		{"foo", "", true},
+		{nil, "", true},
+		{42, "", true},
+	}
+
+	for _, tc := range tests {
+		var v Version
+		err := v.Scan(tc.value)
+		if tc.err {
+			if err == nil {
+				t.Errorf("Expected error scanning %#v", tc.value)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Error scanning %#v: %s", tc.value, err)
+			continue
+		}
+		if v.String() != tc.expected {
+			t.Errorf("Expected %#v to scan as %q, got %q", tc.value, tc.expected, v.String())
+		}
+	}
+}
+
+func TestVersionFlag(t *testing.T) {
+	var v Version
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(ioutil.Discard)
+	fs.Var(&v, "min-version", "minimum version")
+
+	if err := fs.Parse([]string{"--min-version", "v1.4.0"}); err != nil {
+		t.Fatalf("Error parsing flags: %s", err)
+	}
+	if v.String() != "1.4.0" || v.Original() != "v1.4.0" {
+		t.Errorf("Expected flag to be set to v1.4.0, got %q", v.Original())
+	}
+	if v.Type() != "version" {
+		t.Errorf("Expected type version, got %q", v.Type())
+	}
+
+	err := fs.Parse([]string{"--min-version", "1.4.x"})
+	if err == nil || !strings.Contains(err.Error(), ErrInvalidSemVer.Error()) {
+		t.Errorf("Expected the parse error in the flag error, got %v", err)
+	}
+}
+
 func TestDriverValuer(t *testing.T) {
 	sVer := "1.1.1"
 	x, err := StrictNewVersion(sVer)
@@ -587,6 +1061,8 @@ func TestValidatePrerelease(t *testing.T) {
 		{"alpha.01", ErrSegmentStartsZero},
 		{"foo☃︎", ErrInvalidPrerelease},
 		{"alpha.0-1", nil},
+		{"alpha..1", ErrInvalidPrerelease},
+		{"alpha.", ErrInvalidPrerelease},
 	}
 
 	for _, tc := range tests {
@@ -607,6 +1083,7 @@ func TestValidateMetadata(t *testing.T) {
 		{"foo☃︎", ErrInvalidMetadata},
 		{"alpha.0-1", nil},
 		{"al-pha.1Phe70CgWe050H9K1mJwRUqTNQXZRERwLOEg37wpXUb4JgzgaD5YkL52ABnoyiE", nil},
+		{"alpha..1", ErrInvalidMetadata},
 	}
 
 	for _, tc := range tests {
@@ -0,0 +1,145 @@
+package semver
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Violation is a way a string deviates from the SemVer 2.0.0 specification.
+// It is returned by Validate.
+type Violation struct {
+	// Start and End are the byte offsets of the offending part of the
+	// string, such that s[Start:End] is that part. They are equal when
+	// something is missing, such as an identifier between two dots.
+	Start, End int
+
+	// Err is the error StrictNewVersion returns for this kind of violation,
+	// such as ErrSegmentStartsZero.
+	Err error
+
+	// Message describes the violation.
+	Message string
+}
+
+// String returns the offset and description of the violation.
+func (v Violation) String() string {
+	return fmt.Sprintf("%d: %s", v.Start, v.Message)
+}
+
+// Validate reports every way s deviates from the SemVer 2.0.0 specification,
+// in the order they appear, where StrictNewVersion returns only the first.
+// It is meant for editors and linters showing all problems at once. s is a
+// valid version when no violations are returned.
+func Validate(s string) []Violation {
+	var vs []Violation
+	add := func(start, end int, err error, format string, args ...interface{}) {
+		vs = append(vs, Violation{Start: start, End: end, Err: err, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if s == "" {
+		add(0, 0, ErrEmptyString, "version is empty")
+		return vs
+	}
+
+	core, pre, meta := len(s), -1, -1
+	if i := strings.IndexByte(s, '+'); i != -1 {
+		meta, core = i, i
+	}
+	if i := strings.IndexByte(s[:core], '-'); i != -1 {
+		pre, core = i, i
+	}
+
+	// The version core is three dot separated numbers.
+	parts := 0
+	validateIdentifiers(s, 0, core, func(start, end int) {
+		parts++
+		if parts > 3 {
+			add(start, end, ErrInvalidCharacters, "version has more than three numbers, found %q", s[start:end])
+			return
+		}
+
+		name := coreNames[parts-1]
+		if parts == 1 && start < end && (s[start] == 'v' || s[start] == 'V') {
+			add(start, start+1, ErrInvalidCharacters, "%q prefix is not part of the version", s[start:start+1])
+			start++
+		}
+
+		p := s[start:end]
+		switch {
+		case p == "":
+			add(start, end, ErrInvalidSemVer, "%s version is empty", name)
+		case !containsOnly(p, num):
+			i := strings.IndexFunc(p, func(r rune) bool { return r < '0' || r > '9' })
+			add(start+i, end, ErrInvalidCharacters, "%s version %q contains non-digits", name, p)
+		case len(p) > 1 && p[0] == '0':
+			add(start, end, ErrSegmentStartsZero, "%s version %q has a leading zero", name, p)
+		default:
+			if _, err := strconv.ParseUint(p, 10, 64); err != nil {
+				add(start, end, ErrSegmentOverflow, "%s version %q is too large", name, p)
+			}
+		}
+	})
+	if parts < 3 {
+		add(core, core, ErrInvalidSemVer, "version is missing its %s version", coreNames[parts])
+	}
+
+	if pre != -1 {
+		end := len(s)
+		if meta != -1 {
+			end = meta
+		}
+		validateIdentifiers(s, pre+1, end, func(start, end int) {
+			id := s[start:end]
+			switch {
+			case id == "":
+				add(start, end, ErrInvalidPrerelease, "prerelease has an empty identifier")
+			case !containsOnly(id, allowed):
+				add(start+invalidIndex(id), end, ErrInvalidPrerelease, "prerelease identifier %q contains invalid characters", id)
+			case containsOnly(id, num) && len(id) > 1 && id[0] == '0':
+				add(start, end, ErrSegmentStartsZero, "numeric prerelease identifier %q has a leading zero", id)
+			}
+		})
+	}
+
+	if meta != -1 {
+		validateIdentifiers(s, meta+1, len(s), func(start, end int) {
+			id := s[start:end]
+			switch {
+			case id == "":
+				add(start, end, ErrInvalidMetadata, "build metadata has an empty identifier")
+			case !containsOnly(id, allowed):
+				add(start+invalidIndex(id), end, ErrInvalidMetadata, "build metadata identifier %q contains invalid characters", id)
+			}
+		})
+	}
+
+	return vs
+}
+
+var coreNames = [...]string{"major", "minor", "patch"}
+
+// validateIdentifiers calls fn with the offsets of each dot separated part of
+// s[start:end].
+func validateIdentifiers(s string, start, end int, fn func(start, end int)) {
+	for {
+		i := strings.IndexByte(s[start:end], '.')
+		if i == -1 {
+			fn(start, end)
+			return
+		}
+		fn(start, start+i)
+		start += i + 1
+	}
+}
+
+// invalidIndex returns the offset of the first character of id that is not
+// allowed in an identifier.
+func invalidIndex(id string) int {
+	for i := 0; i < len(id); i++ {
+		if !strings.ContainsRune(allowed, rune(id[i])) {
+			return i
+		}
+	}
+	return 0
+}
@@ -0,0 +1,97 @@
+package semver
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		version    string
+		violations []string
+	}{
+		{"1.2.3", nil},
+		{"1.2.3-beta.1+build.0-1", nil},
+		{"", []string{"0: version is empty"}},
+		{"01.002.3", []string{
+			`0: major version "01" has a leading zero`,
+			`3: minor version "002" has a leading zero`,
+		}},
+		{"v1.2", []string{
+			`0: "v" prefix is not part of the version`,
+			"4: version is missing its patch version",
+		}},
+		{"1.2.3.4", []string{`6: version has more than three numbers, found "4"`}},
+		{"1..3", []string{"2: minor version is empty"}},
+		{"1.2x.3", []string{`3: minor version "2x" contains non-digits`}},
+		{"1.2.99999999999999999999", []string{`4: patch version "99999999999999999999" is too large`}},
+		{"1.2.3-beta..01.a_b", []string{
+			"11: prerelease has an empty identifier",
+			`12: numeric prerelease identifier "01" has a leading zero`,
+			`16: prerelease identifier "a_b" contains invalid characters`,
+		}},
+		{"1.2.3-", []string{"6: prerelease has an empty identifier"}},
+		{"1.2.3+build.+x+y", []string{
+			`12: build metadata identifier "+x+y" contains invalid characters`,
+		}},
+		{"1.2.3-β", []string{`6: prerelease identifier "β" contains invalid characters`}},
+		{"x.01.3-01+", []string{
+			`0: major version "x" contains non-digits`,
+			`2: minor version "01" has a leading zero`,
+			`7: numeric prerelease identifier "01" has a leading zero`,
+			"10: build metadata has an empty identifier",
+		}},
+	}
+
+	for _, tc := range tests {
+		var got []string
+		for _, v := range Validate(tc.version) {
+			got = append(got, v.String())
+			if v.Start < 0 || v.End > len(tc.version) || v.Start > v.End {
+				t.Errorf("Violation %q of %q has invalid offsets %d:%d", v, tc.version, v.Start, v.End)
+			}
+			if v.Err == nil {
+				t.Errorf("Violation %q of %q has no error", v, tc.version)
+			}
+		}
+
+		if strings.Join(got, "\n") != strings.Join(tc.violations, "\n") {
+			t.Errorf("Expected violations of %q to be %q, got %q", tc.version, tc.violations, got)
+		}
+
+		_, err := StrictNewVersion(tc.version)
+		if (err == nil) != (len(got) == 0) {
+			t.Errorf("Expected Validate and StrictNewVersion to agree on %q, got %q and %v", tc.version, got, err)
+		}
+	}
+}
+
+func TestValidateErr(t *testing.T) {
+	// Each of these has a single violation, which must carry the error
+	// StrictNewVersion returns.
+	tests := []string{
+		"01.2.3",
+		"1.2.3-01",
+		"1.2.3-beta.01",
+		"1.2.3-",
+		"1.2.3-a..b",
+		"1.2.3+",
+		"1.2.3-a_b",
+		"1.2.3+a_b",
+		"1.2.3.4",
+		"v1.2.3",
+		"1.2",
+		"99999999999999999999.0.0",
+	}
+
+	for _, tc := range tests {
+		vs := Validate(tc)
+		if len(vs) != 1 {
+			t.Errorf("Expected one violation of %q, got %q", tc, vs)
+			continue
+		}
+		if _, err := StrictNewVersion(tc); vs[0].Err != err {
+			t.Errorf("Expected the violation of %q to have error %v, got %v", tc, err, vs[0].Err)
+		}
+	}
+}